@@ -0,0 +1,212 @@
+// Package alert evaluates alert rules, such as "cpu > 90% for 30s" or
+// "restarted", on the stats of containers and on Docker events.
+package alert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is an alert rule: either a condition on a metric of a container
+// that has to hold for a while, or a container event.
+type Rule struct {
+	Text string // the rule as written
+	// Metric is cpu, mem or pids, empty for event rules.
+	Metric    string
+	Op        string // >, >=, < or <=
+	Threshold float64
+	For       time.Duration
+	// Event is restarted, unhealthy, oom or died, empty for metric rules.
+	Event string
+}
+
+// eventActions maps the events of event rules to the Docker container
+// event actions they fire on.
+var eventActions = map[string]string{
+	"restarted": "restart",
+	"unhealthy": "health_status: unhealthy",
+	"oom":       "oom",
+	"died":      "die",
+}
+
+var metricRule = regexp.MustCompile(`^(cpu|mem|memory|pids)\s*(>=|<=|>|<)\s*([0-9]+(?:\.[0-9]+)?)\s*(%?)(?:\s+of\s+(?:the\s+)?limit)?(?:\s+for\s+(\S+))?$`)
+
+// Parse parses a rule: "cpu > 90% for 30s", "mem > 80% of limit",
+// "pids > 500", or one of the events "restarted", "unhealthy", "oom" and
+// "died", which can be written as "container restarted" or "health became
+// unhealthy".
+func Parse(s string) (Rule, error) {
+	text := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	event := strings.TrimPrefix(strings.TrimPrefix(text, "container "), "health became ")
+	if _, ok := eventActions[event]; ok {
+		return Rule{Text: text, Event: event}, nil
+	}
+	m := metricRule.FindStringSubmatch(text)
+	if m == nil {
+		return Rule{}, fmt.Errorf("invalid alert rule %q", s)
+	}
+	r := Rule{Text: text, Metric: m[1], Op: m[2]}
+	if r.Metric == "memory" {
+		r.Metric = "mem"
+	}
+	if r.Metric == "pids" && m[4] != "" {
+		return Rule{}, fmt.Errorf("invalid alert rule %q: pids are not a percentage", s)
+	}
+	r.Threshold, _ = strconv.ParseFloat(m[3], 64)
+	if m[5] != "" {
+		d, err := time.ParseDuration(m[5])
+		if err != nil || d < 0 {
+			return Rule{}, fmt.Errorf("invalid duration in alert rule %q", s)
+		}
+		r.For = d
+	}
+	return r, nil
+}
+
+// holds returns whether a metric rule holds for the given value.
+func (r Rule) holds(v float64) bool {
+	switch r.Op {
+	case ">":
+		return v > r.Threshold
+	case ">=":
+		return v >= r.Threshold
+	case "<":
+		return v < r.Threshold
+	case "<=":
+		return v <= r.Threshold
+	}
+	return false
+}
+
+// Values are the metrics of a container rules are evaluated on.
+type Values struct {
+	CPU    float64 // percentage of CPU used
+	Memory float64 // percentage of the memory limit used
+	Pids   float64
+}
+
+func (v Values) get(metric string) float64 {
+	switch metric {
+	case "cpu":
+		return v.CPU
+	case "mem":
+		return v.Memory
+	}
+	return v.Pids
+}
+
+// Alert is a rule that fired for a container.
+type Alert struct {
+	Rule      Rule
+	Container string // ID
+	Name      string
+	At        time.Time
+	Value     float64 // of the metric, for metric rules
+}
+
+func (a Alert) String() string {
+	if a.Rule.Metric == "" {
+		return fmt.Sprintf("%s: %s", a.Name, a.Rule.Text)
+	}
+	unit := "%"
+	if a.Rule.Metric == "pids" {
+		unit = ""
+	}
+	return fmt.Sprintf("%s: %s (%.1f%s)", a.Name, a.Rule.Text, a.Value, unit)
+}
+
+type key struct {
+	rule int
+	id   string
+}
+
+// Evaluator evaluates rules on the stats and events of containers. A
+// metric rule fires once when it has held for its duration, and again
+// only after it stopped holding. It is not safe for concurrent use.
+type Evaluator struct {
+	rules  []Rule
+	since  map[key]time.Time // when a metric rule started to hold
+	firing map[key]bool
+}
+
+// NewEvaluator creates an evaluator of the given rules.
+func NewEvaluator(rules []Rule) *Evaluator {
+	return &Evaluator{
+		rules:  rules,
+		since:  make(map[key]time.Time),
+		firing: make(map[key]bool),
+	}
+}
+
+// Rules returns the rules evaluated.
+func (e *Evaluator) Rules() []Rule {
+	return e.rules
+}
+
+// Observe evaluates the metric rules on the values of a container at the
+// given time, returning the alerts fired.
+func (e *Evaluator) Observe(id, name string, v Values, at time.Time) []Alert {
+	var fired []Alert
+	for i, r := range e.rules {
+		if r.Metric == "" {
+			continue
+		}
+		k := key{rule: i, id: id}
+		value := v.get(r.Metric)
+		if !r.holds(value) {
+			delete(e.since, k)
+			delete(e.firing, k)
+			continue
+		}
+		since, ok := e.since[k]
+		if !ok {
+			since = at
+			e.since[k] = at
+		}
+		if e.firing[k] || at.Sub(since) < r.For {
+			continue
+		}
+		e.firing[k] = true
+		fired = append(fired, Alert{Rule: r, Container: id, Name: name, At: at, Value: value})
+	}
+	return fired
+}
+
+// Event evaluates the event rules on a container event, returning the
+// alerts fired.
+func (e *Evaluator) Event(id, name, action string, at time.Time) []Alert {
+	var fired []Alert
+	for _, r := range e.rules {
+		if r.Event != "" && eventActions[r.Event] == action {
+			fired = append(fired, Alert{Rule: r, Container: id, Name: name, At: at})
+		}
+	}
+	return fired
+}
+
+// Firing returns whether a metric rule is firing for a container.
+func (e *Evaluator) Firing(id string) bool {
+	for k := range e.firing {
+		if k.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Forget drops the state of the rules of a container no longer observed.
+func (e *Evaluator) Forget(id string) {
+	for k := range e.since {
+		if k.id == id {
+			delete(e.since, k)
+		}
+	}
+	for k := range e.firing {
+		if k.id == id {
+			delete(e.firing, k)
+		}
+	}
+}
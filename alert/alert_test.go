@@ -0,0 +1,84 @@
+package alert
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Rule
+	}{
+		{"cpu > 90% for 30s", Rule{Text: "cpu > 90% for 30s", Metric: "cpu", Op: ">", Threshold: 90, For: 30 * time.Second}},
+		{"Mem>80% of limit", Rule{Text: "mem>80% of limit", Metric: "mem", Op: ">", Threshold: 80}},
+		{"memory >= 75.5%", Rule{Text: "memory >= 75.5%", Metric: "mem", Op: ">=", Threshold: 75.5}},
+		{"pids > 500 for 1m", Rule{Text: "pids > 500 for 1m", Metric: "pids", Op: ">", Threshold: 500, For: time.Minute}},
+		{"container restarted", Rule{Text: "container restarted", Event: "restarted"}},
+		{"health became unhealthy", Rule{Text: "health became unhealthy", Event: "unhealthy"}},
+		{"oom", Rule{Text: "oom", Event: "oom"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Fatalf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{"", "disk > 90%", "cpu 90%", "cpu > 90% for ever", "pids > 5%", "container paused"} {
+		if _, err := Parse(in); err == nil {
+			t.Fatalf("expected Parse(%q) to fail", in)
+		}
+	}
+}
+
+func TestEvaluator_MetricRulesFireOnceAfterTheirDuration(t *testing.T) {
+	cpu, _ := Parse("cpu > 90% for 30s")
+	mem, _ := Parse("mem > 80%")
+	e := NewEvaluator([]Rule{cpu, mem})
+	start := time.Unix(1000, 0)
+
+	if fired := e.Observe("a", "web", Values{CPU: 95, Memory: 10}, start); len(fired) != 0 {
+		t.Fatalf("expected nothing to fire before 30s, got %v", fired)
+	}
+	fired := e.Observe("a", "web", Values{CPU: 96, Memory: 85}, start.Add(30*time.Second))
+	if len(fired) != 2 || fired[0].Rule != cpu || fired[1].Rule != mem {
+		t.Fatalf("expected both rules to fire, got %v", fired)
+	}
+	if got := fired[0].String(); got != "web: cpu > 90% for 30s (96.0%)" {
+		t.Fatalf("unexpected alert text %q", got)
+	}
+	if !e.Firing("a") || e.Firing("b") {
+		t.Fatal("expected only the container a to be firing")
+	}
+	if fired := e.Observe("a", "web", Values{CPU: 97, Memory: 90}, start.Add(40*time.Second)); len(fired) != 0 {
+		t.Fatalf("expected firing rules not to fire again, got %v", fired)
+	}
+
+	// Dropping below the threshold resets the rule.
+	e.Observe("a", "web", Values{CPU: 10, Memory: 10}, start.Add(50*time.Second))
+	if e.Firing("a") {
+		t.Fatal("expected the rules to stop firing")
+	}
+	if fired := e.Observe("a", "web", Values{CPU: 95}, start.Add(60*time.Second)); len(fired) != 0 {
+		t.Fatalf("expected the duration to start over, got %v", fired)
+	}
+}
+
+func TestEvaluator_EventRules(t *testing.T) {
+	restarted, _ := Parse("restarted")
+	unhealthy, _ := Parse("unhealthy")
+	e := NewEvaluator([]Rule{restarted, unhealthy})
+	at := time.Unix(1000, 0)
+	if fired := e.Event("a", "web", "restart", at); len(fired) != 1 || fired[0].String() != "web: restarted" {
+		t.Fatalf("expected the restart to fire, got %v", fired)
+	}
+	if fired := e.Event("a", "web", "health_status: unhealthy", at); len(fired) != 1 || fired[0].Rule != unhealthy {
+		t.Fatalf("expected the health change to fire, got %v", fired)
+	}
+	if fired := e.Event("a", "web", "start", at); len(fired) != 0 {
+		t.Fatalf("expected a start to fire nothing, got %v", fired)
+	}
+}
@@ -0,0 +1,127 @@
+package app
+
+// Alerts: the rules of the configuration file are evaluated on the stats
+// of the monitor and on container events. Alerts fired are told in the
+// message bar, optionally with the terminal bell or a desktop
+// notification, and listed in the alerts panel.
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moncho/dry/alert"
+	"github.com/moncho/dry/docker"
+)
+
+// alertLogSize is how many alerts the alerts panel lists.
+const alertLogSize = 200
+
+// parseAlertRules parses the alert rules of the configuration.
+func parseAlertRules(texts []string) ([]alert.Rule, error) {
+	rules := make([]alert.Rule, 0, len(texts))
+	for _, text := range texts {
+		r, err := alert.Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// observeAlerts evaluates the alert rules on the stats of a container.
+func (m *model) observeAlerts(cid string, s *docker.Stats) tea.Cmd {
+	if m.alerts == nil || s == nil {
+		return nil
+	}
+	return m.raiseAlerts(m.alerts.Observe(cid, strings.TrimPrefix(s.Name, "/"), alert.Values{
+		CPU:    s.CPUPercentage,
+		Memory: s.MemoryPercentage,
+		Pids:   float64(s.PidsCurrent),
+	}, time.Now()))
+}
+
+// eventAlerts evaluates the alert rules on a Docker event.
+func (m *model) eventAlerts(e events.Message) tea.Cmd {
+	if m.alerts == nil || e.Type != events.ContainerEventType {
+		return nil
+	}
+	return m.raiseAlerts(m.alerts.Event(e.Actor.ID, e.Actor.Attributes["name"], string(e.Action), time.Now()))
+}
+
+// raiseAlerts logs the alerts fired and tells about the last one.
+func (m *model) raiseAlerts(fired []alert.Alert) tea.Cmd {
+	if len(fired) == 0 {
+		return nil
+	}
+	m.alertLog = append(m.alertLog, fired...)
+	if extra := len(m.alertLog) - alertLogSize; extra > 0 {
+		m.alertLog = append([]alert.Alert(nil), m.alertLog[extra:]...)
+	}
+	text := "⚠ " + fired[len(fired)-1].String()
+	if len(fired) > 1 {
+		text += fmt.Sprintf(" (and %d more, a lists them)", len(fired)-1)
+	}
+	m.messageBar.SetMessage(text, 10*time.Second)
+	m.monitor.FlushTable()
+	var raw strings.Builder
+	if m.config.AlertBell {
+		raw.WriteByte(ansi.BEL)
+	}
+	if m.config.AlertNotify {
+		for _, a := range fired {
+			raw.WriteString(ansi.Notify("dry: " + a.String()))
+		}
+	}
+	if raw.Len() == 0 {
+		return nil
+	}
+	return tea.Raw(raw.String())
+}
+
+// alertsReport lists the alert rules, the containers they are firing for
+// and the alerts fired, newest first.
+func (m model) alertsReport() string {
+	var b strings.Builder
+	if m.alerts == nil || len(m.alerts.Rules()) == 0 {
+		b.WriteString("No alert rules: add them to the configuration file, as in\n\n")
+		b.WriteString("    alerts = [\"cpu > 90% for 30s\", \"mem > 80%\", \"restarted\", \"unhealthy\"]\n")
+		return b.String()
+	}
+	b.WriteString("Rules:\n")
+	for _, r := range m.alerts.Rules() {
+		fmt.Fprintf(&b, "  %s\n", r.Text)
+	}
+	var firing []string
+	seen := make(map[string]bool)
+	for _, a := range m.alertLog {
+		if !seen[a.Container] && m.alerts.Firing(a.Container) {
+			seen[a.Container] = true
+			firing = append(firing, a.Name)
+		}
+	}
+	if len(firing) > 0 {
+		fmt.Fprintf(&b, "\nFiring for: %s\n", strings.Join(firing, ", "))
+	}
+	b.WriteString("\nAlerts:\n")
+	if len(m.alertLog) == 0 {
+		b.WriteString("  none yet\n")
+	}
+	for i := len(m.alertLog) - 1; i >= 0; i-- {
+		a := m.alertLog[i]
+		fmt.Fprintf(&b, "  %s  %s\n", a.At.Format("2006-01-02 15:04:05"), a)
+	}
+	return b.String()
+}
+
+// showAlerts opens the alerts panel.
+func (m model) showAlerts() (tea.Model, tea.Cmd) {
+	content := m.alertsReport()
+	return m, func() tea.Msg {
+		return showLessMsg{content: content, title: fmt.Sprintf("Alerts (%d)", len(m.alertLog))}
+	}
+}
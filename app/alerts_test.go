@@ -0,0 +1,70 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+func TestModel_MonitorStatsRaiseAlerts(t *testing.T) {
+	m := NewModel(Config{Alerts: []string{"cpu > 50%"}})
+	m.width, m.height = 120, 40
+	m.ready = true
+	m.view = Monitor
+	m.monitor.SetSize(m.width, m.contentHeight())
+
+	ch := make(chan *docker.Stats)
+	stats := &docker.Stats{CID: "abc123", ID: "abc123", Name: "/web", CPUPercentage: 20}
+	result, _ := m.Update(appui.MonitorStatsMsg{CID: "abc123", Stats: stats, StatsCh: ch})
+	m = result.(model)
+	if msg := m.messageBar.Message(); msg != "" {
+		t.Fatalf("expected no alert below the threshold, got %q", msg)
+	}
+
+	hot := *stats
+	hot.CPUPercentage = 75
+	result, _ = m.Update(appui.MonitorStatsMsg{CID: "abc123", Stats: &hot, StatsCh: ch})
+	m = result.(model)
+	if msg := m.messageBar.Message(); msg != "⚠ web: cpu > 50% (75.0%)" {
+		t.Fatalf("expected the alert in the message bar, got %q", msg)
+	}
+	if view := m.monitor.View(); !strings.Contains(view, "⚠") {
+		t.Fatalf("expected the row of the container to be highlighted:\n%s", view)
+	}
+
+	result, cmd := m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+	m = result.(model)
+	if cmd == nil {
+		t.Fatal("expected a to open the alerts panel")
+	}
+	show, ok := cmd().(showLessMsg)
+	if !ok || !strings.Contains(show.content, "cpu > 50%") || !strings.Contains(show.content, "Firing for: web") {
+		t.Fatalf("unexpected alerts panel %+v", show)
+	}
+}
+
+func TestModel_DockerEventsRaiseAlerts(t *testing.T) {
+	m := NewModel(Config{Alerts: []string{"container restarted"}, AlertBell: true})
+	ch := make(chan events.Message)
+	m.eventsChan = ch
+	ev := events.Message{
+		Type:   events.ContainerEventType,
+		Action: events.ActionRestart,
+		Actor:  events.Actor{ID: "abc123", Attributes: map[string]string{"name": "web"}},
+	}
+	result, _ := m.Update(dockerEventMsg{event: ev, ch: ch})
+	m = result.(model)
+	if msg := m.messageBar.Message(); msg != "⚠ web: container restarted" {
+		t.Fatalf("expected the alert in the message bar, got %q", msg)
+	}
+	if len(m.alertLog) != 1 {
+		t.Fatalf("expected the alert to be logged, got %v", m.alertLog)
+	}
+	if cmd := m.raiseAlerts(m.alertLog); cmd == nil {
+		t.Fatal("expected the bell to be rung")
+	}
+}
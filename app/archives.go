@@ -0,0 +1,408 @@
+package app
+
+// Archives: containers committed to images, container filesystems exported
+// to tar files, and images saved to and loaded from tar archives, with the
+// progress of transfers shown in the message bar.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/docker/go-units"
+	"github.com/mitchellh/go-homedir"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+// commitFormTag tags the commit form.
+const commitFormTag = "commit"
+
+// commitFormFields are the fields of the commit form.
+func commitFormFields() []appui.FormField {
+	return []appui.FormField{
+		{Key: "reference", Label: "Image", Placeholder: "repository[:tag], untagged if empty"},
+		{Key: "message", Label: "Message", Placeholder: "commit message"},
+		{Key: "author", Label: "Author", Placeholder: "Jane Doe <jane@example.com>"},
+		{Key: "changes", Label: "Changes", Placeholder: `Dockerfile instructions separated by ";", such as ENV A=1; CMD ["sh"]`},
+		{Key: "pause", Label: "Pause while committing", Toggle: true, On: true},
+	}
+}
+
+// commitOptions reads the options of docker commit from the commit form.
+func commitOptions(v appui.FormValues) docker.CommitOptions {
+	return docker.CommitOptions{
+		Reference: v.String("reference"),
+		Comment:   v.String("message"),
+		Author:    v.String("author"),
+		Changes:   splitChanges(v.String("changes")),
+		NoPause:   !v.Bool("pause"),
+	}
+}
+
+// splitChanges splits Dockerfile instructions separated by ";", keeping
+// those in quotes or brackets, such as in CMD ["sh", "-c", "a; b"].
+func splitChanges(s string) []string {
+	var changes []string
+	var quote rune
+	depth, start, escaped := 0, 0, false
+	add := func(end int) {
+		if change := strings.TrimSpace(s[start:end]); change != "" {
+			changes = append(changes, change)
+		}
+	}
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']' && depth > 0:
+			depth--
+		case r == ';' && depth == 0:
+			add(i)
+			start = i + 1
+		}
+	}
+	add(len(s))
+	return changes
+}
+
+// containerCommittedMsg reports the commit of a container to an image.
+type containerCommittedMsg struct {
+	id        string
+	reference string
+	imageID   string
+	err       error
+}
+
+// transferMsg reports the start of a transfer from or to a tar archive,
+// with the function cancelling it, its progress and its end.
+type transferMsg struct {
+	label   string // what is transferred, such as "Saving nginx:latest"
+	cancel  context.CancelFunc
+	bytes   int64
+	done    bool
+	message string
+	err     error
+	ch      <-chan transferMsg
+	files   string // container whose files are read again once done
+}
+
+// showCommitForm opens the commit form for the given container.
+func (m model) showCommitForm(c *docker.Container) (model, tea.Cmd) {
+	if c == nil {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.form, cmd = appui.NewFormModel(
+		fmt.Sprintf("Commit %s to an image", paletteContainerLabel(c)),
+		commitFormTag, c.ID, commitFormFields())
+	m.form.SetSize(m.width, m.height)
+	m.overlay = overlayForm
+	return m, cmd
+}
+
+// submitCommitForm commits the container with the options of the commit
+// form, keeping the form open until it is done.
+func (m model) submitCommitForm(msg appui.FormSubmittedMsg) (tea.Model, tea.Cmd) {
+	m.form.SetBusy(fmt.Sprintf("Committing container %s...", shortID(msg.ID)))
+	daemon := m.daemonFor(msg.ID)
+	id, opts := msg.ID, commitOptions(msg.Values)
+	return m, func() tea.Msg {
+		imageID, err := daemon.CommitContainer(id, opts)
+		return containerCommittedMsg{id: id, reference: opts.Reference, imageID: imageID, err: err}
+	}
+}
+
+// containerCommitted closes the commit form once the image is created, or
+// shows in the form why it was not.
+func (m model) containerCommitted(msg containerCommittedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if m.overlay == overlayForm {
+			m.form.SetError(msg.err.Error())
+			return m, nil
+		}
+		m.messageBar.SetMessage(fmt.Sprintf("Commit error: %s", msg.err), 8*time.Second)
+		return m, nil
+	}
+	if m.overlay == overlayForm {
+		m.overlay = overlayNone
+	}
+	image := docker.TruncateID(docker.ImageID(msg.imageID))
+	if msg.reference != "" {
+		image = fmt.Sprintf("%s (%s)", msg.reference, image)
+	}
+	m.messageBar.SetMessage(fmt.Sprintf("Container %s committed to image %s", shortID(msg.id), image), 5*time.Second)
+	return m, m.loadViewData(m.view)
+}
+
+// tarFileName returns a file name in the working directory for an archive
+// of the given name.
+func (m model) tarFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', ':', '@', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+	return filepath.Join(m.workingDir, name+".tar")
+}
+
+// exportFileName is the file the container with the given id is exported
+// to by default.
+func (m model) exportFileName(id string) string {
+	name := shortID(id)
+	if c := m.containerAPI().ContainerByID(id); c != nil && len(c.Names) > 0 {
+		name = strings.TrimPrefix(c.Names[0], "/")
+	}
+	return m.tarFileName(name)
+}
+
+// saveFileName is the file the images with the given references are saved
+// to by default.
+func (m model) saveFileName(refs []string) string {
+	if len(refs) == 1 {
+		return m.tarFileName(refs[0])
+	}
+	return m.tarFileName("images")
+}
+
+// showExportPrompt asks for the file to export the filesystem of the given
+// container to.
+func (m model) showExportPrompt(c *docker.Container) (model, tea.Cmd) {
+	if c == nil {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.inputPrompt, cmd = appui.NewInputPromptModelWithLimit(
+		fmt.Sprintf("Export the filesystem of %s to:", paletteContainerLabel(c)),
+		m.exportFileName(c.ID), "export", c.ID, 1024,
+	)
+	m.inputPrompt.SetSize(m.width, m.height)
+	m.overlay = overlayInputPrompt
+	return m, cmd
+}
+
+// imageRefs returns the references an image is saved with, keeping its
+// tags in the archive.
+func imageRefs(img image.Summary) []string {
+	var refs []string
+	for _, tag := range img.RepoTags {
+		if tag != "<none>:<none>" {
+			refs = append(refs, tag)
+		}
+	}
+	if len(refs) == 0 {
+		return []string{img.ID}
+	}
+	return refs
+}
+
+// showSavePrompt asks for the file to save the marked images, or the
+// selected one, to.
+func (m model) showSavePrompt() (model, tea.Cmd) {
+	images := m.images.MarkedImages()
+	if len(images) == 0 {
+		if img := m.images.SelectedImage(); img != nil {
+			images = []image.Summary{*img}
+		}
+	}
+	if len(images) == 0 {
+		return m, nil
+	}
+	var refs []string
+	for _, img := range images {
+		refs = append(refs, imageRefs(img)...)
+	}
+	text := fmt.Sprintf("Save %d images to:", len(images))
+	if len(images) == 1 {
+		text = fmt.Sprintf("Save %s to:", imageLabel(images[0]))
+	}
+	var cmd tea.Cmd
+	m.inputPrompt, cmd = appui.NewInputPromptModelWithLimit(
+		text, m.saveFileName(refs), "image-save", strings.Join(refs, " "), 1024,
+	)
+	m.inputPrompt.SetSize(m.width, m.height)
+	m.overlay = overlayInputPrompt
+	return m, cmd
+}
+
+// showLoadPrompt asks for the tar archive to load images from.
+func (m model) showLoadPrompt() (model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.inputPrompt, cmd = appui.NewInputPromptModelWithLimit(
+		"Load images from the archive:", "path of a tar archive", "image-load", "", 1024,
+	)
+	m.inputPrompt.SetSize(m.width, m.height)
+	m.overlay = overlayInputPrompt
+	return m, cmd
+}
+
+// archiveCmd starts the transfer of the given input op: export, image-save
+// or image-load; id is the container exported or the references of the
+// images saved, separated by spaces.
+func (m model) archiveCmd(tag, id, path string) tea.Cmd {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		switch tag {
+		case "export":
+			path = m.exportFileName(id)
+		case "image-save":
+			path = m.saveFileName(strings.Fields(id))
+		default:
+			return nil
+		}
+	}
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return func() tea.Msg {
+			return statusMessageMsg{text: fmt.Sprintf("Error: %s", err), expiry: 5 * time.Second}
+		}
+	}
+	switch tag {
+	case "export":
+		daemon := m.daemonFor(id)
+		return startTransfer(fmt.Sprintf("Exporting %s", shortID(id)), func(ctx context.Context, progress func(int64)) (string, error) {
+			if err := daemon.ExportContainer(ctx, id, path, progress); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Container %s exported to %s", shortID(id), path), nil
+		})
+	case "image-save":
+		daemon, refs := m.daemon, strings.Fields(id)
+		label := refs[0]
+		if len(refs) > 1 {
+			label = fmt.Sprintf("%d images", len(refs))
+		}
+		return startTransfer(fmt.Sprintf("Saving %s", label), func(ctx context.Context, progress func(int64)) (string, error) {
+			if err := daemon.ImageSave(ctx, refs, path, progress); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Saved %s to %s", label, path), nil
+		})
+	case "image-load":
+		daemon := m.daemon
+		return startTransfer(fmt.Sprintf("Loading %s", filepath.Base(path)), func(ctx context.Context, progress func(int64)) (string, error) {
+			loaded, err := daemon.ImageLoad(ctx, path, progress)
+			if err != nil {
+				return "", err
+			}
+			if len(loaded) == 0 {
+				return fmt.Sprintf("No images loaded from %s", path), nil
+			}
+			return fmt.Sprintf("Loaded %s", strings.Join(loaded, ", ")), nil
+		})
+	}
+	return nil
+}
+
+// startTransfer runs a transfer in the background, reporting its start,
+// progress and end as transferMsgs, in that order. Progress reports are
+// dropped while the previous one is pending, so the transfer never waits
+// for the UI.
+func startTransfer(label string, run func(ctx context.Context, progress func(int64)) (string, error)) tea.Cmd {
+	return startFilesTransfer(label, "", run)
+}
+
+// startFilesTransfer is startTransfer for a copy into the container with
+// the given id, whose files the browser reads again once it is done.
+func startFilesTransfer(label, id string, run func(ctx context.Context, progress func(int64)) (string, error)) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan transferMsg, 1)
+	ch <- transferMsg{cancel: cancel}
+	go func() {
+		defer close(ch)
+		defer cancel()
+		progress := func(n int64) {
+			select {
+			case ch <- transferMsg{bytes: n}:
+			default:
+			}
+		}
+		message, err := run(ctx, progress)
+		ch <- transferMsg{done: true, message: message, err: err, files: id}
+	}()
+	return listenTransfer(label, ch)
+}
+
+func listenTransfer(label string, ch <-chan transferMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		msg.label, msg.ch = label, ch
+		return msg
+	}
+}
+
+// transferred shows the progress of a transfer, reloading the view once it
+// is done.
+func (m model) transferred(msg transferMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.cancel != nil:
+		if m.transfers == nil {
+			m.transfers = make(map[<-chan transferMsg]context.CancelFunc)
+		}
+		m.transfers[msg.ch] = msg.cancel
+		m.transferMessage(msg.label+"...", 5*time.Second)
+		return m, listenTransfer(msg.label, msg.ch)
+	case errors.Is(msg.err, context.Canceled):
+		delete(m.transfers, msg.ch)
+		m.transferMessage(fmt.Sprintf("%s cancelled", msg.label), 5*time.Second)
+		return m, nil
+	case msg.err != nil:
+		delete(m.transfers, msg.ch)
+		m.transferMessage(fmt.Sprintf("%s failed: %s", msg.label, msg.err), 8*time.Second)
+		return m, nil
+	case msg.done:
+		delete(m.transfers, msg.ch)
+		m.transferMessage(msg.message, 5*time.Second)
+		if msg.files != "" {
+			if msg.files == m.files.ContainerID() {
+				return m, m.loadFiles(msg.files)
+			}
+			return m, nil
+		}
+		return m, m.loadViewData(m.view)
+	}
+	m.transferMessage(fmt.Sprintf("%s: %s", msg.label, units.HumanSize(float64(msg.bytes))), 5*time.Second)
+	return m, listenTransfer(msg.label, msg.ch)
+}
+
+// transferMessage tells how a transfer goes in the message bar and, as it
+// covers the message bar, in the files browser.
+func (m *model) transferMessage(text string, expiry time.Duration) {
+	m.messageBar.SetMessage(text, expiry)
+	if m.filesOpen() {
+		m.files.SetMessage(text)
+	}
+}
+
+// cancelTransfers cancels the transfers in progress, returning false if
+// there are none.
+func (m model) cancelTransfers() (model, bool) {
+	if len(m.transfers) == 0 {
+		return m, false
+	}
+	for _, cancel := range m.transfers {
+		cancel()
+	}
+	m.transferMessage(fmt.Sprintf("Cancelling %d transfers...", len(m.transfers)), 5*time.Second)
+	return m, true
+}
@@ -0,0 +1,168 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/mocks"
+)
+
+// archivesRecordingDaemon records the commits and image saves it is asked
+// for.
+type archivesRecordingDaemon struct {
+	mocks.DockerDaemonMock
+	c       *docker.Container
+	commits []docker.CommitOptions
+	saved   []string
+	path    string
+}
+
+func (d *archivesRecordingDaemon) ContainerByID(id string) *docker.Container {
+	return d.c
+}
+
+func (d *archivesRecordingDaemon) CommitContainer(id string, opts docker.CommitOptions) (string, error) {
+	d.commits = append(d.commits, opts)
+	return d.DockerDaemonMock.CommitContainer(id, opts)
+}
+
+func (d *archivesRecordingDaemon) ImageSave(ctx context.Context, refs []string, path string, progress func(int64)) error {
+	d.saved, d.path = refs, path
+	return d.DockerDaemonMock.ImageSave(ctx, refs, path, progress)
+}
+
+func TestModel_CommitContainer(t *testing.T) {
+	m := newTestModel()
+	c := &docker.Container{Summary: container.Summary{ID: "c1", Names: []string{"/web"}, Status: "Up 1 minute"}}
+	daemon := &archivesRecordingDaemon{c: c}
+	m.daemon = daemon
+	m.containers.SetContainers([]*docker.Container{c})
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: 'I', Text: "I"})
+	m = result.(model)
+	if m.overlay != overlayForm || !strings.Contains(m.View().Content, "Commit web to an image") {
+		t.Fatalf("expected I to open the commit form:\n%s", m.View().Content)
+	}
+	for _, r := range "web:v2" {
+		result, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+		m = result.(model)
+	}
+
+	result, cmd := m.Update(appui.FormSubmittedMsg{Tag: commitFormTag, ID: "c1", Values: m.form.Values()})
+	m = runCmd(t, result.(model), cmd)
+	if len(daemon.commits) != 1 || daemon.commits[0].Reference != "web:v2" || daemon.commits[0].NoPause {
+		t.Fatalf("unexpected commits %+v", daemon.commits)
+	}
+	if m.overlay != overlayNone || m.messageBar.Message() != "Container c1 committed to image web:v2 (0123456789ab)" {
+		t.Fatalf("expected the form to close, message %q", m.messageBar.Message())
+	}
+}
+
+func TestCommitOptionsFromForm(t *testing.T) {
+	form, _ := appui.NewFormModel("Commit", commitFormTag, "", commitFormFields())
+	opts := commitOptions(form.Values())
+	if opts.Reference != "" || opts.Changes != nil || opts.NoPause {
+		t.Fatalf("unexpected defaults %+v", opts)
+	}
+}
+
+func TestSplitChanges(t *testing.T) {
+	got := splitChanges(`ENV A=1; CMD ["sh", "-c", "a; b"] ;LABEL x='y;z'; `)
+	want := []string{"ENV A=1", `CMD ["sh", "-c", "a; b"]`, "LABEL x='y;z'"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestModel_SaveAndLoadImages(t *testing.T) {
+	m := newTestModel()
+	m.workingDir = t.TempDir()
+	daemon := &archivesRecordingDaemon{}
+	m.daemon = daemon
+	m.view = Images
+	imgs, err := daemon.Images()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.images.SetImages(imgs)
+	img := m.images.SelectedImage()
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+	m = result.(model)
+	if m.overlay != overlayInputPrompt {
+		t.Fatal("expected s to ask where to save the image")
+	}
+	result, cmd := m.Update(appui.InputPromptResultMsg{Tag: "image-save", ID: strings.Join(imageRefs(*img), " ")})
+	m = runCmd(t, result.(model), cmd)
+	if strings.Join(daemon.saved, " ") != strings.Join(imageRefs(*img), " ") {
+		t.Fatalf("unexpected images saved %v", daemon.saved)
+	}
+	if filepath.Dir(daemon.path) != m.workingDir || filepath.Ext(daemon.path) != ".tar" {
+		t.Fatalf("expected a tar file in the working directory, got %s", daemon.path)
+	}
+	if msg := m.messageBar.Message(); !strings.HasPrefix(msg, "Saved ") || !strings.HasSuffix(msg, daemon.path) {
+		t.Fatalf("unexpected message %q", msg)
+	}
+
+	result, _ = m.Update(tea.KeyPressMsg{Code: 'l', Text: "l"})
+	m = result.(model)
+	if m.overlay != overlayInputPrompt {
+		t.Fatal("expected l to ask for the archive to load")
+	}
+	result, cmd = m.Update(appui.InputPromptResultMsg{Tag: "image-load", Value: daemon.path})
+	m = runCmd(t, result.(model), cmd)
+	if msg := m.messageBar.Message(); msg != "Loaded dry/dry:1" {
+		t.Fatalf("unexpected message %q", msg)
+	}
+}
+
+func TestModel_TransferProgress(t *testing.T) {
+	m := newTestModel()
+	ch := make(chan transferMsg)
+	result, cmd := m.Update(transferMsg{label: "Saving nginx:latest", bytes: 12 * 1000 * 1000, ch: ch})
+	m = result.(model)
+	if msg := m.messageBar.Message(); msg != "Saving nginx:latest: 12MB" {
+		t.Fatalf("unexpected message %q", msg)
+	}
+	if cmd == nil {
+		t.Fatal("expected to keep listening to the transfer")
+	}
+}
+
+func TestModel_TransferReportsItsStartFirst(t *testing.T) {
+	m := newTestModel()
+	cmd := startTransfer("Saving nginx:latest", func(context.Context, func(int64)) (string, error) {
+		return "Saved nginx:latest", nil
+	})
+	result, cmd := m.Update(cmd())
+	m = result.(model)
+	if msg := m.messageBar.Message(); msg != "Saving nginx:latest..." || len(m.transfers) != 1 {
+		t.Fatalf("expected the transfer to start first, message %q", msg)
+	}
+	m = runCmd(t, m, cmd)
+	if msg := m.messageBar.Message(); msg != "Saved nginx:latest" || len(m.transfers) != 0 {
+		t.Fatalf("expected the transfer to end last, message %q", msg)
+	}
+}
+
+func TestModel_CancelTransfers(t *testing.T) {
+	m := newTestModel()
+	cmd := startTransfer("Exporting c1", func(ctx context.Context, _ func(int64)) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	result, cmd := m.Update(cmd())
+	m = result.(model)
+	result, _ = m.Update(tea.KeyPressMsg{Code: 'x', Mod: tea.ModCtrl})
+	m = result.(model)
+	m = runCmd(t, m, cmd)
+	if msg := m.messageBar.Message(); msg != "Exporting c1 cancelled" || len(m.transfers) != 0 {
+		t.Fatalf("expected the transfer to be cancelled, message %q", msg)
+	}
+}
@@ -0,0 +1,82 @@
+package app
+
+// Pager buffers: writing the lines shown by the log/inspect viewer to a
+// file and piping them to a shell command.
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// writeBufferCmd writes the content of the viewer to a new file at path,
+// never replacing an existing one.
+func writeBufferCmd(path, content string) tea.Cmd {
+	return func() tea.Msg {
+		file, err := homedir.Expand(path)
+		if err == nil {
+			err = writeNewFile(file, content)
+		}
+		if errors.Is(err, fs.ErrExist) {
+			return lessStatusMsg{text: fmt.Sprintf("Could not write %s: the file already exists", path)}
+		}
+		if err != nil {
+			return lessStatusMsg{text: fmt.Sprintf("Could not write %s: %s", path, err)}
+		}
+		return lessStatusMsg{text: fmt.Sprintf("Wrote %d lines to %s", lineCount(content), file)}
+	}
+}
+
+func writeNewFile(path, content string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// pipeBufferCmd runs command with the shell, giving it the content of the
+// viewer on its standard input. The last line it prints is shown.
+func pipeBufferCmd(command, content string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Stdin = strings.NewReader(content)
+		out, err := cmd.CombinedOutput()
+		last := lastLine(out)
+		switch {
+		case err != nil && last != "":
+			return lessStatusMsg{text: fmt.Sprintf("%s: %s: %s", command, err, last)}
+		case err != nil:
+			return lessStatusMsg{text: fmt.Sprintf("%s: %s", command, err)}
+		case last != "":
+			return lessStatusMsg{text: fmt.Sprintf("Piped %d lines to %s: %s", lineCount(content), command, last)}
+		}
+		return lessStatusMsg{text: fmt.Sprintf("Piped %d lines to %s", lineCount(content), command)}
+	}
+}
+
+func lineCount(content string) int {
+	if content == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
+}
+
+func lastLine(out []byte) string {
+	out = bytes.TrimSpace(out)
+	if i := bytes.LastIndexByte(out, '\n'); i >= 0 {
+		out = out[i+1:]
+	}
+	return string(out)
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteBufferCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.txt")
+	msg := writeBufferCmd(path, "one\ntwo\n")().(lessStatusMsg)
+	if !strings.Contains(msg.text, "Wrote 2 lines") {
+		t.Fatalf("unexpected result %q", msg.text)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "one\ntwo\n" {
+		t.Fatalf("expected the buffer in the file, got %q (%v)", data, err)
+	}
+
+	msg = writeBufferCmd(path, "three\n")().(lessStatusMsg)
+	if !strings.Contains(msg.text, "already exists") {
+		t.Fatalf("expected the existing file to be kept, got %q", msg.text)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "one\ntwo\n" {
+		t.Fatalf("expected the file unchanged, got %q", data)
+	}
+
+	msg = writeBufferCmd(filepath.Join(path, "nope"), "x")().(lessStatusMsg)
+	if !strings.HasPrefix(msg.text, "Could not write") {
+		t.Fatalf("expected an error, got %q", msg.text)
+	}
+}
+
+func TestPipeBufferCmd(t *testing.T) {
+	msg := pipeBufferCmd("grep -c o", "one\ntwo\nthree\n")().(lessStatusMsg)
+	if msg.text != "Piped 3 lines to grep -c o: 2" {
+		t.Fatalf("unexpected result %q", msg.text)
+	}
+	msg = pipeBufferCmd("echo failed >&2; exit 3", "x")().(lessStatusMsg)
+	if !strings.Contains(msg.text, "exit status 3: failed") {
+		t.Fatalf("expected the command's error, got %q", msg.text)
+	}
+}
@@ -0,0 +1,190 @@
+package app
+
+// Bulk operations: the operations of the containers, images, networks,
+// volumes and services lists run on every marked row, after a confirmation
+// listing them, with a report of how each one went.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moncho/dry/appui"
+)
+
+// bulkItem is a marked row a bulk operation runs on.
+type bulkItem struct {
+	id   string
+	name string
+}
+
+// bulkOp is a bulk operation waiting for confirmation.
+type bulkOp struct {
+	op    string // tag of the operation on one item, see executeContainerOp
+	verb  string // what the operation does, as in "Stop"
+	noun  string // what it runs on, as in "container"
+	view  viewMode
+	items []bulkItem
+}
+
+// title describes the operation, as in "Stop 3 containers".
+func (b bulkOp) title() string {
+	noun := b.noun
+	if len(b.items) != 1 {
+		noun += "s"
+	}
+	return fmt.Sprintf("%s %d %s", b.verb, len(b.items), noun)
+}
+
+// bulkPromptNames is how many names a bulk confirmation lists.
+const bulkPromptNames = 5
+
+// summary lists the names of the items, the first bulkPromptNames of them.
+func (b bulkOp) summary() string {
+	var names []string
+	for i, item := range b.items {
+		if i == bulkPromptNames {
+			names = append(names, fmt.Sprintf("and %d more", len(b.items)-i))
+			break
+		}
+		names = append(names, item.name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// markedItems returns the rows marked in the list shown.
+func (m model) markedItems() []bulkItem {
+	var items []bulkItem
+	switch m.view {
+	case Main:
+		for _, c := range m.containers.MarkedContainers() {
+			items = append(items, bulkItem{id: c.ID, name: paletteContainerLabel(c)})
+		}
+	case Images:
+		for _, img := range m.images.MarkedImages() {
+			items = append(items, bulkItem{id: img.ID, name: imageLabel(img)})
+		}
+	case Networks:
+		for _, n := range m.networks.MarkedNetworks() {
+			items = append(items, bulkItem{id: n.ID, name: n.Name})
+		}
+	case Volumes:
+		for _, v := range m.volumes.MarkedVolumes() {
+			items = append(items, bulkItem{id: v.Name, name: v.Name})
+		}
+	case Services:
+		for _, s := range m.services.MarkedServices() {
+			items = append(items, bulkItem{id: s.ID, name: s.Spec.Name})
+		}
+	}
+	return items
+}
+
+// clearMarks unmarks the rows of the list of the given view, returning
+// false if none was marked.
+func (m *model) clearMarks(view viewMode) bool {
+	switch {
+	case view == Main && m.containers.MarkedCount() > 0:
+		m.containers.ClearMarks()
+	case view == Images && m.images.MarkedCount() > 0:
+		m.images.ClearMarks()
+	case view == Networks && m.networks.MarkedCount() > 0:
+		m.networks.ClearMarks()
+	case view == Volumes && m.volumes.MarkedCount() > 0:
+		m.volumes.ClearMarks()
+	case view == Services && m.services.MarkedCount() > 0:
+		m.services.ClearMarks()
+	default:
+		return false
+	}
+	return true
+}
+
+// showBulkPrompt asks for confirmation of a bulk operation on the marked
+// rows. It returns false if no row is marked.
+func (m model) showBulkPrompt(op, verb, noun string) (model, bool) {
+	items := m.markedItems()
+	if len(items) == 0 {
+		return m, false
+	}
+	b := bulkOp{op: op, verb: verb, noun: noun, view: m.view, items: items}
+	m.pendingBulk = &b
+	return m.showPrompt(fmt.Sprintf("%s? %s", b.title(), b.summary()), "bulk", ""), true
+}
+
+// showBulkScalePrompt asks for the number of replicas to scale the marked
+// services to. It returns false if no service is marked.
+func (m model) showBulkScalePrompt() (model, tea.Cmd, bool) {
+	items := m.markedItems()
+	if len(items) == 0 {
+		return m, nil, false
+	}
+	b := bulkOp{op: "service-scale", verb: "Scale", noun: "service", view: m.view, items: items}
+	m.pendingBulk = &b
+	var cmd tea.Cmd
+	m.inputPrompt, cmd = appui.NewInputPromptModel(
+		fmt.Sprintf("%s (%s) to replicas:", b.title(), b.summary()),
+		"number", "bulk", "",
+	)
+	m.inputPrompt.SetSize(m.width, m.height)
+	m.overlay = overlayInputPrompt
+	return m, cmd, true
+}
+
+// executeBulkOp runs a bulk operation on its items one after the other,
+// reporting how each one went. value is the input of operations that take
+// one, such as the replicas to scale services to.
+func (m model) executeBulkOp(b bulkOp, value string) tea.Cmd {
+	if b.op == "service-scale" {
+		if _, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64); err != nil {
+			return func() tea.Msg {
+				return statusMessageMsg{
+					text:   fmt.Sprintf("Invalid replica count: %s", value),
+					expiry: 5 * time.Second,
+				}
+			}
+		}
+	}
+	ops := make([]tea.Cmd, len(b.items))
+	for i, item := range b.items {
+		if b.op == "service-scale" {
+			ops[i] = m.executeInputOp(b.op, item.id, strings.TrimSpace(value))
+		} else {
+			ops[i] = m.executeContainerOp(b.op, item.id)
+		}
+	}
+	return func() tea.Msg {
+		report := bulkDoneMsg{title: b.title(), view: b.view}
+		for i, item := range b.items {
+			var msg tea.Msg
+			if ops[i] != nil {
+				msg = ops[i]()
+			}
+			switch msg := msg.(type) {
+			case operationSuccessMsg:
+				report.lines = append(report.lines, fmt.Sprintf("✓ %s: %s", item.name, msg.message))
+			case statusMessageMsg:
+				report.failed++
+				report.lines = append(report.lines, fmt.Sprintf("✗ %s: %s", item.name, msg.text))
+			default:
+				report.failed++
+				report.lines = append(report.lines, fmt.Sprintf("✗ %s: not done", item.name))
+			}
+		}
+		return report
+	}
+}
+
+// bulkDone shows the report of a bulk operation and reloads the list it
+// ran on, unmarking its rows.
+func (m model) bulkDone(msg bulkDoneMsg) (tea.Model, tea.Cmd) {
+	m.clearMarks(msg.view)
+	summary := fmt.Sprintf("%s: %d done, %d failed", msg.title, len(msg.lines)-msg.failed, msg.failed)
+	m.messageBar.SetMessage(summary, 5*time.Second)
+	content := strings.Join(msg.lines, "\n")
+	return m, tea.Batch(m.loadViewData(msg.view), func() tea.Msg {
+		return showLessMsg{content: content, title: summary}
+	})
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/mocks"
+)
+
+func TestModel_BulkStopMarkedContainers(t *testing.T) {
+	m := newTestModel()
+	m.containers.SetContainers((&mocks.DockerDaemonMock{}).Containers(nil, 0)[:4])
+
+	// v marks the first container, V the range from it to the third.
+	for _, key := range []tea.KeyPressMsg{{Code: 'v', Text: "v"}, {Code: 'j'}, {Code: 'V', Text: "V"}} {
+		result, _ := m.Update(key)
+		m = result.(model)
+	}
+	if n := m.containers.MarkedCount(); n != 3 {
+		t.Fatalf("expected 3 marked containers, got %d", n)
+	}
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: 't', Mod: tea.ModCtrl})
+	m = result.(model)
+	if m.overlay != overlayPrompt || m.pendingBulk == nil || m.pendingBulk.title() != "Stop 3 containers" {
+		t.Fatalf("expected a confirmation to stop 3 containers, got %+v", m.pendingBulk)
+	}
+
+	result, _ = m.Update(appui.PromptResultMsg{Confirmed: true, Tag: "bulk"})
+	m = result.(model)
+	if m.pendingBulk != nil {
+		t.Fatal("expected the bulk operation to be taken")
+	}
+}
+
+func TestExecuteBulkOpReportsEachItem(t *testing.T) {
+	m := newTestModel()
+	b := bulkOp{op: "stop", verb: "Stop", noun: "container", view: Main,
+		items: []bulkItem{{id: "1", name: "api"}, {id: "2", name: "db"}}}
+	msg, ok := m.executeBulkOp(b, "")().(bulkDoneMsg)
+	if !ok {
+		t.Fatalf("expected a bulkDoneMsg")
+	}
+	if msg.failed != 0 || len(msg.lines) != 2 || !strings.HasPrefix(msg.lines[1], "✓ db:") {
+		t.Fatalf("unexpected report %+v", msg)
+	}
+
+	b.op = "service-scale"
+	if _, ok := m.executeBulkOp(b, "many")().(statusMessageMsg); !ok {
+		t.Fatal("expected an invalid replica count to be refused")
+	}
+}
+
+func TestModel_EscapeClearsMarksFirst(t *testing.T) {
+	m := newTestModel()
+	m.containers.SetContainers((&mocks.DockerDaemonMock{}).Containers(nil, 0)[:2])
+	result, _ := m.Update(tea.KeyPressMsg{Code: '*', Text: "*"})
+	m = result.(model)
+	if m.containers.MarkedCount() != 2 {
+		t.Fatalf("expected * to mark every container, got %d", m.containers.MarkedCount())
+	}
+	result, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = result.(model)
+	if m.containers.MarkedCount() != 0 {
+		t.Fatal("expected escape to unmark the containers")
+	}
+}
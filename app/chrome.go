@@ -82,6 +82,7 @@ func (m model) renderFooter() string {
 			key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑↓", "move")),
 		}
 		leftBindings = append(leftBindings, m.workspaceViewFooterBindings()...)
+		leftBindings = m.keyOverrides.rebind(leftBindings)
 		return m.renderWorkspaceFooter(leftBindings, footerBg, keyStyle, descStyle, sepStyle)
 	}
 
@@ -117,6 +118,7 @@ func (m model) renderFooter() string {
 	bindings = append(bindings, globalKeys.Theme)
 	bindings = append(bindings, globalKeys.Palette)
 	bindings = append(bindings, globalKeys.QuickPeek)
+	bindings = m.keyOverrides.rebind(bindings)
 
 	renderBindings := func(bindings []key.Binding) string {
 		var b strings.Builder
@@ -216,7 +218,7 @@ func (m *model) resizeContentModels() {
 	ch := m.contentHeight()
 	width := m.width
 	height := ch
-	m.containers.SetCompact(m.workspaceEnabled())
+	m.containers.SetCompact(m.workspaceEnabled() || m.config.Compact)
 	if m.workspaceEnabled() {
 		width, _, height, _ = m.workspaceLayout()
 	}
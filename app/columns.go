@@ -0,0 +1,142 @@
+package app
+
+// Column layout: the views whose table columns can be chosen, the
+// [columns] and [format] configuration tables and the column picker
+// overlay.
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker/formatter"
+	"github.com/moncho/dry/export"
+)
+
+// columnViews maps the view names accepted in the [columns] table to the
+// list whose columns they set.
+var columnViews = map[string]func(m *model) appui.ColumnLayout{
+	"containers": func(m *model) appui.ColumnLayout { return &m.containers },
+	"images":     func(m *model) appui.ColumnLayout { return &m.images },
+	"networks":   func(m *model) appui.ColumnLayout { return &m.networks },
+	"volumes":    func(m *model) appui.ColumnLayout { return &m.volumes },
+	"nodes":      func(m *model) appui.ColumnLayout { return &m.nodes },
+	"services":   func(m *model) appui.ColumnLayout { return &m.services },
+	"stacks":     func(m *model) appui.ColumnLayout { return &m.stacks },
+}
+
+// formatViews maps the view names accepted in the [format] table to the
+// list whose rows the format renders.
+var formatViews = map[string]func(m *model, f *formatter.RowFormat){
+	"containers": func(m *model, f *formatter.RowFormat) { m.containers.SetRowFormat(f) },
+	"images":     func(m *model, f *formatter.RowFormat) { m.images.SetRowFormat(f) },
+	"networks":   func(m *model, f *formatter.RowFormat) { m.networks.SetRowFormat(f) },
+	"volumes":    func(m *model, f *formatter.RowFormat) { m.volumes.SetRowFormat(f) },
+}
+
+// parseColumnChoices parses the columns configured for a view, as
+// "key" or "key:width" entries.
+func parseColumnChoices(view string, columns []string) ([]appui.ColumnChoice, error) {
+	layout, ok := columnViews[view]
+	if !ok {
+		return nil, fmt.Errorf("unknown view %q in [columns]", view)
+	}
+	choices := make([]appui.ColumnChoice, 0, len(columns))
+	for _, c := range columns {
+		choice, err := appui.ParseColumnChoice(c)
+		if err != nil {
+			return nil, fmt.Errorf("[columns] %s: %w", view, err)
+		}
+		choices = append(choices, choice)
+	}
+	if err := appui.CheckColumnChoices(layout(&model{}).ColumnOptions(), choices); err != nil {
+		return nil, fmt.Errorf("[columns] %s: %w", view, err)
+	}
+	return choices, nil
+}
+
+// applyColumnConfig sets the columns of each view as configured. The
+// configuration was validated when it was loaded, so an error here only
+// comes from a Config built by hand, and it is reported on the message bar.
+func (m *model) applyColumnConfig() {
+	var invalid []string
+	for view, columns := range m.config.Columns {
+		choices, err := parseColumnChoices(view, columns)
+		if err == nil {
+			err = columnViews[view](m).SetColumnChoices(choices)
+		}
+		if err != nil {
+			invalid = append(invalid, err.Error())
+		}
+	}
+	if len(invalid) > 0 {
+		slices.Sort(invalid)
+		m.messageBar.SetMessage("Config: "+strings.Join(invalid, "; "), 10*time.Second)
+	}
+}
+
+// applyFormatConfig renders the rows of each view with its configured row
+// format, which wins over the columns configured for the view.
+func (m *model) applyFormatConfig() {
+	var invalid []string
+	for view, format := range m.config.Formats {
+		set, ok := formatViews[view]
+		if !ok {
+			invalid = append(invalid, fmt.Sprintf("[format] %s: view takes no format", view))
+			continue
+		}
+		f, err := export.ParseRowFormat(view, format)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("[format] %s: %s", view, err))
+			continue
+		}
+		set(m, f)
+	}
+	if len(invalid) > 0 {
+		slices.Sort(invalid)
+		m.messageBar.SetMessage("Config: "+strings.Join(invalid, "; "), 10*time.Second)
+	}
+}
+
+// columnLayout returns the name and the column layout of the view shown,
+// if its columns can be chosen.
+func (m *model) columnLayout() (string, appui.ColumnLayout, bool) {
+	for name, v := range viewNames {
+		if v != m.view {
+			continue
+		}
+		if layout, ok := columnViews[name]; ok {
+			return name, layout(m), true
+		}
+	}
+	return "", nil, false
+}
+
+// openColumnPicker shows the column picker for the view shown.
+func (m model) openColumnPicker() (tea.Model, tea.Cmd) {
+	name, layout, ok := m.columnLayout()
+	if !ok {
+		return m, nil
+	}
+	m.columnPicker = appui.NewColumnPickerModel(
+		"Columns: "+name, layout.ColumnOptions(), layout.ColumnChoices())
+	m.columnPicker.SetSize(m.width, m.height)
+	m.overlay = overlayColumnPicker
+	return m, nil
+}
+
+// chooseColumns applies the columns picked for the view shown.
+func (m model) chooseColumns(choices []appui.ColumnChoice) (tea.Model, tea.Cmd) {
+	m.overlay = overlayNone
+	_, layout, ok := m.columnLayout()
+	if !ok {
+		return m, nil
+	}
+	if err := layout.SetColumnChoices(choices); err != nil {
+		m.messageBar.SetMessage(fmt.Sprintf("Columns: %s", err), 5*time.Second)
+	}
+	return m, nil
+}
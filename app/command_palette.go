@@ -57,21 +57,36 @@ func (m model) commandPaletteActions() []paletteAction {
 			add("Container", "container:inspect", "Inspect", label, "inspect details")
 			add("Container", "container:logs", "Logs", label, "logs output")
 			add("Container", "container:stats", "Stats", label, "stats top usage")
+			add("Container", "container:health", "Health Checks", label, "health check probes")
 			add("Container", "container:exec", "Exec Shell", label, "exec shell terminal")
 			add("Container", "container:restart", "Restart", label, "restart")
 			add("Container", "container:stop", "Stop", label, "stop")
+			if docker.IsContainerPaused(c) {
+				add("Container", "container:pause", "Unpause", label, "unpause resume")
+			} else {
+				add("Container", "container:pause", "Pause", label, "pause freeze suspend")
+			}
+			add("Container", "container:rename", "Rename", label, "rename name")
+			add("Container", "container:update", "Update Resources", label, "update resources limits memory cpu pids restart")
+			add("Container", "container:files", "Browse Files", label, "files filesystem browse copy cp diff changes")
+			add("Container", "container:commit", "Commit to Image", label, "commit image snapshot")
+			add("Container", "container:export", "Export Filesystem", label, "export filesystem tar archive")
 			add("Container", "container:kill", "Kill", label, "kill")
 			add("Container", "container:rm", "Remove", label, "rm delete")
 		}
 		add("Containers", "containers:rm-stopped", "Remove All Stopped", "", "prune stopped remove")
+		add("Containers", "containers:unhealthy", "Toggle Unhealthy Only", "", "filter unhealthy health")
 	case Images:
 		if img := m.images.SelectedImage(); img != nil {
 			label := shortID(img.ID)
 			add("Image", "image:inspect", "Inspect", label, "inspect details")
 			add("Image", "image:history", "History", label, "history layers")
+			add("Image", "image:run", "Run Container", label, "run create start container docker run")
+			add("Image", "image:save", "Save to Archive", label, "save export tar archive")
 			add("Image", "image:rm", "Remove", label, "remove delete")
 			add("Image", "image:rm-force", "Force Remove", label, "force remove delete")
 		}
+		add("Images", "images:load", "Load from Archive", "", "load import tar archive")
 		add("Images", "images:rm-dangling", "Remove Dangling", "", "dangling cleanup")
 		add("Images", "images:rm-unused", "Remove Unused", "", "unused cleanup")
 	case Networks:
@@ -93,6 +108,7 @@ func (m model) commandPaletteActions() []paletteAction {
 			add("Monitor", "monitor:stats", "Open Container Stats", s.CID, "container stats")
 			add("Monitor", "monitor:exec", "Exec Shell", s.CID, "container exec shell")
 		}
+		add("Monitor", "monitor:alerts", "Show Alerts", "", "alerts rules notifications")
 	case Nodes:
 		if n := m.nodes.SelectedNode(); n != nil {
 			label := n.Description.Hostname
@@ -171,6 +187,19 @@ func (m model) commandPaletteActions() []paletteAction {
 	add("Docker", "global:info", "Show Info", "", "info")
 	add("Docker", "global:disk-usage", "Show Disk Usage", "", "disk usage")
 	add("Docker", "global:prune", "Prune Unused Resources", "", "prune cleanup unused")
+	add("Docker", "global:contexts", "Switch Context", m.activeContextName(), "context host endpoint switch")
+	if m.hosts != nil {
+		add("Docker", "global:all-hosts", "Show Current Host Only", m.activeContextName(), "hosts contexts aggregate all")
+	} else {
+		add("Docker", "global:all-hosts", "Show All Hosts", "containers from every context", "hosts contexts aggregate all")
+	}
+	if _, _, ok := m.columnLayout(); ok {
+		add("View", "global:columns", "Choose Columns", "", "columns layout width order")
+	}
+	if isSavedViewName(m.currentViewName()) {
+		add("View", "global:save-view", "Save View", "filter, sort and columns", "save named view filter sort columns")
+	}
+	actions = append(actions, m.savedViewActions()...)
 	add("Theme", "global:theme", "Cycle Color Theme", "", "color dark light")
 
 	if m.view != Main {
@@ -208,6 +237,12 @@ func (m model) commandPaletteActions() []paletteAction {
 }
 
 func (m model) executePaletteAction(id string) (tea.Model, tea.Cmd) {
+	if name, ok := strings.CutPrefix(id, "context:"); ok {
+		return m.switchContext(name)
+	}
+	if name, ok := strings.CutPrefix(id, "saved-view:"); ok {
+		return m.recallView(name)
+	}
 	switch id {
 	case "global:help":
 		return m, showHelpCmd()
@@ -228,6 +263,14 @@ func (m model) executePaletteAction(id string) (tea.Model, tea.Cmd) {
 	case "global:theme":
 		m.rotateTheme()
 		return m, nil
+	case "global:contexts":
+		return m.openContextPicker()
+	case "global:all-hosts":
+		return m.toggleAllHosts()
+	case "global:columns":
+		return m.openColumnPicker()
+	case "global:save-view":
+		return m.openSaveViewPrompt()
 	case "workspace:pin":
 		// Pin unconditionally: the palette is a snapshot, and by execution
 		// time the cursor may be back on the pinned item, where the toggle
@@ -289,6 +332,13 @@ func (m model) executePaletteAction(id string) (tea.Model, tea.Cmd) {
 		if c := m.containers.SelectedContainer(); c != nil {
 			return m.executeMenuCommand(c.ID, docker.STATS)
 		}
+	case "container:health":
+		if c := m.containers.SelectedContainer(); c != nil {
+			return m.executeMenuCommand(c.ID, docker.HEALTH)
+		}
+	case "containers:unhealthy":
+		m.containers.SetUnhealthyOnly(!m.containers.UnhealthyOnly())
+		return m, nil
 	case "container:exec":
 		if c := m.containers.SelectedContainer(); c != nil {
 			return m.executeMenuCommand(c.ID, docker.EXEC)
@@ -297,6 +347,30 @@ func (m model) executePaletteAction(id string) (tea.Model, tea.Cmd) {
 		if c := m.containers.SelectedContainer(); c != nil {
 			return m.executeMenuCommand(c.ID, docker.RESTART)
 		}
+	case "container:pause":
+		if m.containers.SelectedContainer() != nil {
+			return m.togglePause()
+		}
+	case "container:files":
+		if c := m.containers.SelectedContainer(); c != nil {
+			return m.showFiles(c)
+		}
+	case "container:commit":
+		if c := m.containers.SelectedContainer(); c != nil {
+			return m.showCommitForm(c)
+		}
+	case "container:export":
+		if c := m.containers.SelectedContainer(); c != nil {
+			return m.showExportPrompt(c)
+		}
+	case "container:update":
+		if c := m.containers.SelectedContainer(); c != nil {
+			return m.showResourcesForm(c)
+		}
+	case "container:rename":
+		if c := m.containers.SelectedContainer(); c != nil {
+			return m.showRenamePrompt(c)
+		}
 	case "container:stop":
 		if c := m.containers.SelectedContainer(); c != nil {
 			return m.executeMenuCommand(c.ID, docker.STOP)
@@ -313,6 +387,12 @@ func (m model) executePaletteAction(id string) (tea.Model, tea.Cmd) {
 		if img := m.images.SelectedImage(); img != nil {
 			return m, inspectImageCmd(m.daemon, img.ID)
 		}
+	case "image:run":
+		return m.showRunForm()
+	case "image:save":
+		return m.showSavePrompt()
+	case "images:load":
+		return m.showLoadPrompt()
 	case "image:history":
 		if img := m.images.SelectedImage(); img != nil {
 			return m, showImageHistoryCmd(m.daemon, img.ID)
@@ -353,12 +433,14 @@ func (m model) executePaletteAction(id string) (tea.Model, tea.Cmd) {
 		}
 	case "monitor:logs":
 		if s := m.monitor.SelectedStats(); s != nil {
-			return m, showContainerLogsCmd(m.daemon, statsContainerID(s))
+			return m, showContainerLogsCmd(m.daemon, statsContainerID(s), m.logTail())
 		}
 	case "monitor:stats":
 		if s := m.monitor.SelectedStats(); s != nil {
 			return m, showContainerStatsCmd(m.daemon, statsContainerID(s))
 		}
+	case "monitor:alerts":
+		return m.showAlerts()
 	case "monitor:exec":
 		if s := m.monitor.SelectedStats(); s != nil {
 			var cmd tea.Cmd
@@ -433,7 +515,7 @@ func (m model) executePaletteAction(id string) (tea.Model, tea.Cmd) {
 		}
 	case "compose-project-service:logs":
 		if svc := m.composeProjects.SelectedService(); svc != nil {
-			return m, showComposeLogsCmd(m.daemon, svc.Project, svc.Name)
+			return m, showComposeLogsCmd(m.daemon, svc.Project, svc.Name, m.logTail())
 		}
 	case "compose-project:open":
 		if p := m.composeProjects.SelectedProject(); p != nil {
@@ -444,7 +526,7 @@ func (m model) executePaletteAction(id string) (tea.Model, tea.Cmd) {
 		}
 	case "compose-project:logs":
 		if p := m.composeProjects.SelectedProject(); p != nil {
-			return m, showComposeLogsCmd(m.daemon, p.Name, "")
+			return m, showComposeLogsCmd(m.daemon, p.Name, "", m.logTail())
 		}
 	case "compose-project:stop":
 		if p := m.composeProjects.SelectedProject(); p != nil {
@@ -464,7 +546,7 @@ func (m model) executePaletteAction(id string) (tea.Model, tea.Cmd) {
 		}
 	case "compose-service:logs":
 		if svc := m.composeServices.SelectedService(); svc != nil {
-			return m, showComposeLogsCmd(m.daemon, svc.Project, svc.Name)
+			return m, showComposeLogsCmd(m.daemon, svc.Project, svc.Name, m.logTail())
 		}
 	case "compose-service:start":
 		if svc := m.composeServices.SelectedService(); svc != nil {
@@ -575,7 +657,7 @@ func (m model) executeWorkspaceOpenInspect() (tea.Model, tea.Cmd) {
 	}
 	switch {
 	case ctx.containerID != "":
-		return m, inspectContainerCmd(m.daemon, ctx.containerID)
+		return m, inspectContainerCmd(m.containerAPI(), ctx.containerID)
 	case ctx.imageID != "":
 		return m, inspectImageCmd(m.daemon, ctx.imageID)
 	case ctx.networkID != "":
@@ -606,13 +688,13 @@ func (m model) executeWorkspaceOpenLogs() (tea.Model, tea.Cmd) {
 	}
 	switch {
 	case ctx.containerID != "":
-		return m, showContainerLogsCmd(m.daemon, ctx.containerID)
+		return m, showContainerLogsCmd(m.containerAPI(), ctx.containerID, m.logTail())
 	case ctx.monitorCID != "":
-		return m, showContainerLogsCmd(m.daemon, ctx.monitorCID)
+		return m, showContainerLogsCmd(m.daemon, ctx.monitorCID, m.logTail())
 	case ctx.project != "" && ctx.service != "":
-		return m, showComposeLogsCmd(m.daemon, ctx.project, ctx.service)
+		return m, showComposeLogsCmd(m.daemon, ctx.project, ctx.service, m.logTail())
 	case ctx.project != "":
-		return m, showComposeLogsCmd(m.daemon, ctx.project, "")
+		return m, showComposeLogsCmd(m.daemon, ctx.project, "", m.logTail())
 	case ctx.serviceID != "":
 		return m, showServiceLogsCmd(m.daemon, ctx.serviceID)
 	}
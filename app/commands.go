@@ -2,6 +2,7 @@ package app
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -75,10 +76,23 @@ func (d *demuxedReadCloser) Close() error {
 
 // demuxDockerStream creates a pipe that demultiplexes Docker's multiplexed
 // log stream (stdout/stderr interleaved with 8-byte headers) into clean text.
+// Lines are written whole, those from stderr starting with
+// appui.StderrLineMark; stripStreamMarks removes the marks for views that
+// do not tell the streams apart. A line left incomplete, such as a prompt,
+// is written once the stream is idle for lineWriterIdle.
 func demuxDockerStream(raw io.ReadCloser) io.ReadCloser {
 	pr, pw := io.Pipe()
 	go func() {
-		_, err := stdcopy.StdCopy(pw, pw, raw)
+		out := &lineOutput{w: pw}
+		stdout := &lineWriter{out: out}
+		stderr := &lineWriter{out: out, mark: appui.StderrLineMark}
+		_, err := stdcopy.StdCopy(stdout, stderr, raw)
+		if err == nil {
+			err = stdout.flush()
+		}
+		if err == nil {
+			err = stderr.flush()
+		}
 		if err != nil {
 			pw.CloseWithError(err)
 		} else {
@@ -96,6 +110,122 @@ func demuxDockerStream(raw io.ReadCloser) io.ReadCloser {
 	}
 }
 
+const (
+	// lineWriterIdle is how long an incomplete line is held back, waiting
+	// for the rest of it.
+	lineWriterIdle = 200 * time.Millisecond
+	// lineWriterMax is how much of an incomplete line is held back at most.
+	lineWriterMax = 64 * 1024
+)
+
+// lineOutput is where the lineWriters of a stream write, keeping which of
+// them wrote the last line if it is incomplete.
+type lineOutput struct {
+	mu      sync.Mutex
+	w       io.Writer
+	midLine *lineWriter
+}
+
+// write writes the text of lw, marking the lines it starts. A line left
+// incomplete by the other writer is ended first, so that the text of each
+// stream stays on lines of its own. The caller holds mu.
+func (o *lineOutput) write(lw *lineWriter, text []byte) error {
+	if len(text) == 0 {
+		return nil
+	}
+	var b bytes.Buffer
+	if o.midLine != nil && o.midLine != lw {
+		b.WriteByte('\n')
+		o.midLine = nil
+	}
+	for _, line := range bytes.SplitAfter(text, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if o.midLine == nil {
+			b.WriteString(lw.mark)
+		}
+		b.Write(line)
+		o.midLine = nil
+		if line[len(line)-1] != '\n' {
+			o.midLine = lw
+		}
+	}
+	_, err := o.w.Write(b.Bytes())
+	return err
+}
+
+// lineWriter writes whole lines to out, those starting there marked with
+// mark, holding back the last line until it is complete, flushed, or no
+// more is written for lineWriterIdle.
+type lineWriter struct {
+	out     *lineOutput
+	mark    string
+	partial []byte
+	idle    *time.Timer
+	err     error // of the last idle flush
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.out.mu.Lock()
+	defer lw.out.mu.Unlock()
+	if lw.err != nil {
+		return 0, lw.err
+	}
+	lw.partial = append(lw.partial, p...)
+	if end := bytes.LastIndexByte(lw.partial, '\n'); end >= 0 {
+		if err := lw.out.write(lw, lw.partial[:end+1]); err != nil {
+			return 0, err
+		}
+		lw.partial = append(lw.partial[:0], lw.partial[end+1:]...)
+	}
+	if len(lw.partial) >= lineWriterMax {
+		if err := lw.out.write(lw, lw.partial); err != nil {
+			return 0, err
+		}
+		lw.partial = lw.partial[:0]
+	}
+	if len(lw.partial) > 0 {
+		if lw.idle == nil {
+			lw.idle = time.AfterFunc(lineWriterIdle, lw.flushIdle)
+		} else {
+			lw.idle.Reset(lineWriterIdle)
+		}
+	}
+	return len(p), nil
+}
+
+// flushIdle writes the incomplete line held back once nothing else is
+// written; an error is returned by the next write or flush.
+func (lw *lineWriter) flushIdle() {
+	lw.out.mu.Lock()
+	defer lw.out.mu.Unlock()
+	if lw.err == nil {
+		lw.err = lw.out.write(lw, lw.partial)
+	}
+	lw.partial = lw.partial[:0]
+}
+
+// flush writes the last line, even if it is not complete.
+func (lw *lineWriter) flush() error {
+	if lw.idle != nil {
+		lw.idle.Stop()
+	}
+	lw.out.mu.Lock()
+	defer lw.out.mu.Unlock()
+	if lw.err != nil {
+		return lw.err
+	}
+	err := lw.out.write(lw, lw.partial)
+	lw.partial = nil
+	return err
+}
+
+// stripStreamMarks removes the marks of stderr lines from log text.
+func stripStreamMarks(s string) string {
+	return strings.ReplaceAll(s, appui.StderrLineMark, "")
+}
+
 // readLogStreamCmd reads the next chunk from a streaming reader.
 func readLogStreamCmd(reader io.ReadCloser) tea.Cmd {
 	return func() tea.Msg {
@@ -123,7 +253,7 @@ func readWorkspaceActivityCmd(reader io.ReadCloser) tea.Cmd {
 		n, err := reader.Read(buf)
 		if n > 0 {
 			return appendWorkspaceActivityMsg{
-				content: string(buf[:n]),
+				content: stripStreamMarks(string(buf[:n])),
 				reader:  reader,
 			}
 		}
@@ -154,7 +284,7 @@ func collectQuickPeekLogContent(stream streamingContent) (string, error) {
 			switch {
 			case n > 0:
 				results <- logReadResult{
-					content: string(buf[:n]),
+					content: stripStreamMarks(string(buf[:n])),
 					err:     err,
 					eof:     errors.Is(err, io.EOF),
 				}
@@ -225,7 +355,11 @@ func loadContainerLogStream(daemon docker.ContainerAPI, id string) (streamingCon
 }
 
 func loadContainerLogStreamWithTail(daemon docker.ContainerAPI, id string, tail int) (streamingContent, error) {
-	reader, err := daemon.Logs(id, fmt.Sprintf("tail:%d", tail), false)
+	return loadContainerLogStreamWithOptions(daemon, id, docker.LogOptions{Tail: tail, Follow: true})
+}
+
+func loadContainerLogStreamWithOptions(daemon docker.ContainerAPI, id string, opts docker.LogOptions) (streamingContent, error) {
+	reader, err := daemon.Logs(id, opts)
 	if err != nil {
 		return streamingContent{}, err
 	}
@@ -269,7 +403,7 @@ func loadComposeLogStreamWithTail(daemon docker.ContainerAPI, project, service s
 	named := make(map[string]io.ReadCloser)
 	for _, c := range targets {
 		svcName := c.Labels["com.docker.compose.service"]
-		reader, err := daemon.Logs(c.ID, fmt.Sprintf("tail:%d", tail), false)
+		reader, err := daemon.Logs(c.ID, docker.LogOptions{Tail: tail, Follow: true})
 		if err != nil || reader == nil {
 			continue
 		}
@@ -302,6 +436,9 @@ type interactiveExecCommand struct {
 	stdin      io.Reader
 	stdout     io.Writer
 	stderr     io.Writer
+	// session, when set, is attached to the container since before it
+	// started and is streamed instead of attaching.
+	session *docker.AttachSession
 }
 
 func (c *interactiveExecCommand) SetStdin(r io.Reader)  { c.stdin = r }
@@ -309,6 +446,9 @@ func (c *interactiveExecCommand) SetStdout(w io.Writer) { c.stdout = w }
 func (c *interactiveExecCommand) SetStderr(w io.Writer) { c.stderr = w }
 
 func (c *interactiveExecCommand) Run() error {
+	if c.session != nil {
+		defer c.session.Close()
+	}
 	// Bubbletea passes /dev/tty (not os.Stdin) as the input reader.
 	// Use its fd for raw mode so keystrokes are sent immediately.
 	var fd int
@@ -323,7 +463,9 @@ func (c *interactiveExecCommand) Run() error {
 	}
 
 	var runErr error
-	if c.command != nil {
+	if c.session != nil {
+		runErr = c.session.Stream(c.stdin, c.stdout, c.stderr)
+	} else if c.command != nil {
 		// When waitForKey is true, don't forward stdin to the exec
 		// session so it remains available for the "press any key" read.
 		runErr = c.daemon.ExecInteractive(
@@ -391,9 +533,9 @@ func listenDockerEvents(ch <-chan events.Message) tea.Cmd {
 	return func() tea.Msg {
 		event, ok := <-ch
 		if !ok {
-			return eventsClosedMsg{}
+			return eventsClosedMsg{ch: ch}
 		}
-		return dockerEventMsg{event: event}
+		return dockerEventMsg{event: event, ch: ch}
 	}
 }
 
@@ -599,10 +741,12 @@ func inspectNetworkCmd(daemon docker.NetworkAPI, id string) tea.Cmd {
 	}
 }
 
-// showContainerLogsCmd opens a streaming log viewer for a container.
-func showContainerLogsCmd(daemon docker.ContainerAPI, id string) tea.Cmd {
+// showContainerLogsCmd opens a streaming log viewer for a container,
+// starting from the last tail lines.
+func showContainerLogsCmd(daemon docker.ContainerAPI, id string, tail int) tea.Cmd {
 	return func() tea.Msg {
-		stream, err := loadContainerLogStream(daemon, id)
+		opts := docker.LogOptions{Tail: tail, Follow: true}
+		stream, err := loadContainerLogStreamWithOptions(daemon, id, opts)
 		if err != nil {
 			return statusMessageMsg{
 				text:   fmt.Sprintf("Logs error: %s", err),
@@ -613,7 +757,20 @@ func showContainerLogsCmd(daemon docker.ContainerAPI, id string) tea.Cmd {
 			content: stream.content,
 			title:   stream.title,
 			reader:  stream.reader,
+			logs:    &logSource{api: daemon, id: id, opts: opts},
+		}
+	}
+}
+
+// reopenLogsCmd reads the logs of a container again, with the options of
+// the given source.
+func reopenLogsCmd(src logSource) tea.Cmd {
+	return func() tea.Msg {
+		stream, err := loadContainerLogStreamWithOptions(src.api, src.id, src.opts)
+		if err != nil {
+			return lessStatusMsg{text: fmt.Sprintf("Logs error: %s", err)}
 		}
+		return logsReopenedMsg{reader: stream.reader}
 	}
 }
 
@@ -634,6 +791,24 @@ func attachContainerCmd(daemon docker.ContainerRuntime, id string) tea.Cmd {
 	})
 }
 
+// attachSessionCmd streams an attach session opened on a container
+// before it started.
+func attachSessionCmd(session *docker.AttachSession) tea.Cmd {
+	cmd := &interactiveExecCommand{id: session.ID, session: session}
+	return tea.Exec(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return execEndedMsg{
+				text:   fmt.Sprintf("Attach error: %s", err),
+				expiry: 5 * time.Second,
+			}
+		}
+		return execEndedMsg{
+			text:   fmt.Sprintf("Attach ended: %s", shortID(session.ID)),
+			expiry: 3 * time.Second,
+		}
+	})
+}
+
 // isInteractiveShell returns true if the command looks like an interactive shell session.
 func isInteractiveShell(command []string) bool {
 	if len(command) == 0 {
@@ -869,9 +1044,9 @@ func inspectComposeServiceCmd(daemon docker.ContainerAPI, project, service strin
 
 // showComposeLogsCmd opens a merged streaming log viewer for compose
 // containers matching the given project and optional service.
-func showComposeLogsCmd(daemon docker.ContainerAPI, project, service string) tea.Cmd {
+func showComposeLogsCmd(daemon docker.ContainerAPI, project, service string, tail int) tea.Cmd {
 	return func() tea.Msg {
-		stream, err := loadComposeLogStream(daemon, project, service)
+		stream, err := loadComposeLogStreamWithTail(daemon, project, service, tail)
 		if err != nil {
 			return statusMessageMsg{
 				text:   err.Error(),
@@ -1186,40 +1361,65 @@ func loadWorkspaceMonitorDetailsFromContext(ctx workspaceContext, chartWidth, ch
 	return func() tea.Msg {
 		cpuHistory := ctx.monitorHistory(ctx.monitorCPUHistory, ctx.monitorCPU)
 		content := workspaceMonitorDetailContent(ctx, chartWidth, chartHeight)
+		window := ctx.chartWindow()
 		return workspaceActivityLoadedMsg{
 			title:   fmt.Sprintf("Monitor Details: %s", ctx.title),
-			status:  fmt.Sprintf("Live stats · %s/%s window", formatMonitorDuration(monitorCollectedDuration(cpuHistory, monitorChartWindow)), formatMonitorDuration(monitorChartWindow)),
+			status:  fmt.Sprintf("Live stats · %s/%s window", formatMonitorDuration(monitorCollectedDuration(cpuHistory, window)), formatMonitorDuration(window)),
 			content: content,
 		}
 	}
 }
 
 func workspaceMonitorDetailContent(ctx workspaceContext, chartWidth, chartHeight int) string {
-	cpuHistory := ctx.monitorHistory(ctx.monitorCPUHistory, ctx.monitorCPU)
-	memHistory := ctx.monitorHistory(ctx.monitorMemHistory, ctx.monitorPct)
 	halfWidth := chartWidth / 2
-	cpu := monitorHistorySection(
-		"CPU",
-		cpuHistory,
-		fmt.Sprintf("now %5.1f%%", ctx.monitorCPU),
-		appui.DryTheme.Info,
-		halfWidth,
-		chartHeight,
-		runes.ArcLineStyle,
-	)
-	mem := monitorHistorySection(
-		"Memory",
-		memHistory,
-		fmt.Sprintf("%s / %s  (%5.1f%%)", units.BytesSize(ctx.monitorMem), units.BytesSize(ctx.monitorMax), ctx.monitorPct),
-		appui.DryTheme.Secondary,
-		halfWidth,
-		chartHeight,
-		runes.ThinLineStyle,
-	)
+	section := func(title string, samples []appui.MonitorPoint, detail string, accent color.Color, width int, unit monitorUnit, lineStyle runes.LineStyle) string {
+		return monitorHistorySection(title, samples, detail, accent, width, chartHeight, ctx.chartWindow(), unit, lineStyle)
+	}
+	series := ctx.monitorSeries
+	var left, right, first, second string
+	switch series.Panel {
+	case appui.MonitorPanelNetwork:
+		first, second = "RX", "TX"
+		left = section("Network RX", ctx.monitorHistory(series.NetworkRx, 0),
+			monitorRateDetail(series.NetworkRx, ctx.monitorNetRx), appui.DryTheme.Info, halfWidth, monitorBytesPerSecond, runes.ArcLineStyle)
+		right = section("Network TX", ctx.monitorHistory(series.NetworkTx, 0),
+			monitorRateDetail(series.NetworkTx, ctx.monitorNetTx), appui.DryTheme.Secondary, halfWidth, monitorBytesPerSecond, runes.ThinLineStyle)
+	case appui.MonitorPanelBlockIO:
+		first, second = "Read", "Write"
+		left = section("Block Read", ctx.monitorHistory(series.BlockRead, 0),
+			monitorRateDetail(series.BlockRead, ctx.monitorBlockRead), appui.DryTheme.Info, halfWidth, monitorBytesPerSecond, runes.ArcLineStyle)
+		right = section("Block Write", ctx.monitorHistory(series.BlockWrite, 0),
+			monitorRateDetail(series.BlockWrite, ctx.monitorBlockWrite), appui.DryTheme.Secondary, halfWidth, monitorBytesPerSecond, runes.ThinLineStyle)
+	case appui.MonitorPanelPids:
+		first = "PIDs"
+		left = section("PIDs", ctx.monitorHistory(series.Pids, float64(ctx.monitorPids)),
+			fmt.Sprintf("now %d", ctx.monitorPids), appui.DryTheme.Info, chartWidth, monitorCount, runes.ArcLineStyle)
+	default:
+		first, second = "CPU", "Memory"
+		left = section("CPU", ctx.monitorHistory(ctx.monitorCPUHistory, ctx.monitorCPU),
+			fmt.Sprintf("now %5.1f%%", ctx.monitorCPU), appui.DryTheme.Info, halfWidth, monitorPercent, runes.ArcLineStyle)
+		right = section("Memory", ctx.monitorHistory(ctx.monitorMemHistory, ctx.monitorPct),
+			fmt.Sprintf("%s / %s  (%5.1f%%)", units.BytesSize(ctx.monitorMem), units.BytesSize(ctx.monitorMax), ctx.monitorPct),
+			appui.DryTheme.Secondary, halfWidth, monitorPercent, runes.ThinLineStyle)
+	}
 	indent := lipgloss.NewStyle().PaddingLeft(2)
-	cpuPane := lipgloss.PlaceHorizontal(halfWidth, lipgloss.Left, indent.Render(cpu))
-	memPane := lipgloss.PlaceHorizontal(halfWidth, lipgloss.Left, indent.Render(mem))
-	return monitorLegendLine() + "\n\n" + lipgloss.JoinHorizontal(lipgloss.Top, cpuPane, memPane)
+	legend := monitorLegendLine(first, second, series.Panel)
+	if right == "" {
+		return legend + "\n\n" + lipgloss.PlaceHorizontal(chartWidth, lipgloss.Left, indent.Render(left))
+	}
+	leftPane := lipgloss.PlaceHorizontal(halfWidth, lipgloss.Left, indent.Render(left))
+	rightPane := lipgloss.PlaceHorizontal(halfWidth, lipgloss.Left, indent.Render(right))
+	return legend + "\n\n" + lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
+}
+
+// monitorRateDetail describes the last rate of a series and the total it
+// was taken from.
+func monitorRateDetail(rates []appui.MonitorPoint, total float64) string {
+	now := 0.0
+	if len(rates) > 0 {
+		now = rates[len(rates)-1].Value
+	}
+	return fmt.Sprintf("now %s/s  (%s total)", units.HumanSize(now), units.HumanSize(total))
 }
 
 func (ctx workspaceContext) monitorHistory(history []appui.MonitorPoint, current float64) []appui.MonitorPoint {
@@ -1229,10 +1429,43 @@ func (ctx workspaceContext) monitorHistory(history []appui.MonitorPoint, current
 	return []appui.MonitorPoint{{At: time.Now(), Value: current}}
 }
 
-func monitorHistorySection(title string, samples []appui.MonitorPoint, detail string, accent color.Color, chartWidth, bodyHeight int, lineStyle runes.LineStyle) string {
+// chartWindow returns the span of time monitor charts show.
+func (ctx workspaceContext) chartWindow() time.Duration {
+	if ctx.monitorWindow > 0 {
+		return ctx.monitorWindow
+	}
+	return monitorChartWindow
+}
+
+// monitorUnit is the unit of the values of a chart.
+type monitorUnit int
+
+const (
+	monitorPercent monitorUnit = iota
+	monitorBytesPerSecond
+	monitorCount
+)
+
+// yRange returns the range of the Y axis of a chart of the given samples.
+func (u monitorUnit) yRange(samples []appui.MonitorPoint) (float64, float64) {
+	if u == monitorPercent {
+		return monitorChartYRange(samples)
+	}
+	return monitorValueYRange(samples)
+}
+
+// label formats a value on the Y axis.
+func (u monitorUnit) label(_ int, v float64) string {
+	if u == monitorBytesPerSecond {
+		return units.CustomSize("%.3g%s", v, 1000.0, []string{"B", "kB", "MB", "GB", "TB"})
+	}
+	return fmt.Sprintf("%.0f", v)
+}
+
+func monitorHistorySection(title string, samples []appui.MonitorPoint, detail string, accent color.Color, chartWidth, bodyHeight int, window time.Duration, unit monitorUnit, lineStyle runes.LineStyle) string {
 	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(appui.DryTheme.Fg)
 	detailStyle := lipgloss.NewStyle().Foreground(appui.DryTheme.FgMuted)
-	graph := monitorHistoryChart(samples, monitorChartWidth(chartWidth), monitorChartHeight(bodyHeight), accent, lineStyle)
+	graph := monitorHistoryChart(samples, monitorChartWidth(chartWidth), monitorChartHeight(bodyHeight), accent, window, unit, lineStyle)
 	return strings.Join([]string{
 		labelStyle.Render(title),
 		detailStyle.Render(detail),
@@ -1240,17 +1473,17 @@ func monitorHistorySection(title string, samples []appui.MonitorPoint, detail st
 	}, "\n")
 }
 
-func monitorHistoryChart(samples []appui.MonitorPoint, width, height int, accent color.Color, lineStyle runes.LineStyle) string {
+func monitorHistoryChart(samples []appui.MonitorPoint, width, height int, accent color.Color, window time.Duration, unit monitorUnit, lineStyle runes.LineStyle) string {
 	if len(samples) == 0 {
 		samples = []appui.MonitorPoint{{At: time.Now(), Value: 0}}
 	}
-	samples = trimMonitorHistory(samples, monitorChartWindow)
+	samples = trimMonitorHistory(samples, window)
 	minTime := samples[0].At
 	maxTime := samples[len(samples)-1].At
 	if !maxTime.After(minTime) {
 		maxTime = minTime.Add(time.Second)
 	}
-	minY, maxY := monitorChartYRange(samples)
+	minY, maxY := unit.yRange(samples)
 	style := lipgloss.NewStyle().Foreground(lipgloss.Color(colorToHex(accent)))
 	axisStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colorToHex(appui.DryTheme.FgSubtle)))
 	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colorToHex(appui.DryTheme.FgMuted)))
@@ -1260,6 +1493,7 @@ func monitorHistoryChart(samples []appui.MonitorPoint, width, height int, accent
 		timeserieslinechart.WithTimeRange(minTime, maxTime),
 		timeserieslinechart.WithYRange(minY, maxY),
 		timeserieslinechart.WithXLabelFormatter(timeserieslinechart.HourTimeLabelFormatter()),
+		timeserieslinechart.WithYLabelFormatter(unit.label),
 		timeserieslinechart.WithXYSteps(0, 2),
 		timeserieslinechart.WithLineStyle(lineStyle),
 		timeserieslinechart.WithStyle(style),
@@ -1275,19 +1509,24 @@ func monitorHistoryChart(samples []appui.MonitorPoint, width, height int, accent
 	return strings.TrimRight(chart.View(), "\n")
 }
 
-func monitorLegendLine() string {
+// monitorLegendLine names the series charted with an arc and with a line,
+// if any, and the panel shown.
+func monitorLegendLine(arc, line string, panel appui.MonitorPanel) string {
 	labelStyle := lipgloss.NewStyle().
 		Foreground(appui.DryTheme.FgSubtle)
-	cpuStyle := lipgloss.NewStyle().
+	arcStyle := lipgloss.NewStyle().
 		Foreground(appui.DryTheme.Info)
-	memStyle := lipgloss.NewStyle().
+	lineStyle := lipgloss.NewStyle().
 		Foreground(appui.DryTheme.Secondary)
-	return strings.Join([]string{
-		labelStyle.Render("Legend:"),
-		cpuStyle.Render("CPU arc"),
-		memStyle.Render("Memory line"),
+	parts := []string{labelStyle.Render("Legend:"), arcStyle.Render(arc + " arc")}
+	if line != "" {
+		parts = append(parts, lineStyle.Render(line+" line"))
+	}
+	parts = append(parts,
 		labelStyle.Render("Y auto-scaled"),
-	}, labelStyle.Render("  ·  "))
+		labelStyle.Render(fmt.Sprintf("g %s (%d/%d)", panel, int(panel)+1, len(appui.MonitorPanels))),
+	)
+	return strings.Join(parts, labelStyle.Render("  ·  "))
 }
 
 func trimMonitorHistory(samples []appui.MonitorPoint, window time.Duration) []appui.MonitorPoint {
@@ -1368,6 +1607,20 @@ func monitorChartYRange(samples []appui.MonitorPoint) (float64, float64) {
 	return minY, maxY
 }
 
+// monitorValueYRange returns the range of the Y axis of a chart of values
+// without an upper bound, such as rates: from zero to a little over the
+// largest value.
+func monitorValueYRange(samples []appui.MonitorPoint) (float64, float64) {
+	maxY := 0.0
+	for _, sample := range samples {
+		maxY = math.Max(maxY, sample.Value)
+	}
+	if maxY <= 0 {
+		return 0, 4
+	}
+	return 0, maxY * 1.15
+}
+
 func colorToHex(c color.Color) string {
 	r, g, b, _ := c.RGBA()
 	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
@@ -1557,7 +1810,8 @@ func parseWorkspaceCount(lines []string, key string) int {
 }
 
 // mergeLogReaders multiplexes multiple named log readers into a single
-// reader, prefixing each line with its name (e.g. "api | <line>").
+// reader, prefixing each line with its name (e.g. "api | <line>"). The mark
+// of a stderr line is kept at its start.
 func mergeLogReaders(named map[string]io.ReadCloser) io.ReadCloser {
 	pr, pw := io.Pipe()
 	var mu sync.Mutex
@@ -1571,7 +1825,11 @@ func mergeLogReaders(named map[string]io.ReadCloser) io.ReadCloser {
 			scanner := bufio.NewScanner(r)
 			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
 			for scanner.Scan() {
-				line := fmt.Sprintf("%s | %s\n", prefix, scanner.Text())
+				text, stderr := strings.CutPrefix(scanner.Text(), appui.StderrLineMark)
+				line := fmt.Sprintf("%s | %s\n", prefix, text)
+				if stderr {
+					line = appui.StderrLineMark + line
+				}
 				mu.Lock()
 				_, err := pw.Write([]byte(line))
 				mu.Unlock()
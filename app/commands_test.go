@@ -1,6 +1,7 @@
 package app
 
 import (
+	"bytes"
 	"io"
 	"strings"
 	"testing"
@@ -172,3 +173,125 @@ func TestMonitorCollectedDurationCapsAtWindow(t *testing.T) {
 		t.Fatalf("expected formatted duration %q, got %q", want, got)
 	}
 }
+
+// frame builds a frame of Docker's multiplexed log stream.
+func frame(stream byte, payload string) []byte {
+	header := []byte{stream, 0, 0, 0, 0, 0, 0, byte(len(payload))}
+	return append(header, payload...)
+}
+
+func TestDemuxDockerStreamMarksStderrLines(t *testing.T) {
+	var raw []byte
+	raw = append(raw, frame(1, "starting\nlisten")...)
+	raw = append(raw, frame(2, "warning: low memory\n")...)
+	raw = append(raw, frame(1, "ing on :80\n")...)
+	raw = append(raw, frame(2, "exit")...)
+
+	out, err := io.ReadAll(demuxDockerStream(io.NopCloser(bytes.NewReader(raw))))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mark := appui.StderrLineMark
+	want := "starting\n" + mark + "warning: low memory\nlistening on :80\n" + mark + "exit"
+	if string(out) != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+	if got := stripStreamMarks(string(out)); strings.Contains(got, mark) {
+		t.Fatalf("expected marks to be stripped, got %q", got)
+	}
+}
+
+func TestDemuxDockerStreamWritesIncompleteLinesWhenIdle(t *testing.T) {
+	raw, w := io.Pipe()
+	defer w.Close()
+	demuxed := demuxDockerStream(raw)
+	defer demuxed.Close()
+
+	read := func(want string) {
+		t.Helper()
+		got := make(chan string, 1)
+		go func() {
+			buf := make([]byte, 64)
+			n, _ := demuxed.Read(buf)
+			got <- string(buf[:n])
+		}()
+		select {
+		case s := <-got:
+			if s != want {
+				t.Fatalf("expected %q, got %q", want, s)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %q to be written without a newline", want)
+		}
+	}
+	mark := appui.StderrLineMark
+	go func() { _, _ = w.Write(frame(2, "Downloading 10%")) }()
+	read(mark + "Downloading 10%")
+	go func() { _, _ = w.Write(frame(2, " 20%\n")) }()
+	read(" 20%\n")
+
+	// stderr written after an incomplete stdout line starts a line of its
+	// own, marked.
+	go func() { _, _ = w.Write(frame(1, "Password: ")) }()
+	read("Password: ")
+	go func() { _, _ = w.Write(frame(2, "timed out\n")) }()
+	read("\n" + mark + "timed out\n")
+}
+
+func TestMergeLogReadersKeepsStderrMarks(t *testing.T) {
+	mark := appui.StderrLineMark
+	out, err := io.ReadAll(mergeLogReaders(map[string]io.ReadCloser{
+		"api": io.NopCloser(strings.NewReader(mark + "boom\n")),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := mark + "api | boom\n"; string(out) != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+}
+
+func TestWorkspaceMonitorDetailContentSwitchesPanels(t *testing.T) {
+	points := func(values ...float64) []appui.MonitorPoint {
+		var p []appui.MonitorPoint
+		for i, v := range values {
+			p = append(p, appui.MonitorPoint{At: time.Unix(int64(i), 0), Value: v})
+		}
+		return p
+	}
+	ctx := workspaceContext{
+		title:             "redis",
+		monitorNetRx:      30_000_000,
+		monitorNetTx:      2_000_000,
+		monitorBlockRead:  4096,
+		monitorBlockWrite: 8192,
+		monitorPids:       12,
+		monitorSeries: appui.MonitorSeries{
+			NetworkRx:  points(1000, 250_000, 1_500_000),
+			NetworkTx:  points(10, 20, 30),
+			BlockRead:  points(0, 0, 0),
+			BlockWrite: points(0, 4096, 0),
+			Pids:       points(10, 11, 12),
+		},
+	}
+	tests := []struct {
+		panel appui.MonitorPanel
+		want  []string
+	}{
+		{appui.MonitorPanelNetwork, []string{"RX arc", "TX line", "g Network (2/4)", "Network RX", "now 1.5MB/s  (30MB total)", "Network TX", "now 30B/s"}},
+		{appui.MonitorPanelBlockIO, []string{"Read arc", "Write line", "Block Read", "Block Write", "now 0B/s  (8.192kB total)"}},
+		{appui.MonitorPanelPids, []string{"PIDs arc", "g PIDs (4/4)", "now 12"}},
+	}
+	for _, tt := range tests {
+		ctx.monitorSeries.Panel = tt.panel
+		plain := ansi.Strip(workspaceMonitorDetailContent(ctx, 96, 18))
+		for _, want := range tt.want {
+			if !strings.Contains(plain, want) {
+				t.Fatalf("expected %q in the %s panel, got:\n%s", want, tt.panel, plain)
+			}
+		}
+		if strings.Contains(plain, "CPU arc") {
+			t.Fatalf("did not expect the CPU chart in the %s panel", tt.panel)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package app
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/moncho/dry/docker"
+)
 
 // Config dry initial configuration
 type Config struct {
@@ -11,4 +18,122 @@ type Config struct {
 	MonitorRefreshRate int
 	SplashDuration     time.Duration
 	WorkspaceMode      bool
+
+	// StartView names the view shown once dry is connected to Docker
+	// (containers, images, monitor, ...). MonitorMode takes precedence.
+	StartView string
+	// LogTail is the number of lines fetched when a log viewer opens.
+	LogTail int
+	// Compact forces the compact container column set outside the
+	// workspace layout.
+	Compact bool
+	// Sort maps a view name to the column (or, for containers, the sort
+	// mode) it is sorted by on startup.
+	Sort map[string]string
+	// Keys rebinds built-in keys, see keyOverrides.
+	Keys map[string]string
+	// Hosts are extra Docker endpoints offered in the context picker.
+	Hosts []HostConfig
+	// Columns maps a view name to the columns it shows, as "key" or
+	// "key:width" entries.
+	Columns map[string][]string
+	// Formats maps a view name to the row format template, as given to
+	// docker ps --format, its rows are rendered with instead of its columns.
+	Formats map[string]string
+	// Views are the saved views offered in the command palette.
+	Views []SavedView
+	// ConfigFile is the configuration file views are saved to; views saved
+	// without one only last until dry exits.
+	ConfigFile string
+	// MetricsDir is the directory monitor stats are kept in, so charts can
+	// span hours or days; stats are only kept in memory if it is empty.
+	MetricsDir string
+	// MetricsRetention is how long stats are kept in MetricsDir.
+	MetricsRetention time.Duration
+	// Alerts are the alert rules evaluated in monitor mode.
+	Alerts []string
+	// AlertBell rings the terminal bell when an alert fires.
+	AlertBell bool
+	// AlertNotify sends a desktop notification when an alert fires.
+	AlertNotify bool
+}
+
+// defaultMonitorRefresh is how often buffered monitor stats are flushed to
+// the table when no refresh rate is configured.
+const defaultMonitorRefresh = 500 * time.Millisecond
+
+// startView returns the view to show once dry is connected. Swarm views
+// fall back to containers when the daemon is not part of a swarm.
+func (m model) startView() viewMode {
+	if m.config.MonitorMode {
+		return Monitor
+	}
+	v, ok := viewNames[m.config.StartView]
+	if !ok {
+		return Main
+	}
+	if (v == Nodes || v == Services || v == Stacks) && !m.swarmMode {
+		return Main
+	}
+	return v
+}
+
+// logTail returns the number of log lines fetched when a log viewer opens.
+func (m model) logTail() int {
+	if m.config.LogTail > 0 {
+		return m.config.LogTail
+	}
+	return workspaceActivityLogTail
+}
+
+// monitorRefresh returns how often buffered monitor stats are flushed.
+func (m model) monitorRefresh() time.Duration {
+	if m.config.MonitorRefreshRate > 0 {
+		return time.Duration(m.config.MonitorRefreshRate) * time.Millisecond
+	}
+	return defaultMonitorRefresh
+}
+
+// applySortConfig sorts each view as configured. A column that does not
+// exist is reported on the message bar rather than silently ignored.
+func (m *model) applySortConfig() {
+	var unknown []string
+	for view, field := range m.config.Sort {
+		var ok bool
+		switch view {
+		case "containers":
+			var mode docker.SortMode
+			if mode, ok = containerSortModes[strings.ToLower(field)]; ok {
+				m.containers.SetSortMode(mode)
+			}
+		case "images":
+			ok = m.images.SortBy(field)
+		case "networks":
+			ok = m.networks.SortBy(field)
+		case "volumes":
+			ok = m.volumes.SortBy(field)
+		case "monitor":
+			ok = m.monitor.SortBy(field)
+		case "nodes":
+			ok = m.nodes.SortBy(field)
+		case "services":
+			ok = m.services.SortBy(field)
+		case "stacks":
+			ok = m.stacks.SortBy(field)
+		case "tasks":
+			ok = m.tasks.SortBy(field)
+		case "compose":
+			ok = m.composeProjects.SortBy(field)
+		case "compose-services":
+			ok = m.composeServices.SortBy(field)
+		}
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("%s=%q", view, field))
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		m.messageBar.SetMessage(
+			"Config: unknown sort column "+strings.Join(unknown, ", "), 10*time.Second)
+	}
 }
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/moncho/dry/alert"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/export"
+)
+
+// FileConfig holds the settings read from dry's configuration file. Every
+// field is optional; command-line flags take precedence over anything set
+// here.
+type FileConfig struct {
+	Theme       string              `toml:"theme"`
+	View        string              `toml:"view"`
+	RefreshRate int                 `toml:"refresh-rate"`
+	LogTail     int                 `toml:"log-tail"`
+	Compact     bool                `toml:"compact"`
+	Workspace   bool                `toml:"workspace"`
+	Sort        map[string]string   `toml:"sort"`
+	Keys        map[string]string   `toml:"keys"`
+	Hosts       []HostConfig        `toml:"hosts"`
+	Columns     map[string][]string `toml:"columns"`
+	Format      map[string]string   `toml:"format"`
+	Views       []SavedView         `toml:"views"`
+	// MetricsHistory keeps monitor stats on disk, under MetricsDir (or
+	// metrics.DefaultDir) for MetricsRetention (a duration such as "48h").
+	MetricsHistory   bool   `toml:"metrics-history"`
+	MetricsDir       string `toml:"metrics-dir"`
+	MetricsRetention string `toml:"metrics-retention"`
+	// Alerts are the rules evaluated in monitor mode, such as
+	// "cpu > 90% for 30s" or "restarted"; AlertBell rings the terminal bell
+	// and AlertNotify sends a desktop notification when one fires.
+	Alerts      []string `toml:"alerts"`
+	AlertBell   bool     `toml:"alert-bell"`
+	AlertNotify bool     `toml:"alert-notify"`
+}
+
+// HostConfig is a Docker endpoint listed in the configuration file, offered
+// in the context picker next to the Docker CLI contexts.
+type HostConfig struct {
+	Name      string `toml:"name"`
+	Host      string `toml:"host"`
+	CertPath  string `toml:"cert-path"`
+	TLSVerify bool   `toml:"tls-verify"`
+}
+
+// viewNames maps the view names accepted in the configuration file to
+// dry's views.
+var viewNames = map[string]viewMode{
+	"containers": Main,
+	"images":     Images,
+	"networks":   Networks,
+	"volumes":    Volumes,
+	"disk-usage": DiskUsage,
+	"monitor":    Monitor,
+	"nodes":      Nodes,
+	"services":   Services,
+	"stacks":     Stacks,
+	"compose":    ComposeProjects,
+}
+
+// sortableViews lists the views that accept an entry in the [sort] table.
+// Task lists and compose services have no top-level view of their own, so
+// they get a name here without being a valid start view.
+var sortableViews = []string{
+	"containers", "images", "networks", "volumes", "monitor",
+	"nodes", "services", "stacks", "tasks",
+	"compose", "compose-services",
+}
+
+// containerSortModes maps the container sort names accepted in the
+// configuration file to the daemon-side sort modes.
+var containerSortModes = map[string]docker.SortMode{
+	"none":   docker.NoSort,
+	"id":     docker.SortByContainerID,
+	"image":  docker.SortByImage,
+	"status": docker.SortByStatus,
+	"name":   docker.SortByName,
+}
+
+// DefaultConfigFile returns the path of dry's configuration file:
+// $XDG_CONFIG_HOME/dry/config.toml, or ~/.config/dry/config.toml when
+// XDG_CONFIG_HOME is not set.
+func DefaultConfigFile() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "dry", "config.toml")
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "dry", "config.toml")
+}
+
+// LoadConfigFile reads the configuration file at the given path. A missing
+// file is not an error, it just yields an empty configuration.
+func LoadConfigFile(path string) (FileConfig, error) {
+	var cfg FileConfig
+	if path == "" {
+		return cfg, nil
+	}
+	md, err := toml.DecodeFile(path, &cfg)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return FileConfig{}, nil
+		}
+		return FileConfig{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return FileConfig{}, fmt.Errorf("%s: unknown setting %q", path, undecoded[0].String())
+	}
+	if err := cfg.validate(); err != nil {
+		return FileConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c FileConfig) validate() error {
+	if c.View != "" {
+		if _, ok := viewNames[c.View]; !ok {
+			return fmt.Errorf("unknown view %q", c.View)
+		}
+	}
+	if c.RefreshRate < 0 {
+		return fmt.Errorf("invalid refresh-rate %d", c.RefreshRate)
+	}
+	if c.LogTail < 0 {
+		return fmt.Errorf("invalid log-tail %d", c.LogTail)
+	}
+	if c.MetricsRetention != "" {
+		if d, err := time.ParseDuration(c.MetricsRetention); err != nil || d <= 0 {
+			return fmt.Errorf("invalid metrics-retention %q", c.MetricsRetention)
+		}
+	}
+	for _, rule := range c.Alerts {
+		if _, err := alert.Parse(rule); err != nil {
+			return err
+		}
+	}
+	for view, field := range c.Sort {
+		if !slices.Contains(sortableViews, view) {
+			return fmt.Errorf("unknown view %q in [sort]", view)
+		}
+		if view == "containers" {
+			if _, ok := containerSortModes[strings.ToLower(field)]; !ok {
+				return fmt.Errorf("unknown container sort %q", field)
+			}
+		}
+	}
+	for from, to := range c.Keys {
+		for _, k := range []string{from, to} {
+			if _, err := parseKeystroke(k); err != nil {
+				return fmt.Errorf("[keys]: %w", err)
+			}
+		}
+		if from == "ctrl+c" {
+			return errors.New("[keys]: ctrl+c cannot be rebound")
+		}
+	}
+	for view, columns := range c.Columns {
+		if _, err := parseColumnChoices(view, columns); err != nil {
+			return err
+		}
+	}
+	for view, format := range c.Format {
+		if _, err := export.ParseRowFormat(view, format); err != nil {
+			return fmt.Errorf("[format] %s: %w", view, err)
+		}
+	}
+	views := make(map[string]bool, len(c.Views))
+	for _, v := range c.Views {
+		if err := v.validate(); err != nil {
+			return err
+		}
+		if views[v.Name] {
+			return fmt.Errorf("[[views]]: duplicate view %q", v.Name)
+		}
+		views[v.Name] = true
+	}
+	names := make(map[string]bool, len(c.Hosts))
+	for _, h := range c.Hosts {
+		switch {
+		case h.Name == "" || h.Host == "":
+			return errors.New("[[hosts]]: name and host are required")
+		case h.Name == defaultContextName:
+			return fmt.Errorf("[[hosts]]: %q is reserved for the endpoint dry starts with", h.Name)
+		case names[h.Name]:
+			return fmt.Errorf("[[hosts]]: duplicate host %q", h.Name)
+		}
+		names[h.Name] = true
+	}
+	return nil
+}
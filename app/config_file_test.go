@@ -0,0 +1,226 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := writeConfigFile(t, `
+theme = "light"
+view = "images"
+refresh-rate = 1000
+log-tail = 2000
+compact = true
+
+[sort]
+containers = "name"
+images = "size"
+
+[keys]
+"l" = "L"
+"ctrl+e" = "f4"
+`)
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Theme != "light" || cfg.View != "images" || cfg.RefreshRate != 1000 ||
+		cfg.LogTail != 2000 || !cfg.Compact {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if cfg.Sort["images"] != "size" || cfg.Keys["ctrl+e"] != "f4" {
+		t.Fatalf("unexpected tables: %+v", cfg)
+	}
+}
+
+func TestLoadConfigFile_MissingFileIsEmpty(t *testing.T) {
+	cfg, err := LoadConfigFile(filepath.Join(t.TempDir(), "nope.toml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Theme != "" || cfg.Sort != nil {
+		t.Fatalf("expected empty config, got %+v", cfg)
+	}
+}
+
+func TestLoadConfigFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown setting", `colour = "red"`, `unknown setting "colour"`},
+		{"unknown view", `view = "pods"`, `unknown view "pods"`},
+		{"invalid metrics retention", `metrics-retention = "a week"`, `invalid metrics-retention "a week"`},
+		{"invalid alert rule", `alerts = ["disk > 90%"]`, `invalid alert rule "disk > 90%"`},
+		{"unknown sort view", "[sort]\npods = \"name\"", `unknown view "pods" in [sort]`},
+		{"unknown container sort", "[sort]\ncontainers = \"size\"", `unknown container sort "size"`},
+		{"invalid key", "[keys]\n\"l\" = \"control+l\"", `unknown modifier "control"`},
+		{"non canonical key", "[keys]\n\"l\" = \"shift+ctrl+l\"", `did you mean "ctrl+shift+l"?`},
+		{"ctrl+c", "[keys]\n\"ctrl+c\" = \"q\"", "ctrl+c cannot be rebound"},
+		{"unknown columns view", "[columns]\nmonitor = [\"cpu\"]", `unknown view "monitor" in [columns]`},
+		{"unknown column", "[columns]\nimages = [\"ports\"]", `[columns] images: unknown column "ports"`},
+		{"invalid column width", "[columns]\ncontainers = [\"image:-3\"]", `invalid width in column "image:-3"`},
+		{"unknown format field", "[format]\nimages = \"{{.Names}}\"", `[format] images: template execution`},
+		{"format for swarm view", "[format]\nnodes = \"{{.ID}}\"", `view "nodes" takes no row format`},
+		{"unnamed view", "[[views]]\nview = \"images\"", "[[views]]: name is required"},
+		{"saved view of unknown view", "[[views]]\nname = \"x\"\nview = \"monitor\"", `[[views]] x: unknown view "monitor"`},
+		{"duplicate saved view", "[[views]]\nname = \"x\"\nview = \"images\"\n[[views]]\nname = \"x\"\nview = \"volumes\"", `duplicate view "x"`},
+		{"saved view columns", "[[views]]\nname = \"x\"\nview = \"compose\"\ncolumns = [\"name\"]", "the compose view takes no columns"},
+		{"syntax", `theme = `, "reading"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfigFile(writeConfigFile(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDefaultConfigFile_HonoursXDG(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	if got := DefaultConfigFile(); got != "/tmp/xdg/dry/config.toml" {
+		t.Fatalf("unexpected path %q", got)
+	}
+}
+
+func TestParseKeystroke_RoundTrips(t *testing.T) {
+	for _, k := range []string{"l", "L", "%", "+", "ctrl+e", "alt+x", "f1", "f10", "enter", "esc", "space", "shift+tab", "ctrl+alt+up"} {
+		msg, err := parseKeystroke(k)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", k, err)
+		}
+		if msg.String() != k {
+			t.Fatalf("%q: round-tripped to %q", k, msg.String())
+		}
+	}
+}
+
+func TestModel_KeyOverridesSwapKeys(t *testing.T) {
+	m := NewModel(Config{Keys: map[string]string{"2": "3", "3": "2"}})
+	m.daemon = newTestModel().daemon
+	m.ready = true
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: '3', Text: "3"})
+	m = result.(model)
+	if m.view != Images {
+		t.Fatalf("expected 3 to open images, got view %d", m.view)
+	}
+	result, _ = m.Update(tea.KeyPressMsg{Code: '2', Text: "2"})
+	m = result.(model)
+	if m.view != Networks {
+		t.Fatalf("expected 2 to open networks, got view %d", m.view)
+	}
+}
+
+func TestModel_KeyOverridesReleaseBuiltinKey(t *testing.T) {
+	m := NewModel(Config{Keys: map[string]string{"2": "I"}})
+	m.daemon = newTestModel().daemon
+	m.ready = true
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: '2', Text: "2"})
+	m = result.(model)
+	if m.view != Main {
+		t.Fatalf("expected 2 to be released, got view %d", m.view)
+	}
+	result, _ = m.Update(tea.KeyPressMsg{Code: 'i', Text: "I", Mod: tea.ModShift})
+	m = result.(model)
+	if m.view != Images {
+		t.Fatalf("expected I to open images, got view %d", m.view)
+	}
+	if got := m.keyOverrides.rebind([]key.Binding{globalKeys.Images})[0].Help().Key; got != "I" {
+		t.Fatalf("expected footer to show I, got %q", got)
+	}
+}
+
+func TestModel_StartViewAndSortFromConfig(t *testing.T) {
+	m := NewModel(Config{
+		StartView: "services",
+		Sort:      map[string]string{"containers": "name", "images": "size", "volumes": "bogus"},
+	})
+	if got := m.startView(); got != Main {
+		t.Fatalf("expected swarm start view to fall back to Main, got %d", got)
+	}
+	m.swarmMode = true
+	if got := m.startView(); got != Services {
+		t.Fatalf("expected Services start view, got %d", got)
+	}
+	if m.containers.SortMode() != docker.SortByName {
+		t.Fatalf("expected containers sorted by name, got %d", m.containers.SortMode())
+	}
+	if msg := m.messageBar.Message(); !strings.Contains(msg, `volumes="bogus"`) {
+		t.Fatalf("expected unknown sort column reported, got %q", msg)
+	}
+}
+
+func TestModel_ColumnsFromConfig(t *testing.T) {
+	m := NewModel(Config{
+		Columns: map[string][]string{
+			"containers": {"names", "image:30", "labels"},
+			"nodes":      {"hostname", "address"},
+		},
+	})
+	got := m.containers.ColumnChoices()
+	if len(got) != 3 || got[1].Key != "image" || got[1].Width != 30 {
+		t.Fatalf("unexpected container columns %+v", got)
+	}
+	if got := m.nodes.ColumnChoices(); len(got) != 2 || got[1].Key != "address" {
+		t.Fatalf("unexpected node columns %+v", got)
+	}
+	if msg := m.messageBar.Message(); msg != "" {
+		t.Fatalf("expected no config message, got %q", msg)
+	}
+}
+
+func TestModel_ColumnPickerAppliesChoices(t *testing.T) {
+	m := NewModel(Config{})
+	m.view = Images
+	m2, _ := m.openColumnPicker()
+	m = m2.(model)
+	if m.overlay != overlayColumnPicker {
+		t.Fatalf("expected the column picker, got overlay %d", m.overlay)
+	}
+	m2, _ = m.Update(appui.ColumnsChosenMsg{Choices: appui.DefaultColumnChoices("size", "id")})
+	m = m2.(model)
+	if m.overlay != overlayNone {
+		t.Fatal("expected the picker to close")
+	}
+	if got := m.images.ColumnChoices(); len(got) != 2 || got[0].Key != "size" {
+		t.Fatalf("unexpected image columns %+v", got)
+	}
+
+	m.view = Monitor
+	if m2, _ = m.openColumnPicker(); m2.(model).overlay != overlayNone {
+		t.Fatal("expected no column picker for the monitor")
+	}
+}
+
+func TestModel_FormatFromConfig(t *testing.T) {
+	m := NewModel(Config{
+		Columns: map[string][]string{"images": {"size"}},
+		Formats: map[string]string{"images": `table {{.Repository}}:{{.Tag}}\t{{.Size}}`},
+	})
+	m.images.SetSize(120, 30)
+	if got := m.images.View(); !strings.Contains(got, "REPOSITORY:TAG") {
+		t.Fatalf("expected the format's headers to win over the columns, got %q", got)
+	}
+}
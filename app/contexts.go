@@ -0,0 +1,234 @@
+package app
+
+// Docker context switching: listing the endpoints dry can talk to, and
+// swapping the daemon the model is bound to.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+// defaultContextName names the endpoint dry was started with, the same
+// name the Docker CLI gives to its implicit context.
+const defaultContextName = "default"
+
+// dockerContexts returns the endpoints dry can switch to: the one it was
+// started with, the hosts from the configuration file and the Docker CLI
+// contexts, in that order. When names collide the first one wins.
+func (m model) dockerContexts() ([]docker.Context, error) {
+	contexts := []docker.Context{{
+		Name:        defaultContextName,
+		Description: "endpoint dry was started with",
+		Env: docker.Env{
+			DockerHost:      m.config.DockerHost,
+			DockerCertPath:  m.config.DockerCertPath,
+			DockerTLSVerify: m.config.DockerTLSVerify,
+		},
+	}}
+	for _, h := range m.config.Hosts {
+		certPath, err := homedir.Expand(h.CertPath)
+		if err != nil {
+			certPath = h.CertPath
+		}
+		contexts = append(contexts, docker.Context{
+			Name:        h.Name,
+			Description: "dry configuration",
+			Env: docker.Env{
+				DockerHost:      h.Host,
+				DockerCertPath:  certPath,
+				DockerTLSVerify: h.TLSVerify,
+			},
+		})
+	}
+	cliContexts, err := docker.DockerCLIContexts()
+	seen := make(map[string]bool, len(contexts))
+	for _, c := range contexts {
+		seen[c.Name] = true
+	}
+	for _, c := range cliContexts {
+		if !seen[c.Name] {
+			contexts = append(contexts, c)
+			seen[c.Name] = true
+		}
+	}
+	return contexts, err
+}
+
+// activeContextName returns the name of the Docker context in use.
+func (m model) activeContextName() string {
+	if m.contextName == "" {
+		return defaultContextName
+	}
+	return m.contextName
+}
+
+// openContextPicker shows the Docker contexts in a palette of their own.
+func (m model) openContextPicker() (tea.Model, tea.Cmd) {
+	contexts, err := m.dockerContexts()
+	if err != nil {
+		m.messageBar.SetMessage(fmt.Sprintf("Docker contexts: %s", err), 5*time.Second)
+	}
+	items := make([]appui.CommandPaletteItem, len(contexts))
+	for i, c := range contexts {
+		items[i] = appui.CommandPaletteItem{
+			ID:          "context:" + c.Name,
+			Group:       "Context",
+			Title:       c.Name,
+			Description: contextDescription(c, c.Name == m.activeContextName()),
+			Search:      c.Name + " " + c.Env.DockerHost,
+		}
+	}
+	palette, cmd := appui.NewCommandPaletteModel(items)
+	palette.SetPlaceholder("Switch Docker context")
+	palette.SetSize(m.width, m.height)
+	m.commandPalette = palette
+	m.overlay = overlayCommandPalette
+	return m, cmd
+}
+
+func contextDescription(c docker.Context, active bool) string {
+	parts := []string{c.Env.DockerHost}
+	if c.Description != "" {
+		parts = append(parts, c.Description)
+	}
+	if active {
+		parts = append(parts, "active")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// switchContext connects to the named Docker context. The current daemon
+// stays in use until the new one answers, so a context that cannot be
+// reached leaves dry where it was.
+func (m model) switchContext(name string) (tea.Model, tea.Cmd) {
+	if name == m.activeContextName() {
+		return m, nil
+	}
+	contexts, _ := m.dockerContexts()
+	for _, c := range contexts {
+		if c.Name == name {
+			m.messageBar.SetMessage(fmt.Sprintf("Connecting to %s ...", name), 30*time.Second)
+			return m, connectToContextCmd(c)
+		}
+	}
+	return m, func() tea.Msg {
+		return statusMessageMsg{
+			text:   fmt.Sprintf("Unknown Docker context %s", name),
+			expiry: 5 * time.Second,
+		}
+	}
+}
+
+// connectToContextCmd connects to the given Docker context asynchronously.
+func connectToContextCmd(c docker.Context) tea.Cmd {
+	return func() tea.Msg {
+		daemon, err := docker.ConnectToDaemon(c.Env)
+		if err != nil {
+			return statusMessageMsg{
+				text:   fmt.Sprintf("Could not connect to %s: %s", c.Name, err),
+				expiry: 8 * time.Second,
+			}
+		}
+		return contextConnectedMsg{name: c.Name, daemon: daemon}
+	}
+}
+
+// useDaemon binds the model to the given daemon: header, monitor, event
+// listener and the data of the view shown. On the initial connection the
+// configured start view is shown, after a context switch the containers.
+func (m model) useDaemon(daemon dockerDaemon, initial bool) (tea.Model, tea.Cmd) {
+	m.daemon = daemon
+	m.swarmMode = false
+	if info, err := m.daemon.Info(); err == nil {
+		m.swarmMode = info.Swarm.LocalNodeState == swarm.LocalNodeStateActive
+	}
+	m.monitor.SetDaemon(m.daemon)
+	m.tasks.SetDaemon(m.daemon)
+	m.resizeContentModels()
+	m.header = appui.NewHeaderModel(m.daemon, m.width)
+	eventsCtx, eventsCancel := context.WithCancel(context.Background())
+	eventsCh, err := m.daemon.Events(eventsCtx)
+	if err != nil {
+		eventsCancel()
+		m.messageBar.SetMessage(fmt.Sprintf("Docker events error: %s", err), 5*time.Second)
+		return m, tea.Batch(
+			loadContainersCmd(m.daemon, m.containers.ShowAll(), m.containers.SortMode()),
+			loadHeaderInfoCmd(m.daemon),
+			detectComposeCmd(m.daemon.DockerEnv()),
+		)
+	}
+	m.eventsChan = eventsCh
+	m.eventsCancel = eventsCancel
+	start := Main
+	if initial {
+		start = m.startView()
+	}
+	if start != Main {
+		m2, cmd := m.switchView(start)
+		return m2, tea.Batch(cmd, listenDockerEvents(m.eventsChan), loadHeaderInfoCmd(m.daemon), detectComposeCmd(m.daemon.DockerEnv()))
+	}
+	return m, tea.Batch(
+		loadContainersCmd(m.daemon, m.containers.ShowAll(), m.containers.SortMode()),
+		listenDockerEvents(m.eventsChan),
+		loadHeaderInfoCmd(m.daemon),
+		detectComposeCmd(m.daemon.DockerEnv()),
+	)
+}
+
+// releaseDaemon stops everything bound to the current daemon (event
+// listener, monitor stats streams, log readers, the all-hosts view), drops the data shown from
+// it and closes it, leaving the model on the containers view ready for
+// useDaemon.
+func (m *model) releaseDaemon() {
+	m.releaseHosts()
+	m.monitor.StopAll()
+	if m.eventsCancel != nil {
+		m.eventsCancel()
+		m.eventsCancel = nil
+	}
+	m.eventsChan = nil
+	if m.streamReader != nil {
+		_ = m.streamReader.Close()
+		m.streamReader = nil
+	}
+	m.closeActivityReader()
+	m.overlay = overlayNone
+	m.eventsLive = false
+	m.healthID = ""
+	m.cancelFilesLoad()
+	m.files = appui.FilesModel{}
+	m.pinnedContext = nil
+	m.selectedProject = ""
+	m.composeCLI = nil
+	m.composeRefreshPending = false
+	m.pendingRefresh = make(map[docker.SourceType]bool)
+
+	m.containers.SetContainers(nil)
+	m.images.SetImages(nil)
+	m.networks.SetNetworks(nil)
+	m.volumes.SetVolumes(nil)
+	m.nodes.SetNodes(nil)
+	m.services.SetServices(nil)
+	m.stacks.SetStacks(nil)
+	m.tasks.SetTasks(nil, "")
+	m.composeProjects.SetProjects(nil)
+	m.composeServices.SetServices(nil, nil, nil, "")
+	m.diskUsage = appui.NewDiskUsageModel()
+
+	if m.daemon != nil {
+		_ = m.daemon.Close()
+	}
+	m.previousView = Main
+	m.view = Main
+	if m.workspaceEnabled() {
+		m.resetWorkspaceActivity()
+	}
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moby/moby/api/types/events"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/mocks"
+)
+
+type closeRecordingDaemon struct {
+	mocks.DockerDaemonMock
+	closed bool
+}
+
+func (d *closeRecordingDaemon) Close() error {
+	d.closed = true
+	return nil
+}
+
+func TestModel_DockerContextsListsConfigAndCLIContexts(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	metaDir := filepath.Join(dir, "contexts", "meta", "abc")
+	if err := os.MkdirAll(metaDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	meta := `{"Name":"staging","Endpoints":{"docker":{"Host":"tcp://cli-staging:2375"}}}`
+	if err := os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	meta = `{"Name":"remote","Endpoints":{"docker":{"Host":"ssh://me@remote"}}}`
+	metaDir = filepath.Join(dir, "contexts", "meta", "def")
+	if err := os.MkdirAll(metaDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(Config{
+		DockerHost: "unix:///var/run/docker.sock",
+		Hosts:      []HostConfig{{Name: "staging", Host: "tcp://staging:2376", TLSVerify: true}},
+	})
+	contexts, err := m.dockerContexts()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names, hosts []string
+	for _, c := range contexts {
+		names = append(names, c.Name)
+		hosts = append(hosts, c.Env.DockerHost)
+	}
+	want := []string{"default", "staging", "remote"}
+	if len(names) != len(want) {
+		t.Fatalf("expected contexts %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("expected contexts %v, got %v", want, names)
+		}
+	}
+	if hosts[1] != "tcp://staging:2376" {
+		t.Fatalf("expected the configured host to win over the CLI context, got %q", hosts[1])
+	}
+}
+
+func TestModel_ContextSwitchReleasesOldDaemon(t *testing.T) {
+	m := newTestModel()
+	old := &closeRecordingDaemon{}
+	m.daemon = old
+	staleEvents := make(chan events.Message)
+	m.eventsChan = staleEvents
+	eventsCancelled := false
+	m.eventsCancel = func() { eventsCancelled = true }
+	m.view = Images
+	m.containers.SetContainers([]*docker.Container{{}})
+
+	result, _ := m.Update(contextConnectedMsg{name: "remote", daemon: &mocks.DockerDaemonMock{}})
+	m = result.(model)
+
+	if !old.closed {
+		t.Fatal("expected the old daemon to be closed")
+	}
+	if !eventsCancelled {
+		t.Fatal("expected the old event listener to be cancelled")
+	}
+	if m.daemon == old {
+		t.Fatal("expected the new daemon to be in use")
+	}
+	if m.view != Main {
+		t.Fatalf("expected containers view after a switch, got %d", m.view)
+	}
+	if m.containers.SelectedContainer() != nil {
+		t.Fatal("expected containers of the old daemon to be dropped")
+	}
+	if m.activeContextName() != "remote" {
+		t.Fatalf("expected active context remote, got %q", m.activeContextName())
+	}
+
+	// A close notice from the old listener must not start a reconnect
+	// against the new daemon.
+	_, cmd := m.Update(eventsClosedMsg{ch: staleEvents})
+	if cmd != nil {
+		t.Fatal("expected stale events close to be ignored")
+	}
+}
+
+func TestModel_PickingActiveContextIsNoop(t *testing.T) {
+	m := newTestModel()
+	_, cmd := m.executePaletteAction("context:default")
+	if cmd != nil {
+		t.Fatal("expected no reconnect when picking the active context")
+	}
+}
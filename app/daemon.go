@@ -16,5 +16,6 @@ type dockerDaemon interface {
 	docker.ComposeAPI
 	docker.ComposeActionsAPI
 	docker.ContainerRuntime
+	docker.ContainerFilesAPI
 	docker.SystemAPI
 }
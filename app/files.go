@@ -0,0 +1,205 @@
+package app
+
+// Files browser: the filesystem of a container as a tree, or the changes
+// made to it, with its files viewed and copied from and to the host.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/mitchellh/go-homedir"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+// fileViewLimit is how much of a file is shown.
+const fileViewLimit = 1024 * 1024
+
+// filesTreeMsg carries the filesystem of a container.
+type filesTreeMsg struct {
+	id   string
+	tree *docker.FileTree
+	err  error
+}
+
+// filesChangesMsg carries the changes made to the filesystem of a container.
+type filesChangesMsg struct {
+	id      string
+	changes []container.FilesystemChange
+	err     error
+}
+
+// filesNoticeMsg tells something about the browsed files, such as why a
+// file could not be shown.
+type filesNoticeMsg struct {
+	text string
+}
+
+// showFiles opens the files browser of the given container.
+func (m model) showFiles(c *docker.Container) (model, tea.Cmd) {
+	if c == nil {
+		return m, nil
+	}
+	m.files = appui.NewFilesModel(c.ID, fmt.Sprintf("Files: %s", paletteContainerLabel(c)))
+	m.files.SetSize(m.width, m.height)
+	m.overlay = overlayFiles
+	return m, m.loadFiles(c.ID)
+}
+
+// filesOpen returns true if the files browser is open, under the overlay
+// shown if any.
+func (m model) filesOpen() bool {
+	return m.files.ContainerID() != ""
+}
+
+// closeFilesOverlay closes the overlay shown on top of the files browser,
+// going back to it, or the browser itself.
+func (m *model) closeFilesOverlay() {
+	if m.overlay == overlayFiles {
+		m.cancelFilesLoad()
+		m.files = appui.FilesModel{}
+		m.overlay = overlayNone
+		return
+	}
+	m.overlay = overlayFiles
+}
+
+// loadFiles reads the filesystem of the container with the given id, and
+// its changes, stopping any read still going on. The read is stopped when
+// the browser is closed.
+func (m *model) loadFiles(id string) tea.Cmd {
+	m.cancelFilesLoad()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.filesCancel = cancel
+	return loadFilesCmd(ctx, m.daemonFor(id), id)
+}
+
+// cancelFilesLoad stops reading the filesystem of the browsed container.
+func (m *model) cancelFilesLoad() {
+	if m.filesCancel != nil {
+		m.filesCancel()
+		m.filesCancel = nil
+	}
+}
+
+func loadFilesCmd(ctx context.Context, daemon docker.ContainerFilesAPI, id string) tea.Cmd {
+	return tea.Batch(
+		func() tea.Msg {
+			tree, err := daemon.ContainerFileTree(ctx, id)
+			return filesTreeMsg{id: id, tree: tree, err: err}
+		},
+		func() tea.Msg {
+			changes, err := daemon.ContainerChanges(id)
+			return filesChangesMsg{id: id, changes: changes, err: err}
+		},
+	)
+}
+
+func viewContainerFileCmd(daemon docker.ContainerFilesAPI, id, p string) tea.Cmd {
+	return func() tea.Msg {
+		content, err := daemon.ReadContainerFile(id, p, fileViewLimit)
+		if err != nil {
+			return filesNoticeMsg{text: fmt.Sprintf("Error reading %s: %s", p, err)}
+		}
+		if bytes.IndexByte(content, 0) >= 0 {
+			return filesNoticeMsg{text: fmt.Sprintf("%s is a binary file, copy it to the host to see it", p)}
+		}
+		title := p
+		if len(content) == fileViewLimit {
+			title += " (first 1MB)"
+		}
+		return showLessMsg{content: string(content), title: title}
+	}
+}
+
+// showCopyOutPrompt asks for the host directory to copy a file of the
+// browsed container to.
+func (m model) showCopyOutPrompt(p string) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.inputPrompt, cmd = appui.NewInputPromptModelWithLimit(
+		fmt.Sprintf("Copy %s to the host directory:", p),
+		m.workingDir, "files-copy-out", p, 1024,
+	)
+	m.inputPrompt.SetSize(m.width, m.height)
+	m.overlay = overlayInputPrompt
+	return m, cmd
+}
+
+// showCopyInPrompt asks for the host file or directory to copy into a
+// directory of the browsed container.
+func (m model) showCopyInPrompt(dir string) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.inputPrompt, cmd = appui.NewInputPromptModelWithLimit(
+		fmt.Sprintf("Copy into %s the host file or directory:", dir),
+		"path on the host", "files-copy-in", dir, 1024,
+	)
+	m.inputPrompt.SetSize(m.width, m.height)
+	m.overlay = overlayInputPrompt
+	return m, cmd
+}
+
+// copyFilesCmd copies between the browsed container and the host, as a
+// transfer that can be cancelled: out copies the container path p into the
+// host directory hostPath, otherwise the host path is copied into the
+// container directory p, whose files are then read again.
+func (m model) copyFilesCmd(out bool, p, hostPath string) tea.Cmd {
+	id := m.files.ContainerID()
+	daemon := m.daemonFor(id)
+	if out && hostPath == "" {
+		hostPath = m.workingDir
+	}
+	if hostPath == "" {
+		return nil
+	}
+	hostPath, err := homedir.Expand(hostPath)
+	if err != nil {
+		return func() tea.Msg {
+			return filesNoticeMsg{text: fmt.Sprintf("Copy error: %s", err)}
+		}
+	}
+	if out {
+		return startTransfer(fmt.Sprintf("Copying %s", p), func(ctx context.Context, progress func(int64)) (string, error) {
+			if err := daemon.CopyFromContainer(ctx, id, p, hostPath, progress); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Copied %s to %s", p, hostPath), nil
+		})
+	}
+	return startFilesTransfer(fmt.Sprintf("Copying %s", hostPath), id, func(ctx context.Context, progress func(int64)) (string, error) {
+		if err := daemon.CopyToContainer(ctx, id, hostPath, p, progress); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Copied %s to %s", hostPath, path.Join(p, path.Base(hostPath))), nil
+	})
+}
+
+// updateFiles handles the messages of the files browser.
+func (m model) updateFiles(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case filesTreeMsg:
+		if msg.id == m.files.ContainerID() {
+			m.files.SetTree(msg.tree, msg.err)
+		}
+	case filesChangesMsg:
+		if msg.id == m.files.ContainerID() {
+			m.files.SetChanges(msg.changes, msg.err)
+		}
+	case filesNoticeMsg:
+		m.files.SetMessage(msg.text)
+	case appui.FilesReloadMsg:
+		if msg.ContainerID == m.files.ContainerID() {
+			return m, m.loadFiles(msg.ContainerID)
+		}
+	case appui.FileViewMsg:
+		return m, viewContainerFileCmd(m.daemonFor(msg.ContainerID), msg.ContainerID, msg.Path)
+	case appui.FileCopyOutMsg:
+		return m.showCopyOutPrompt(msg.Path)
+	case appui.FileCopyInMsg:
+		return m.showCopyInPrompt(msg.Dir)
+	}
+	return m, nil
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+// runCmd runs a command, and the commands of the batch it returns if any,
+// feeding their messages to the model.
+func runCmd(t *testing.T, m model, cmd tea.Cmd) model {
+	t.Helper()
+	if cmd == nil {
+		return m
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			m = runCmd(t, m, c)
+		}
+		return m
+	}
+	result, next := m.Update(msg)
+	return runCmd(t, result.(model), next)
+}
+
+func TestModel_FilesBrowser(t *testing.T) {
+	m := newTestModel()
+	m.containers.SetContainers([]*docker.Container{
+		{Summary: container.Summary{ID: "c1", Names: []string{"/web"}, Status: "Up 1 minute"}},
+	})
+
+	result, cmd := m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	m = runCmd(t, result.(model), cmd)
+	if m.overlay != overlayFiles || !strings.Contains(m.View().Content, "Files: web") {
+		t.Fatalf("expected f to open the files browser:\n%s", m.View().Content)
+	}
+
+	// Viewing a file goes back to the browser once closed.
+	result, cmd = m.Update(appui.FileViewMsg{ContainerID: "c1", Path: "/etc/hostname"})
+	m = runCmd(t, result.(model), cmd)
+	if m.overlay != overlayLess || !strings.Contains(m.less.View(), "dry-container") {
+		t.Fatal("expected the file to be shown")
+	}
+	result, _ = m.Update(appui.CloseOverlayMsg{})
+	m = result.(model)
+	if m.overlay != overlayFiles {
+		t.Fatal("expected to be back in the files browser")
+	}
+
+	// So does copying a file to the host.
+	result, _ = m.Update(appui.FileCopyOutMsg{ContainerID: "c1", Path: "/etc/hostname"})
+	m = result.(model)
+	if m.overlay != overlayInputPrompt {
+		t.Fatal("expected to be asked where to copy the file")
+	}
+	result, cmd = m.Update(appui.InputPromptResultMsg{Tag: "files-copy-out", ID: "/etc/hostname", Value: "/tmp"})
+	m = runCmd(t, result.(model), cmd)
+	if m.overlay != overlayFiles || !strings.Contains(m.View().Content, "Copied /etc/hostname to /tmp") {
+		t.Fatalf("expected the copy to be reported in the browser:\n%s", m.View().Content)
+	}
+
+	result, _ = m.Update(appui.CloseOverlayMsg{})
+	m = result.(model)
+	if m.overlay != overlayNone || m.filesOpen() {
+		t.Fatal("expected the browser to be closed")
+	}
+}
+
+func TestModel_FilesBrowserCancelsCopies(t *testing.T) {
+	m := newTestModel()
+	m.containers.SetContainers([]*docker.Container{
+		{Summary: container.Summary{ID: "c1", Names: []string{"/web"}, Status: "Up 1 minute"}},
+	})
+	result, cmd := m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	m = runCmd(t, result.(model), cmd)
+
+	cmd = startFilesTransfer("Copying /tmp/app", "c1", func(ctx context.Context, _ func(int64)) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	result, cmd = m.Update(cmd())
+	m = result.(model)
+	result, _ = m.Update(tea.KeyPressMsg{Code: 'x', Mod: tea.ModCtrl})
+	m = runCmd(t, result.(model), cmd)
+	if m.overlay != overlayFiles || len(m.transfers) != 0 || !strings.Contains(m.View().Content, "Copying /tmp/app cancelled") {
+		t.Fatalf("expected the copy to be cancelled from the browser:\n%s", m.View().Content)
+	}
+}
@@ -0,0 +1,142 @@
+package app
+
+// Health panel: the health check of a container, its recent probes and
+// failing streak, kept current from health_status events while shown.
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+// showContainerHealthCmd inspects a container and shows its health. With
+// refresh, the panel already open is updated instead.
+func showContainerHealthCmd(daemon docker.ContainerAPI, id string, refresh bool) tea.Cmd {
+	return func() tea.Msg {
+		info, err := daemon.Inspect(id)
+		if err != nil {
+			if refresh {
+				return nil
+			}
+			return statusMessageMsg{
+				text:   fmt.Sprintf("Inspect error: %s", err),
+				expiry: 5 * time.Second,
+			}
+		}
+		return healthMsg{
+			id:      id,
+			title:   fmt.Sprintf("Health: %s", strings.TrimPrefix(info.Name, "/")),
+			content: healthReport(info),
+			refresh: refresh,
+		}
+	}
+}
+
+// showHealth opens the health panel, or updates the one open.
+func (m model) showHealth(msg healthMsg) (tea.Model, tea.Cmd) {
+	if msg.refresh {
+		if m.overlay == overlayLess && m.healthID == msg.id {
+			m.less.ReplaceContent(msg.content)
+		}
+		return m, nil
+	}
+	m.logSource = nil
+	m.eventsLive = false
+	m.less = appui.NewLessModel()
+	m.less.SetSize(m.width, m.height)
+	m.less.SetContent(msg.content, msg.title)
+	m.overlay = overlayLess
+	m.healthID = msg.id
+	return m, nil
+}
+
+// healthEvent updates the health panel open on the container of a
+// health_status event.
+func (m model) healthEvent(e events.Message) tea.Cmd {
+	if m.healthID == "" || m.overlay != overlayLess || e.Type != events.ContainerEventType ||
+		e.Actor.ID != m.healthID || !strings.HasPrefix(string(e.Action), "health_status") {
+		return nil
+	}
+	return showContainerHealthCmd(m.containerAPI(), m.healthID, true)
+}
+
+// healthReport describes the health check of a container and the result
+// of its last probes, newest first.
+func healthReport(info container.InspectResponse) string {
+	var b strings.Builder
+	var check *container.HealthConfig
+	if info.Config != nil {
+		check = info.Config.Healthcheck
+	}
+	if check == nil || len(check.Test) == 0 || check.Test[0] == "NONE" {
+		b.WriteString("No health check is configured for this container.\n")
+		return b.String()
+	}
+	b.WriteString("Health check:\n")
+	test := check.Test
+	switch test[0] {
+	case "CMD-SHELL":
+		fmt.Fprintf(&b, "  test:           %s\n", strings.Join(test[1:], " "))
+	case "CMD":
+		fmt.Fprintf(&b, "  test:           %q\n", test[1:])
+	default:
+		fmt.Fprintf(&b, "  test:           %q\n", test)
+	}
+	fmt.Fprintf(&b, "  interval:       %s\n", healthDuration(check.Interval, 30*time.Second))
+	fmt.Fprintf(&b, "  timeout:        %s\n", healthDuration(check.Timeout, 30*time.Second))
+	fmt.Fprintf(&b, "  start period:   %s\n", healthDuration(check.StartPeriod, 0))
+	if check.StartInterval > 0 {
+		fmt.Fprintf(&b, "  start interval: %s\n", check.StartInterval)
+	}
+	retries := check.Retries
+	if retries == 0 {
+		retries = 3
+	}
+	fmt.Fprintf(&b, "  retries:        %d\n", retries)
+
+	var health *container.Health
+	if info.State != nil {
+		health = info.State.Health
+	}
+	if health == nil {
+		b.WriteString("\nNo probe has run yet.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\nStatus:         %s\n", health.Status)
+	fmt.Fprintf(&b, "Failing streak: %d\n", health.FailingStreak)
+	fmt.Fprintf(&b, "\nProbes (%d, newest first):\n", len(health.Log))
+	for i := len(health.Log) - 1; i >= 0; i-- {
+		probe := health.Log[i]
+		if probe == nil {
+			continue
+		}
+		result := "ok"
+		if probe.ExitCode != 0 {
+			result = "failed"
+		}
+		fmt.Fprintf(&b, "\n  %s  exit %d (%s) in %s\n",
+			probe.Start.Local().Format("2006-01-02 15:04:05"), probe.ExitCode, result,
+			probe.End.Sub(probe.Start).Round(time.Millisecond))
+		for _, line := range strings.Split(strings.TrimRight(probe.Output, "\n"), "\n") {
+			if line != "" {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// healthDuration formats a duration of a health check, zero meaning the
+// default of the daemon.
+func healthDuration(d, def time.Duration) string {
+	if d == 0 {
+		return fmt.Sprintf("%s (default)", def)
+	}
+	return d.String()
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+)
+
+func TestHealthReport(t *testing.T) {
+	start := time.Date(2026, 5, 1, 10, 0, 0, 0, time.Local)
+	info := container.InspectResponse{
+		Config: &container.Config{Healthcheck: &container.HealthConfig{
+			Test:     []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"},
+			Interval: 10 * time.Second,
+		}},
+		State: &container.State{Health: &container.Health{
+			Status:        container.Unhealthy,
+			FailingStreak: 2,
+			Log: []*container.HealthcheckResult{
+				{Start: start, End: start.Add(40 * time.Millisecond), ExitCode: 0, Output: "ok\n"},
+				{Start: start.Add(10 * time.Second), End: start.Add(10*time.Second + 2*time.Second), ExitCode: 1, Output: "curl: (7) Failed to connect\n"},
+			},
+		}},
+	}
+	report := healthReport(info)
+	for _, want := range []string{
+		"test:           curl -f http://localhost/ || exit 1",
+		"interval:       10s",
+		"timeout:        30s (default)",
+		"retries:        3",
+		"Status:         unhealthy",
+		"Failing streak: 2",
+		"2026-05-01 10:00:10  exit 1 (failed) in 2s\n    curl: (7) Failed to connect",
+	} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("expected %q in the report:\n%s", want, report)
+		}
+	}
+	if strings.Index(report, "exit 1") > strings.Index(report, "exit 0") {
+		t.Fatalf("expected the newest probe first:\n%s", report)
+	}
+
+	if report := healthReport(container.InspectResponse{Config: &container.Config{}}); !strings.Contains(report, "No health check") {
+		t.Fatalf("unexpected report without health check:\n%s", report)
+	}
+}
+
+func TestModel_HealthPanelFollowsHealthEvents(t *testing.T) {
+	m := newTestModel()
+	result, _ := m.Update(healthMsg{id: "abc", title: "Health: web", content: "Status: starting"})
+	m = result.(model)
+	if m.overlay != overlayLess || m.healthID != "abc" {
+		t.Fatal("expected the health panel to be open")
+	}
+
+	ch := make(chan events.Message)
+	m.eventsChan = ch
+	ev := events.Message{Type: events.ContainerEventType, Action: "health_status: healthy", Actor: events.Actor{ID: "abc"}}
+	if cmd := m.healthEvent(ev); cmd == nil {
+		t.Fatal("expected a health event of the container to update the panel")
+	}
+	ev.Actor.ID = "other"
+	if cmd := m.healthEvent(ev); cmd != nil {
+		t.Fatal("expected events of other containers to be ignored")
+	}
+
+	result, _ = m.Update(healthMsg{id: "abc", content: "Status: healthy", refresh: true})
+	m = result.(model)
+	if !strings.Contains(m.less.View(), "Status: healthy") {
+		t.Fatalf("expected the panel to be updated:\n%s", m.less.View())
+	}
+}
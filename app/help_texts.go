@@ -30,12 +30,18 @@ Visit <blue>http://moncho.github.io/dry/</> for more information.
 	<white>7</>         To stack list (in Swarm mode)
 	<white>8</>         To compose projects list
 	<white>m</>         Show container monitor mode
+	<white>w</>         In monitor mode, changes the span of time charts show (with --metrics-history)
+	<white>g</>         In monitor mode, switches charts between CPU/memory, network, block IO and PIDs
+	<white>a</>         In monitor mode, lists the alert rules and the alerts fired
 	<white>h</>         Shows this help screen
 	<white>Ctrl+c</>    Quits <white>dry</> immediately
 	<white>Q</>         Quits <white>dry</>
 	<white>esc</>       Goes back to the main screen
 	<white>Ctrl+0</>    Cycles color theme (dark/light)
 	<white>:</>         Opens the command palette
+	<white>C</>         Switches to another Docker context or configured host
+	<white>Ctrl+o</>    Chooses, reorders and sizes the columns of the list shown
+	<white>Ctrl+x</>    Cancels the archive transfers and file copies in progress
 	<white>Space</>     Opens Quick Peek for the current selection
 	<white>Tab</>       Moves workspace focus forward between navigator, context, and activity
 	<white>Shift+Tab</> Moves workspace focus backward between navigator, context, and activity
@@ -45,18 +51,30 @@ Visit <blue>http://moncho.github.io/dry/</> for more information.
 <yellow>Global list keybinds</>	
 	<white>F1</>        Cycles through sort modes
 	<white>F5</>        Refreshes the list
-	<white>%</>         Filter
+	<white>%</>         Filters by text and field=value, field~value, label=key=value or since=2h terms
+	<white>v</>         Marks or unmarks the selected row (Esc unmarks all)
+	<white>V</>         Marks the rows from the last one marked to the selected one
+	<white>*</>         Marks all the rows shown, or unmarks them if all are marked
+	            Removing, stopping, restarting, killing, pausing and scaling run on the marked rows when there are any
 
 <yellow>Container list keybinds</>
 	<white>F2</>        Toggles showing all containers (default shows just running)
+	<white>F3</>        Toggles showing only unhealthy containers
+	<white>c</>         Shows the health check of the selected container and its last probes
 	<white>e</>         Removes the selected container
+	<white>f</>         Browses the filesystem of the selected container, and the changes made to it
 	<white>Ctrl+e</>    Removes all stopped containers
 	<white>Ctrl+k</>    Kills the selected container
-	<white>l</>         Displays the logs of the selected container
+	<white>l</>         Displays the logs of the selected container, or the merged logs of the marked ones
 	<white>Ctrl+r</>    Restarts selected container
 	<white>s</>         Displays resource usage statistics of the selected container
 	<white>Ctrl+t</>    Stops selected container (noop if it is not running)
 	<white>x</>         Exec a command in the selected container (default /bin/sh)
+	<white>z</>         Pauses the selected container, or unpauses it if it is paused
+	<white>R</>         Renames the selected container
+	<white>u</>         Updates the resource limits and restart policy of the selected container
+	<white>I</>         Commits the selected container to a new image
+	<white>X</>         Exports the filesystem of the selected container to a tar file
 	<white>Enter</>     Opens the command menu for the selected container (includes Attach and Exec)
 
 <yellow>Image list keybinds</>
@@ -64,7 +82,10 @@ Visit <blue>http://moncho.github.io/dry/</> for more information.
 	<white>Ctrl+e</>    Removes the selected image
 	<white>Ctrl+f</>    Forces removal of the selected image
 	<white>Ctrl+u</>    Removes unused images
+	<white>r</>         Runs a container of the selected image, with the options of docker run
 	<white>i</>         Shows image history
+	<white>s</>         Saves the selected image, or the marked ones, to a tar archive
+	<white>l</>         Loads images from a tar archive
 	<white>Enter</>     Shows low-level information of the selected image
 
 <yellow>Network list keybinds</>
@@ -139,7 +160,21 @@ Visit <blue>http://moncho.github.io/dry/</> for more information.
 
 <yellow>Move around in logs/inspect buffers</>
 	<white>/</>         Searches for a pattern
-	<white>F</>         Only show lines that matches a pattern
+	<white>F</>         Only show lines that matches a pattern, or hide them with !pattern
+	<white>R</>         Toggles between plain text and regular expression patterns
+	<white>i</>         Toggles between case-insensitive and case-sensitive patterns
+	<white>w</>         Writes the lines shown to a new file (tab keeps ANSI escape codes)
+	<white>|</>         Pipes the lines shown to a shell command (tab keeps ANSI escape codes)
+
+<yellow>Container logs</>
+	<white>1/2/3</>     Shows the logs of the last 5 minutes, hour or 24 hours
+	<white>r</>         Shows a range of logs: all, the last lines (500), since (2h) or since..until
+	<white>t</>         Toggles timestamps
+	<white>s</>         Toggles streaming new log lines
+	<white>+/-</>       Adds or removes a container from merged logs
+	<white>e</>         Shows stdout and stderr lines, stdout only or stderr only
+	<white>J</>         Toggles between JSON/logfmt lines as written and as time level msg key=val
+	<white>F</>         Also filters JSON/logfmt lines by field: level>=warn, trace_id=abc, key!=val, key~text
 	<white>f</>         Toggles follow mode (auto-scroll to bottom)
 	<white>g</>         Moves the cursor to the beginning
 	<white>G</>         Moves the cursor until the end
@@ -0,0 +1,190 @@
+package app
+
+// The all-hosts view: containers from every known Docker host listed
+// together, with container operations routed to the daemon owning them.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moncho/dry/docker"
+)
+
+// containerAPI returns the containers dry works with: those of every host
+// while the all-hosts view is on, those of the current daemon otherwise.
+func (m model) containerAPI() docker.ContainerAPI {
+	if m.hosts != nil {
+		return m.hosts
+	}
+	return m.daemon
+}
+
+// daemonFor returns the daemon owning the container with the given id.
+func (m model) daemonFor(id string) dockerDaemon {
+	if m.hosts != nil && id != "" {
+		if host, ok := m.hosts.HostOf(id); ok {
+			return m.hostDaemons[host]
+		}
+	}
+	return m.daemon
+}
+
+// toggleAllHosts turns the all-hosts view on, connecting to every known
+// host other than the current one, or off, releasing those connections.
+func (m model) toggleAllHosts() (tea.Model, tea.Cmd) {
+	if m.daemon == nil {
+		return m, nil
+	}
+	if m.hosts != nil {
+		m.releaseHosts()
+		m.messageBar.SetMessage(fmt.Sprintf("Showing containers from %s", m.activeContextName()), 3*time.Second)
+		return m, m.loadViewData(m.view)
+	}
+	contexts, err := m.dockerContexts()
+	if err != nil {
+		m.messageBar.SetMessage(fmt.Sprintf("Docker contexts: %s", err), 5*time.Second)
+	}
+	others := otherHosts(contexts, m.activeContextName())
+	if len(others) == 0 {
+		m.messageBar.SetMessage("No other Docker hosts configured", 5*time.Second)
+		return m, nil
+	}
+	m.messageBar.SetMessage(fmt.Sprintf("Connecting to %d hosts ...", len(others)), 30*time.Second)
+	return m, connectToHostsCmd(m.activeContextName(), others)
+}
+
+// otherHosts returns the contexts other than the active one, skipping
+// those pointing at an endpoint already listed, whose containers would
+// otherwise show up twice.
+func otherHosts(contexts []docker.Context, active string) []docker.Context {
+	endpoints := make(map[string]bool)
+	for _, c := range contexts {
+		if c.Name == active {
+			endpoints[c.Env.DockerHost] = true
+		}
+	}
+	var others []docker.Context
+	for _, c := range contexts {
+		if c.Name == active || endpoints[c.Env.DockerHost] {
+			continue
+		}
+		endpoints[c.Env.DockerHost] = true
+		others = append(others, c)
+	}
+	return others
+}
+
+// connectToHostsCmd connects to the given contexts concurrently.
+func connectToHostsCmd(active string, contexts []docker.Context) tea.Cmd {
+	return func() tea.Msg {
+		daemons := make([]dockerDaemon, len(contexts))
+		errs := make([]error, len(contexts))
+		var wg sync.WaitGroup
+		for i, c := range contexts {
+			wg.Go(func() {
+				daemon, err := docker.ConnectToDaemon(c.Env)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				daemons[i] = daemon
+			})
+		}
+		wg.Wait()
+
+		msg := hostsConnectedMsg{active: active, daemons: make(map[string]dockerDaemon)}
+		for i, c := range contexts {
+			if errs[i] != nil {
+				msg.failed = append(msg.failed, c.Name)
+				continue
+			}
+			msg.names = append(msg.names, c.Name)
+			msg.daemons[c.Name] = daemons[i]
+		}
+		return msg
+	}
+}
+
+// useHosts turns the all-hosts view on with the daemons just connected to.
+// Connections that are no longer wanted, because the view was turned on
+// twice or the context changed meanwhile, are closed right away.
+func (m model) useHosts(msg hostsConnectedMsg) (tea.Model, tea.Cmd) {
+	if m.daemon == nil || m.hosts != nil || msg.active != m.activeContextName() {
+		for _, d := range msg.daemons {
+			_ = d.Close()
+		}
+		return m, nil
+	}
+	if len(msg.names) == 0 {
+		m.messageBar.SetMessage(
+			fmt.Sprintf("Could not connect to %s", strings.Join(msg.failed, ", ")), 8*time.Second)
+		return m, nil
+	}
+
+	active := m.activeContextName()
+	hosts := []docker.ContainerHost{{Name: active, API: m.daemon}}
+	m.hostDaemons = map[string]dockerDaemon{active: m.daemon}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.hostsCancel = cancel
+	var cmds []tea.Cmd
+	for _, name := range msg.names {
+		daemon := msg.daemons[name]
+		hosts = append(hosts, docker.ContainerHost{Name: name, API: daemon})
+		m.hostDaemons[name] = daemon
+		// Each daemon keeps its container store up to date from its own
+		// event stream.
+		if ch, err := daemon.Events(ctx); err == nil {
+			cmds = append(cmds, listenHostEvents(ch))
+		}
+	}
+	m.hosts = docker.NewMultiHostContainers(hosts...)
+	m.containers.SetShowHost(true)
+
+	status := fmt.Sprintf("Showing containers from %d hosts", len(hosts))
+	if len(msg.failed) > 0 {
+		status += fmt.Sprintf(", could not connect to %s", strings.Join(msg.failed, ", "))
+	}
+	m.messageBar.SetMessage(status, 5*time.Second)
+	if m.view == Main {
+		cmds = append(cmds, loadContainersCmd(m.hosts, m.containers.ShowAll(), m.containers.SortMode()))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// releaseHosts turns the all-hosts view off, closing every daemon but the
+// current one.
+func (m *model) releaseHosts() {
+	if m.hosts == nil {
+		return
+	}
+	if m.hostsCancel != nil {
+		m.hostsCancel()
+		m.hostsCancel = nil
+	}
+	for _, d := range m.hostDaemons {
+		if d != m.daemon {
+			_ = d.Close()
+		}
+	}
+	m.hosts = nil
+	m.hostDaemons = nil
+	m.containers.SetShowHost(false)
+}
+
+// listenHostEvents blocks on the events channel of one of the all-hosts
+// daemons and returns the next event. The channel closes when the view is
+// turned off, which ends the listener.
+func listenHostEvents(ch <-chan events.Message) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return hostEventMsg{event: event, ch: ch}
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/mocks"
+)
+
+// hostDaemon is a daemon owning a single container that records the
+// containers it is asked to stop and the context of its events stream.
+type hostDaemon struct {
+	closeRecordingDaemon
+	container *docker.Container
+	stopped   []string
+	eventsCtx context.Context
+}
+
+func newHostDaemon(id string) *hostDaemon {
+	return &hostDaemon{container: &docker.Container{Summary: container.Summary{
+		ID: id, Names: []string{"/" + id}, Status: "Up 1 minute",
+	}}}
+}
+
+func (d *hostDaemon) ContainerByID(id string) *docker.Container {
+	if id == d.container.ID {
+		return d.container
+	}
+	return nil
+}
+
+func (d *hostDaemon) Containers([]docker.ContainerFilter, docker.SortMode) []*docker.Container {
+	return []*docker.Container{d.container}
+}
+
+func (d *hostDaemon) Events(ctx context.Context) (<-chan events.Message, error) {
+	d.eventsCtx = ctx
+	return make(chan events.Message), nil
+}
+
+func (d *hostDaemon) StopContainer(id string) error {
+	d.stopped = append(d.stopped, id)
+	return nil
+}
+
+func TestModel_AllHostsListsAndRoutesContainers(t *testing.T) {
+	m := newTestModel()
+	local := newHostDaemon("local1")
+	remote := newHostDaemon("remote1")
+	m.daemon = local
+
+	result, _ := m.Update(hostsConnectedMsg{
+		active:  defaultContextName,
+		names:   []string{"staging"},
+		daemons: map[string]dockerDaemon{"staging": remote},
+	})
+	m = result.(model)
+
+	if m.hosts == nil || !m.containers.ShowHost() {
+		t.Fatal("expected the all-hosts view with a HOST column")
+	}
+	containers := m.containerAPI().Containers(nil, docker.NoSort)
+	if len(containers) != 2 || containers[0].Host != "default" || containers[1].Host != "staging" {
+		t.Fatalf("expected a container from each host, got %v", containers)
+	}
+	if m.daemonFor("remote1") != remote {
+		t.Fatal("expected the remote container to belong to the staging daemon")
+	}
+
+	if msg := m.executeContainerOp("stop", "remote1")(); msg == nil {
+		t.Fatal("expected a result from the stop operation")
+	}
+	if len(remote.stopped) != 1 || len(local.stopped) != 0 {
+		t.Fatalf("expected the stop to reach the staging daemon only, got local %v, staging %v",
+			local.stopped, remote.stopped)
+	}
+
+	result, _ = m.toggleAllHosts()
+	m = result.(model)
+	if m.hosts != nil || m.containers.ShowHost() {
+		t.Fatal("expected the all-hosts view to be off")
+	}
+	if !remote.closed || local.closed {
+		t.Fatal("expected only the extra daemons to be closed")
+	}
+	if remote.eventsCtx == nil || remote.eventsCtx.Err() == nil {
+		t.Fatal("expected the events stream of the staging daemon, refreshing its containers, to be stopped")
+	}
+}
+
+func TestModel_AllHostsIgnoresConnectionsForAnotherContext(t *testing.T) {
+	m := newTestModel()
+	m.daemon = &mocks.DockerDaemonMock{}
+	remote := newHostDaemon("remote1")
+
+	result, _ := m.Update(hostsConnectedMsg{
+		active:  "previous",
+		names:   []string{"staging"},
+		daemons: map[string]dockerDaemon{"staging": remote},
+	})
+	m = result.(model)
+
+	if m.hosts != nil {
+		t.Fatal("expected connections started from another context to be dropped")
+	}
+	if !remote.closed {
+		t.Fatal("expected the dropped connection to be closed")
+	}
+}
+
+func TestOtherHostsSkipsActiveAndDuplicateEndpoints(t *testing.T) {
+	contexts := []docker.Context{
+		{Name: "default", Env: docker.Env{DockerHost: "unix:///var/run/docker.sock"}},
+		{Name: "staging", Env: docker.Env{DockerHost: "tcp://staging:2376"}},
+		{Name: "desktop", Env: docker.Env{DockerHost: "unix:///var/run/docker.sock"}},
+		{Name: "staging-cli", Env: docker.Env{DockerHost: "tcp://staging:2376"}},
+	}
+	others := otherHosts(contexts, "default")
+	if len(others) != 1 || others[0].Name != "staging" {
+		t.Fatalf("expected only staging, got %v", others)
+	}
+}
@@ -22,6 +22,8 @@ type globalKeyMap struct {
 	Palette      key.Binding
 	QuickPeek    key.Binding
 	Theme        key.Binding
+	Contexts     key.Binding
+	Columns      key.Binding
 }
 
 var globalKeys = globalKeyMap{
@@ -101,6 +103,14 @@ var globalKeys = globalKeyMap{
 		key.WithKeys("ctrl+0"),
 		key.WithHelp("^0", "theme"),
 	),
+	Contexts: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "contexts"),
+	),
+	Columns: key.NewBinding(
+		key.WithKeys("ctrl+o"),
+		key.WithHelp("^o", "columns"),
+	),
 }
 
 // ---------------------------------------------------------------------------
@@ -29,10 +29,10 @@ func (m model) handleComposeProjectsKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cm
 		return m, nil
 	case "l", "L":
 		if svc := m.composeProjects.SelectedService(); svc != nil {
-			return m, showComposeLogsCmd(m.daemon, svc.Project, svc.Name)
+			return m, showComposeLogsCmd(m.daemon, svc.Project, svc.Name, m.logTail())
 		}
 		if p := m.composeProjects.SelectedProject(); p != nil {
-			return m, showComposeLogsCmd(m.daemon, p.Name, "")
+			return m, showComposeLogsCmd(m.daemon, p.Name, "", m.logTail())
 		}
 		return m, nil
 	case "f5":
@@ -103,7 +103,7 @@ func (m model) handleComposeServicesKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cm
 		return m, nil
 	case "l", "L":
 		if svc := m.composeServices.SelectedService(); svc != nil {
-			return m, showComposeLogsCmd(m.daemon, svc.Project, svc.Name)
+			return m, showComposeLogsCmd(m.daemon, svc.Project, svc.Name, m.logTail())
 		}
 		return m, nil
 	case "f5":
@@ -23,16 +23,27 @@ func (m model) handleContainersKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case "l", "L":
+		if marked := m.containers.MarkedContainers(); len(marked) > 0 {
+			return m, showMergedLogsCmd(m.containerAPI(), marked, m.logTail())
+		}
 		if c := m.containers.SelectedContainer(); c != nil {
-			return m, showContainerLogsCmd(m.daemon, c.ID)
+			return m, showContainerLogsCmd(m.containerAPI(), c.ID, m.logTail())
 		}
 		return m, nil
 	case "s":
 		if c := m.containers.SelectedContainer(); c != nil {
-			return m, showContainerStatsCmd(m.daemon, c.ID)
+			return m, showContainerStatsCmd(m.daemonFor(c.ID), c.ID)
+		}
+		return m, nil
+	case "c":
+		if c := m.containers.SelectedContainer(); c != nil {
+			return m, showContainerHealthCmd(m.containerAPI(), c.ID, false)
 		}
 		return m, nil
 	case "e":
+		if bm, ok := m.showBulkPrompt("rm", "Remove", "container"); ok {
+			return bm, nil
+		}
 		if c := m.containers.SelectedContainer(); c != nil {
 			return m.showPrompt(
 				fmt.Sprintf("Remove container %s?", shortID(c.ID)),
@@ -52,12 +63,27 @@ func (m model) handleContainersKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 		return m, nil
+	case "z":
+		return m.togglePause()
+	case "R":
+		return m.showRenamePrompt(m.containers.SelectedContainer())
+	case "u":
+		return m.showResourcesForm(m.containers.SelectedContainer())
+	case "f":
+		return m.showFiles(m.containers.SelectedContainer())
+	case "I":
+		return m.showCommitForm(m.containers.SelectedContainer())
+	case "X":
+		return m.showExportPrompt(m.containers.SelectedContainer())
 	case "ctrl+e":
 		return m.showPrompt(
 			"Remove all stopped containers?",
 			"rm-all-stopped", "",
 		), nil
 	case "ctrl+k":
+		if bm, ok := m.showBulkPrompt("kill", "Kill", "container"); ok {
+			return bm, nil
+		}
 		if c := m.containers.SelectedContainer(); c != nil {
 			return m.showPrompt(
 				fmt.Sprintf("Kill container %s?", shortID(c.ID)),
@@ -66,6 +92,9 @@ func (m model) handleContainersKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case "ctrl+r":
+		if bm, ok := m.showBulkPrompt("restart", "Restart", "container"); ok {
+			return bm, nil
+		}
 		if c := m.containers.SelectedContainer(); c != nil {
 			return m.showPrompt(
 				fmt.Sprintf("Restart container %s?", shortID(c.ID)),
@@ -74,6 +103,9 @@ func (m model) handleContainersKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case "ctrl+t":
+		if bm, ok := m.showBulkPrompt("stop", "Stop", "container"); ok {
+			return bm, nil
+		}
 		if c := m.containers.SelectedContainer(); c != nil {
 			return m.showPrompt(
 				fmt.Sprintf("Stop container %s?", shortID(c.ID)),
@@ -87,7 +119,7 @@ func (m model) handleContainersKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		m.containers, cmd = m.containers.Update(msg)
 		if m.daemon != nil {
 			return m, tea.Batch(cmd,
-				loadContainersCmd(m.daemon, m.containers.ShowAll(), m.containers.SortMode()))
+				loadContainersCmd(m.containerAPI(), m.containers.ShowAll(), m.containers.SortMode()))
 		}
 		return m, cmd
 	case "f2":
@@ -96,13 +128,13 @@ func (m model) handleContainersKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		m.containers, cmd = m.containers.Update(msg)
 		if m.daemon != nil {
 			return m, tea.Batch(cmd,
-				loadContainersCmd(m.daemon, m.containers.ShowAll(), m.containers.SortMode()))
+				loadContainersCmd(m.containerAPI(), m.containers.ShowAll(), m.containers.SortMode()))
 		}
 		return m, cmd
 	case "f5":
 		// Refresh
 		if m.daemon != nil {
-			return m, loadContainersCmd(m.daemon, m.containers.ShowAll(), m.containers.SortMode())
+			return m, loadContainersCmd(m.containerAPI(), m.containers.ShowAll(), m.containers.SortMode())
 		}
 		return m, nil
 	}
@@ -27,6 +27,9 @@ func (m model) handleImagesKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case "ctrl+d":
 		return m.showPrompt("Remove dangling images?", "rmi-dangling", ""), nil
 	case "ctrl+e":
+		if bm, ok := m.showBulkPrompt("rmi", "Remove", "image"); ok {
+			return bm, nil
+		}
 		if img := m.images.SelectedImage(); img != nil {
 			return m.showPrompt(
 				fmt.Sprintf("Remove image %s?", docker.TruncateID(docker.ImageID(img.ID))),
@@ -35,6 +38,9 @@ func (m model) handleImagesKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case "ctrl+f":
+		if bm, ok := m.showBulkPrompt("rmi-force", "Force remove", "image"); ok {
+			return bm, nil
+		}
 		if img := m.images.SelectedImage(); img != nil {
 			return m.showPrompt(
 				fmt.Sprintf("Force remove image %s?", docker.TruncateID(docker.ImageID(img.ID))),
@@ -44,6 +50,12 @@ func (m model) handleImagesKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case "ctrl+u":
 		return m.showPrompt("Remove unused images?", "rmi-unused", ""), nil
+	case "r":
+		return m.showRunForm()
+	case "s":
+		return m.showSavePrompt()
+	case "l":
+		return m.showLoadPrompt()
 	case "f5":
 		return m, loadImagesCmd(m.daemon)
 	}
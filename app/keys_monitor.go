@@ -10,6 +10,9 @@ import (
 
 // handleMonitorKeys handles key presses for the Monitor view.
 func (m model) handleMonitorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "a" {
+		return m.showAlerts()
+	}
 	var cmd tea.Cmd
 	m.monitor, cmd = m.monitor.Update(msg)
 	return m, tea.Batch(cmd, m.workspaceSelectionActivityCmd())
@@ -19,6 +19,9 @@ func (m model) handleNetworksKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case "ctrl+e":
+		if bm, ok := m.showBulkPrompt("net-rm", "Remove", "network"); ok {
+			return bm, nil
+		}
 		if n := m.networks.SelectedNetwork(); n != nil {
 			return m.showPrompt(
 				fmt.Sprintf("Remove network %s?", n.Name),
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+)
+
+// keyOverrides rebinds dry's built-in keys as configured in the [keys]
+// table of the configuration file. Each entry maps a built-in key to the
+// key that triggers it instead. The built-in key is released unless
+// another entry claims it, which is what lets two keys be swapped.
+type keyOverrides struct {
+	builtin     map[string]string // replacement key -> built-in key
+	replacement map[string]string // built-in key -> replacement key
+}
+
+func newKeyOverrides(keys map[string]string) keyOverrides {
+	o := keyOverrides{
+		builtin:     make(map[string]string, len(keys)),
+		replacement: make(map[string]string, len(keys)),
+	}
+	for from, to := range keys {
+		if _, err := parseKeystroke(from); err != nil {
+			continue
+		}
+		if _, err := parseKeystroke(to); err != nil {
+			continue
+		}
+		o.builtin[to] = from
+		o.replacement[from] = to
+	}
+	return o
+}
+
+// translate returns the key press dry should act on for msg. The second
+// result is false when msg is a built-in key that was moved elsewhere and
+// should be ignored.
+func (o keyOverrides) translate(msg tea.KeyPressMsg) (tea.KeyPressMsg, bool) {
+	pressed := msg.String()
+	if from, ok := o.builtin[pressed]; ok {
+		k, err := parseKeystroke(from)
+		if err != nil {
+			return msg, true
+		}
+		return k, true
+	}
+	if _, moved := o.replacement[pressed]; moved {
+		return msg, false
+	}
+	return msg, true
+}
+
+// rebind returns a copy of bindings whose help keys show the configured
+// replacements, so the footer advertises the keys that actually work.
+func (o keyOverrides) rebind(bindings []key.Binding) []key.Binding {
+	if len(o.replacement) == 0 {
+		return bindings
+	}
+	rebound := make([]key.Binding, len(bindings))
+	for i, kb := range bindings {
+		rebound[i] = kb
+		for _, k := range kb.Keys() {
+			if to, ok := o.replacement[k]; ok {
+				rebound[i].SetHelp(helpKeyName(to), kb.Help().Desc)
+				break
+			}
+		}
+	}
+	return rebound
+}
+
+// helpKeyName formats a keystroke the way the footer shows keys: ^x for
+// ctrl combinations and upper-case function keys.
+func helpKeyName(k string) string {
+	if rest, ok := strings.CutPrefix(k, "ctrl+"); ok {
+		return "^" + rest
+	}
+	if len(k) > 1 && k[0] == 'f' && k[1] >= '0' && k[1] <= '9' {
+		return "F" + k[1:]
+	}
+	return k
+}
+
+var namedKeys = map[string]rune{
+	"enter":     tea.KeyEnter,
+	"tab":       tea.KeyTab,
+	"backspace": tea.KeyBackspace,
+	"esc":       tea.KeyEscape,
+	"space":     tea.KeySpace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"insert":    tea.KeyInsert,
+	"delete":    tea.KeyDelete,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"f1":        tea.KeyF1,
+	"f2":        tea.KeyF2,
+	"f3":        tea.KeyF3,
+	"f4":        tea.KeyF4,
+	"f5":        tea.KeyF5,
+	"f6":        tea.KeyF6,
+	"f7":        tea.KeyF7,
+	"f8":        tea.KeyF8,
+	"f9":        tea.KeyF9,
+	"f10":       tea.KeyF10,
+	"f11":       tea.KeyF11,
+	"f12":       tea.KeyF12,
+}
+
+var keyModifiers = map[string]tea.KeyMod{
+	"ctrl":  tea.ModCtrl,
+	"alt":   tea.ModAlt,
+	"shift": tea.ModShift,
+}
+
+// parseKeystroke builds the key press whose String() is s, using the same
+// names bubbletea uses ("ctrl+e", "f1", "L", "shift+tab").
+func parseKeystroke(s string) (tea.KeyPressMsg, error) {
+	var mods []string
+	name := s
+	// "+" itself is a key, so split modifiers off before the last '+'
+	// that is not the final character.
+	if i := strings.LastIndex(s[:max(len(s)-1, 0)], "+"); i >= 0 {
+		mods = strings.Split(s[:i], "+")
+		name = s[i+1:]
+	}
+	var k tea.KeyPressMsg
+	for _, mod := range mods {
+		m, ok := keyModifiers[mod]
+		if !ok {
+			return k, fmt.Errorf("invalid key %q: unknown modifier %q", s, mod)
+		}
+		k.Mod |= m
+	}
+	if code, ok := namedKeys[name]; ok {
+		k.Code = code
+	} else if utf8.RuneCountInString(name) == 1 {
+		k.Code, _ = utf8.DecodeRuneInString(name)
+		if k.Mod == 0 {
+			k.Text = name
+		}
+	} else {
+		return k, fmt.Errorf("invalid key %q", s)
+	}
+	// Modifier order and names are canonical in bubbletea; anything that
+	// does not round-trip would never match a real key press.
+	if k.String() != s {
+		return k, fmt.Errorf("invalid key %q, did you mean %q?", s, k.String())
+	}
+	return k, nil
+}
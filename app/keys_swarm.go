@@ -64,6 +64,9 @@ func (m model) handleServicesKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "ctrl+r":
+			if bm, ok := m.showBulkPrompt("service-rm", "Remove", "service"); ok {
+				return bm, nil
+			}
 			if s := m.services.SelectedService(); s != nil {
 				return m.showPrompt(
 					fmt.Sprintf("Remove service %s?", s.Spec.Name),
@@ -72,6 +75,9 @@ func (m model) handleServicesKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "ctrl+s":
+			if bm, cmd, ok := m.showBulkScalePrompt(); ok {
+				return bm, cmd
+			}
 			if s := m.services.SelectedService(); s != nil {
 				var cmd tea.Cmd
 				m.inputPrompt, cmd = appui.NewInputPromptModel(
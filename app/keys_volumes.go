@@ -21,6 +21,9 @@ func (m model) handleVolumesKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case "ctrl+a":
 		return m.showPrompt("Remove all volumes?", "vol-rm-all", ""), nil
 	case "ctrl+e":
+		if bm, ok := m.showBulkPrompt("vol-rm", "Remove", "volume"); ok {
+			return bm, nil
+		}
 		if v := m.volumes.SelectedVolume(); v != nil {
 			return m.showPrompt(
 				fmt.Sprintf("Remove volume %s?", v.Name),
@@ -29,6 +32,9 @@ func (m model) handleVolumesKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case "ctrl+f":
+		if bm, ok := m.showBulkPrompt("vol-rm-force", "Force remove", "volume"); ok {
+			return bm, nil
+		}
 		if v := m.volumes.SelectedVolume(); v != nil {
 			return m.showPrompt(
 				fmt.Sprintf("Force remove volume %s?", v.Name),
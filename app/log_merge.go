@@ -0,0 +1,413 @@
+package app
+
+// Merged container logs: the logs of the containers marked in the
+// containers list, read as one stream ordered by time, each line prefixed
+// with the name of its container. Containers can be added and removed
+// while the stream is shown.
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+// logMergeWindow is how long a stream with nothing more to read is waited
+// for before the lines of other streams written since are shown: lines
+// are held back until every stream has a later line, or has ended.
+const logMergeWindow = 150 * time.Millisecond
+
+// mergedLine is a line of merged logs, with the time it was written at
+// and the stream it was read from.
+type mergedLine struct {
+	time   time.Time
+	text   string
+	source int
+}
+
+// mergeSource is a stream to merge and its name.
+type mergeSource struct {
+	name string
+	r    io.ReadCloser
+}
+
+// logMerger merges log streams, read with timestamps, into one stream of
+// lines ordered by time. Lines are prefixed with the name of their stream
+// in a color of its own, stderr lines keeping their mark at their start,
+// and written without their timestamp. The merged stream ends once every
+// stream merged has ended.
+type logMerger struct {
+	pr      *io.PipeReader
+	pw      *io.PipeWriter
+	now     func() time.Time
+	lines   chan mergedLine
+	wake    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	sources map[string]io.ReadCloser
+	queue   *mergeQueue
+	added   int  // streams added so far, picking the color of the next one
+	ended   bool // whether every stream added has ended
+}
+
+func newLogMerger() *logMerger {
+	return newLogMergerWithClock(time.Now)
+}
+
+// newLogMergerWithClock creates a merger telling how long streams have
+// had nothing to read by the given clock.
+func newLogMergerWithClock(now func() time.Time) *logMerger {
+	pr, pw := io.Pipe()
+	lm := &logMerger{
+		pr:      pr,
+		pw:      pw,
+		now:     now,
+		lines:   make(chan mergedLine),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		sources: make(map[string]io.ReadCloser),
+		queue:   newMergeQueue(),
+	}
+	go lm.run()
+	return lm
+}
+
+func (lm *logMerger) Read(p []byte) (int, error) {
+	return lm.pr.Read(p)
+}
+
+// Close stops merging and closes every stream.
+func (lm *logMerger) Close() error {
+	lm.once.Do(func() {
+		close(lm.done)
+		lm.mu.Lock()
+		for name, r := range lm.sources {
+			_ = r.Close()
+			delete(lm.sources, name)
+		}
+		lm.mu.Unlock()
+		_ = lm.pr.Close()
+	})
+	return nil
+}
+
+// add merges the given streams, all of them or, if one cannot be merged,
+// none.
+func (lm *logMerger) add(sources ...mergeSource) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	select {
+	case <-lm.done:
+		return errors.New("logs are closed")
+	default:
+	}
+	if lm.ended {
+		return errors.New("logs have ended")
+	}
+	for i, s := range sources {
+		_, shown := lm.sources[s.name]
+		if shown || slices.ContainsFunc(sources[:i], func(o mergeSource) bool { return o.name == s.name }) {
+			return fmt.Errorf("%s is already shown", s.name)
+		}
+	}
+	for _, s := range sources {
+		lm.sources[s.name] = s.r
+		lm.queue.start(lm.added, lm.now())
+		prefix := appui.ColorFg(s.name, logSourceColor(lm.added)) + " | "
+		go lm.read(lm.added, s.name, prefix, s.r)
+		lm.added++
+	}
+	return nil
+}
+
+// remove stops merging the stream with the given name, returning false if
+// there is none.
+func (lm *logMerger) remove(name string) bool {
+	lm.mu.Lock()
+	r, ok := lm.sources[name]
+	delete(lm.sources, name)
+	lm.mu.Unlock()
+	if ok {
+		_ = r.Close()
+	}
+	return ok
+}
+
+// names returns the names of the streams merged, sorted.
+func (lm *logMerger) names() []string {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	names := make([]string, 0, len(lm.sources))
+	for name := range lm.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// read sends the lines of a stream to be merged until the stream ends or
+// is removed.
+func (lm *logMerger) read(source int, name, prefix string, r io.ReadCloser) {
+	defer func() {
+		lm.mu.Lock()
+		if lm.sources[name] == r {
+			delete(lm.sources, name)
+		}
+		lm.queue.end(source)
+		lm.mu.Unlock()
+		_ = r.Close()
+		select {
+		case lm.wake <- struct{}{}:
+		default:
+		}
+	}()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text, stderr := strings.CutPrefix(scanner.Text(), appui.StderrLineMark)
+		var at time.Time
+		if stamp, rest, ok := strings.Cut(text, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+				at, text = t, rest
+			}
+		}
+		line := prefix + text + "\n"
+		if stderr {
+			line = appui.StderrLineMark + line
+		}
+		select {
+		case lm.lines <- mergedLine{time: at, text: line, source: source}:
+		case <-lm.done:
+			return
+		}
+	}
+}
+
+// run writes the lines received in time order, as soon as no stream can
+// have an earlier one, closing the merged stream once every stream ended.
+func (lm *logMerger) run() {
+	ticker := time.NewTicker(logMergeWindow / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case l := <-lm.lines:
+			lm.mu.Lock()
+			lm.queue.push(l, lm.now())
+			lm.mu.Unlock()
+		case <-lm.wake:
+		case <-ticker.C:
+		case <-lm.done:
+			_ = lm.pw.Close()
+			return
+		}
+		lm.mu.Lock()
+		lines := lm.queue.ready(lm.now())
+		lm.ended = lm.added > 0 && lm.queue.live() == 0
+		ended := lm.ended
+		lm.mu.Unlock()
+		if err := lm.write(lines); err != nil {
+			return
+		}
+		if ended {
+			_ = lm.pw.Close()
+			return
+		}
+	}
+}
+
+func (lm *logMerger) write(lines []mergedLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l.text)
+	}
+	_, err := lm.pw.Write([]byte(b.String()))
+	return err
+}
+
+// mergeQueue holds back the lines of merged streams until no stream can
+// have an earlier line: every stream has a later one, has ended, or has
+// had nothing to read for logMergeWindow.
+type mergeQueue struct {
+	pending []mergedLine // ordered by time
+	streams map[int]*streamMark
+}
+
+// streamMark is how far a stream has been read: the time of its last
+// line, and when it was last read.
+type streamMark struct {
+	last time.Time
+	read time.Time
+}
+
+func newMergeQueue() *mergeQueue {
+	return &mergeQueue{streams: make(map[int]*streamMark)}
+}
+
+// start adds a stream, read from now on.
+func (q *mergeQueue) start(source int, now time.Time) {
+	q.streams[source] = &streamMark{read: now}
+}
+
+// end removes a stream, whose lines are no longer waited for.
+func (q *mergeQueue) end(source int) {
+	delete(q.streams, source)
+}
+
+// live returns the number of streams not ended.
+func (q *mergeQueue) live() int {
+	return len(q.streams)
+}
+
+// push adds a line read at the given time.
+func (q *mergeQueue) push(l mergedLine, now time.Time) {
+	if s, ok := q.streams[l.source]; ok {
+		if l.time.After(s.last) {
+			s.last = l.time
+		}
+		s.read = now
+	}
+	i := sort.Search(len(q.pending), func(i int) bool {
+		return q.pending[i].time.After(l.time)
+	})
+	q.pending = slices.Insert(q.pending, i, l)
+}
+
+// ready removes and returns the lines no stream can have an earlier line
+// than, in time order.
+func (q *mergeQueue) ready(now time.Time) []mergedLine {
+	n := len(q.pending)
+	if len(q.streams) > 0 {
+		var watermark time.Time
+		first := true
+		for _, s := range q.streams {
+			mark := s.last
+			// A stream with nothing to read for a while has written its
+			// history: its next line is written from now on.
+			if idle := now.Add(-logMergeWindow); !s.read.After(idle) && idle.After(mark) {
+				mark = idle
+			}
+			if first || mark.Before(watermark) {
+				watermark, first = mark, false
+			}
+		}
+		n = sort.Search(len(q.pending), func(i int) bool {
+			return q.pending[i].time.After(watermark)
+		})
+	}
+	lines := slices.Clone(q.pending[:n])
+	q.pending = slices.Delete(q.pending, 0, n)
+	return lines
+}
+
+// logSourceColor returns the color of the prefix of the i-th stream added.
+func logSourceColor(i int) color.Color {
+	colors := []color.Color{
+		appui.DryTheme.Info,
+		appui.DryTheme.Success,
+		appui.DryTheme.Secondary,
+		appui.DryTheme.Tertiary,
+		appui.DryTheme.Primary,
+		appui.DryTheme.Warning,
+	}
+	return colors[i%len(colors)]
+}
+
+// openMergedLogSource opens the logs of a container, from the last tail
+// lines on, to be merged.
+func openMergedLogSource(daemon docker.ContainerAPI, c *docker.Container, tail int) (mergeSource, error) {
+	reader, err := daemon.Logs(c.ID, docker.LogOptions{Tail: tail, Follow: true, Timestamps: true})
+	if err != nil {
+		return mergeSource{}, err
+	}
+	if reader == nil {
+		return mergeSource{}, errors.New("log stream unavailable")
+	}
+	return mergeSource{name: paletteContainerLabel(c), r: demuxDockerStream(reader)}, nil
+}
+
+// showMergedLogsCmd opens a streaming log viewer merging the logs of the
+// given containers, starting from the last tail lines of each.
+func showMergedLogsCmd(daemon docker.ContainerAPI, containers []*docker.Container, tail int) tea.Cmd {
+	return func() tea.Msg {
+		var sources []mergeSource
+		var problems []string
+		for _, c := range containers {
+			s, err := openMergedLogSource(daemon, c, tail)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s", paletteContainerLabel(c), err))
+				continue
+			}
+			sources = append(sources, s)
+		}
+		// All the streams are added at once, so that the merged stream does
+		// not end with the first one.
+		lm := newLogMerger()
+		if err := lm.add(sources...); err != nil {
+			for _, s := range sources {
+				_ = s.r.Close()
+			}
+			problems = append(problems, err.Error())
+		}
+		names := lm.names()
+		if len(names) == 0 {
+			_ = lm.Close()
+			return statusMessageMsg{
+				text:   fmt.Sprintf("Logs error: %s", strings.Join(problems, "; ")),
+				expiry: 5 * time.Second,
+			}
+		}
+		msg := showStreamingLessMsg{
+			title:   fmt.Sprintf("Logs: %d containers", len(names)),
+			reader:  lm,
+			sources: names,
+		}
+		if len(problems) > 0 {
+			msg.message = fmt.Sprintf("Logs error: %s", strings.Join(problems, "; "))
+		}
+		return msg
+	}
+}
+
+// addLogSourceCmd adds the logs of the container with the given name or ID
+// prefix to the merged logs shown.
+func addLogSourceCmd(daemon docker.ContainerAPI, lm *logMerger, name string, tail int) tea.Cmd {
+	return func() tea.Msg {
+		containers := daemon.Containers(nil, docker.NoSort)
+		i := slices.IndexFunc(containers, func(c *docker.Container) bool {
+			return paletteContainerLabel(c) == name
+		})
+		if i < 0 {
+			i = slices.IndexFunc(containers, func(c *docker.Container) bool {
+				return strings.HasPrefix(c.ID, name)
+			})
+		}
+		if i < 0 {
+			return logSourcesMsg{merger: lm, text: fmt.Sprintf("No container %s", name)}
+		}
+		c := containers[i]
+		s, err := openMergedLogSource(daemon, c, tail)
+		if err == nil {
+			if err = lm.add(s); err != nil {
+				_ = s.r.Close()
+			}
+		}
+		if err != nil {
+			return logSourcesMsg{merger: lm, text: fmt.Sprintf("Logs of %s: %s", paletteContainerLabel(c), err)}
+		}
+		return logSourcesMsg{merger: lm, text: fmt.Sprintf("Added the logs of %s", paletteContainerLabel(c))}
+	}
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/mocks"
+)
+
+func TestLogMergerOrdersLinesByTime(t *testing.T) {
+	// A stopped clock: streams are waited for until they end.
+	start := time.Now()
+	lm := newLogMergerWithClock(func() time.Time { return start })
+	defer lm.Close()
+
+	api := io.NopCloser(strings.NewReader("2026-10-17T10:00:02Z request served\n2026-10-17T10:00:04Z request served\n"))
+	db := io.NopCloser(strings.NewReader(appui.StderrLineMark + "2026-10-17T10:00:01Z slow query\n2026-10-17T10:00:03Z checkpoint\n"))
+	if err := lm.add(mergeSource{name: "api", r: api}, mergeSource{name: "db", r: db}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := io.ReadAll(lm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := appui.StderrLineMark + "db | slow query\napi | request served\ndb | checkpoint\napi | request served\n"
+	if got := ansi.Strip(string(out)); got != want {
+		t.Fatalf("expected the merged stream to end with the lines in time order %q, got %q", want, got)
+	}
+	if err := lm.add(mergeSource{name: "web", r: io.NopCloser(strings.NewReader(""))}); err == nil {
+		t.Fatal("expected adding to ended logs to fail")
+	}
+}
+
+func TestLogMergerAddsAndRemovesStreams(t *testing.T) {
+	lm := newLogMerger()
+	defer lm.Close()
+
+	apiR, apiW := io.Pipe()
+	defer apiW.Close()
+	dbR, dbW := io.Pipe()
+	defer dbW.Close()
+	if err := lm.add(mergeSource{name: "api", r: apiR}, mergeSource{name: "db", r: dbR}); err != nil {
+		t.Fatal(err)
+	}
+	if err := lm.add(mergeSource{name: "db", r: dbR}); err == nil {
+		t.Fatal("expected adding db twice to fail")
+	}
+	if !lm.remove("db") || lm.remove("db") {
+		t.Fatal("expected db to be removed once")
+	}
+	if names := lm.names(); strings.Join(names, ",") != "api" {
+		t.Fatalf("expected only api left, got %v", names)
+	}
+}
+
+func TestMergeQueueWaitsForEveryStream(t *testing.T) {
+	at := func(s int) time.Time { return time.Date(2026, 10, 17, 10, 0, s, 0, time.UTC) }
+	now := at(30)
+	q := newMergeQueue()
+	q.start(0, now)
+	q.start(1, now)
+
+	q.push(mergedLine{time: at(2), text: "api\n", source: 0}, now)
+	if lines := q.ready(now); len(lines) != 0 {
+		t.Fatalf("expected the line held back until db has a later one, got %v", lines)
+	}
+	// The tail of db arrives later, with an earlier line.
+	q.push(mergedLine{time: at(1), text: "db\n", source: 1}, now)
+	if lines := q.ready(now); len(lines) != 1 || lines[0].text != "db\n" {
+		t.Fatalf("expected only the db line, earlier than every stream's last, got %v", lines)
+	}
+	q.push(mergedLine{time: at(3), text: "db\n", source: 1}, now)
+	if lines := q.ready(now); len(lines) != 1 || lines[0].text != "api\n" {
+		t.Fatalf("expected the api line once db has a later one, got %v", lines)
+	}
+
+	// db has nothing more to read for a while: the lines of api written
+	// since are not held back by it.
+	q.push(mergedLine{time: at(31), text: "api\n", source: 0}, at(31))
+	if lines := q.ready(at(31)); len(lines) != 1 || !lines[0].time.Equal(at(3)) {
+		t.Fatalf("expected the api line held back while db may still send, got %v", lines)
+	}
+	later := at(31).Add(logMergeWindow + time.Second)
+	if lines := q.ready(later); len(lines) != 1 || !lines[0].time.Equal(at(31)) {
+		t.Fatalf("expected the api line once db is idle, got %v", lines)
+	}
+
+	q.push(mergedLine{time: at(40), text: "db\n", source: 1}, later)
+	q.end(0)
+	q.end(1)
+	if lines := q.ready(later); len(lines) != 1 || q.live() != 0 {
+		t.Fatalf("expected every line once the streams ended, got %v", lines)
+	}
+}
+
+// logsDaemon streams empty logs for the containers whose ID it has.
+type logsDaemon struct {
+	mocks.DockerDaemonMock
+	ids map[string]bool
+}
+
+func (d *logsDaemon) Logs(id string, _ docker.LogOptions) (io.ReadCloser, error) {
+	if !d.ids[id] {
+		return nil, errors.New("no such container")
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func TestShowMergedLogsReportsStreamsNotOpened(t *testing.T) {
+	daemon := &logsDaemon{ids: map[string]bool{"a1": true, "b1": true}}
+	var containers []*docker.Container
+	for _, id := range []string{"a1", "b1", "c1"} {
+		containers = append(containers, &docker.Container{Summary: container.Summary{ID: id, Names: []string{"/" + id}}})
+	}
+	msg, ok := showMergedLogsCmd(daemon, containers, 10)().(showStreamingLessMsg)
+	if !ok {
+		t.Fatal("expected the logs that opened to be shown")
+	}
+	defer msg.reader.Close()
+	if msg.title != "Logs: 2 containers" {
+		t.Fatalf("expected the title to count the logs shown, got %q", msg.title)
+	}
+	if msg.message != "Logs error: c1: no such container" {
+		t.Fatalf("expected the logs not opened to be reported, got %q", msg.message)
+	}
+}
@@ -25,11 +25,41 @@ type dockerErrorMsg struct {
 	err error
 }
 
+// dockerEventMsg and eventsClosedMsg carry the channel they came from, so
+// that after a context switch the old daemon's listener cannot re-arm or
+// reconnect against the new one.
 type dockerEventMsg struct {
 	event events.Message
+	ch    <-chan events.Message
 }
 
-type eventsClosedMsg struct{}
+type eventsClosedMsg struct {
+	ch <-chan events.Message
+}
+
+// contextConnectedMsg carries a daemon connected to after picking a
+// Docker context.
+type contextConnectedMsg struct {
+	name   string
+	daemon dockerDaemon
+}
+
+// hostsConnectedMsg carries the daemons connected to for the all-hosts
+// view, keyed by host name, along with the hosts that could not be reached.
+// active is the context in use when the connections were started.
+type hostsConnectedMsg struct {
+	active  string
+	names   []string
+	daemons map[string]dockerDaemon
+	failed  []string
+}
+
+// hostEventMsg is an event from one of the extra daemons of the all-hosts
+// view.
+type hostEventMsg struct {
+	event events.Message
+	ch    <-chan events.Message
+}
 
 type reconnectEventsMsg struct{}
 
@@ -72,6 +102,20 @@ type statusMessageMsg struct {
 
 type flushRefreshMsg struct{}
 
+// lessStatusMsg reports the outcome of an action of the log/inspect
+// viewer, such as writing or piping its buffer.
+type lessStatusMsg struct {
+	text string
+}
+
+// viewSavedMsg reports a view saved under a name, and whether it could be
+// written to the configuration file at path.
+type viewSavedMsg struct {
+	view SavedView
+	path string
+	err  error
+}
+
 type flushMonitorStatsMsg struct{}
 
 // messageBarExpiredMsg triggers a re-render so the expired message clears.
@@ -84,12 +128,53 @@ type showLessMsg struct {
 	title   string
 }
 
+// healthMsg carries the health panel of a container, to open or, with
+// refresh, to update the one open.
+type healthMsg struct {
+	id      string
+	title   string
+	content string
+	refresh bool
+}
+
 // showStreamingLessMsg opens a less viewer with initial content and a
 // reader that will be streamed via appendLessMsg.
 type showStreamingLessMsg struct {
 	content string
 	title   string
 	reader  io.ReadCloser
+	logs    *logSource // set for container logs, which can be read again
+	sources []string   // containers whose logs are merged, see logMerger
+	message string     // shown on the status line of the viewer
+}
+
+// logSource is where the logs shown by the viewer come from, kept to read
+// them again with other options.
+type logSource struct {
+	api  docker.ContainerAPI
+	id   string
+	opts docker.LogOptions
+}
+
+// logsReopenedMsg carries the stream of logs read again with other
+// options, to replace those shown.
+type logsReopenedMsg struct {
+	reader io.ReadCloser
+}
+
+// bulkDoneMsg reports how a bulk operation went on each of its items.
+type bulkDoneMsg struct {
+	title  string
+	view   viewMode // view of the list the operation ran on
+	lines  []string // one per item, telling whether it was done
+	failed int
+}
+
+// logSourcesMsg reports a change to the containers whose logs are merged
+// by a logMerger.
+type logSourcesMsg struct {
+	merger *logMerger
+	text   string
 }
 
 // appendLessMsg appends streamed content to an open less viewer.
@@ -10,12 +10,13 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/moby/moby/api/types/events"
-	"github.com/moby/moby/api/types/swarm"
+	"github.com/moncho/dry/alert"
 	"github.com/moncho/dry/appui"
 	appcompose "github.com/moncho/dry/appui/compose"
 	appswarm "github.com/moncho/dry/appui/swarm"
 	appworkspace "github.com/moncho/dry/appui/workspace"
 	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/metrics"
 )
 
 // Compile-time assertion: model implements tea.Model.
@@ -51,11 +52,20 @@ type model struct {
 	// Docker
 	daemon       dockerDaemon
 	config       Config
+	keyOverrides keyOverrides
+	contextName  string // Docker context in use, empty until one is picked
 	swarmMode    bool
 	eventsChan   <-chan events.Message
 	eventsCancel context.CancelFunc
-	composeCLI   composeEngine
-	workingDir   string
+	// hosts merges the containers of every known host while the all-hosts
+	// view is on, hostDaemons holds their daemons by host name.
+	hosts       *docker.MultiHostContainers
+	hostDaemons map[string]dockerDaemon
+	hostsCancel context.CancelFunc
+	composeCLI  composeEngine
+	workingDir  string
+	// transfers cancels the archive transfers in progress.
+	transfers map[<-chan transferMsg]context.CancelFunc
 
 	// Sub-models
 	containers       appui.ContainersModel
@@ -86,9 +96,18 @@ type model struct {
 	containerMenu  appui.ContainerMenuModel
 	commandPalette appui.CommandPaletteModel
 	quickPeek      appui.QuickPeekModel
+	columnPicker   appui.ColumnPickerModel
+	form           appui.FormModel
+	// files is the files browser, kept open under the file viewer and the
+	// copy prompts; filesCancel stops reading the filesystem it browses.
+	files          appui.FilesModel
+	filesCancel    context.CancelFunc
 	streamReader   io.ReadCloser // active streaming reader (logs)
+	logSource      *logSource    // container logs shown, nil for other streams
+	pendingBulk    *bulkOp       // bulk operation waiting for confirmation
 	activityReader io.ReadCloser
-	eventsLive     bool // true when events less overlay is open
+	eventsLive     bool   // true when events less overlay is open
+	healthID       string // container whose health panel is open, if any
 
 	// Docker event throttling
 	pendingRefresh map[docker.SourceType]bool
@@ -112,6 +131,11 @@ type model struct {
 	// Monitor stats workspace throttling
 	monitorStatsTimer bool
 
+	// Alerts evaluates the alert rules, nil without rules; alertLog keeps
+	// the last alerts fired for the alerts panel.
+	alerts   *alert.Evaluator
+	alertLog []alert.Alert
+
 	// Loading animation
 	loadingFrame int
 	loadingFwd   bool
@@ -123,9 +147,10 @@ type model struct {
 // NewModel creates a new top-level model.
 func NewModel(cfg Config) model {
 	workingDir, _ := os.Getwd()
-	return model{
+	m := model{
 		workingDir:       workingDir,
 		config:           cfg,
+		keyOverrides:     newKeyOverrides(cfg.Keys),
 		view:             Main,
 		showHeader:       true,
 		containers:       appui.NewContainersModel(),
@@ -146,6 +171,23 @@ func NewModel(cfg Config) model {
 		loadingFwd:       true,
 		splashDone:       cfg.SplashDuration <= 0,
 	}
+	m.applyColumnConfig()
+	m.applyFormatConfig()
+	m.applySortConfig()
+	if cfg.MetricsDir != "" {
+		h, err := metrics.NewHistory(cfg.MetricsDir, cfg.MetricsRetention)
+		if err != nil {
+			m.messageBar.SetMessage(fmt.Sprintf("Monitor stats are not kept: %s", err), 10*time.Second)
+		} else {
+			m.monitor.SetHistory(h)
+		}
+	}
+	// The configuration file checked the rules are valid.
+	if rules, err := parseAlertRules(cfg.Alerts); err == nil && len(rules) > 0 {
+		m.alerts = alert.NewEvaluator(rules)
+		m.monitor.SetAlerting(m.alerts.Firing)
+	}
+	return m
 }
 
 func (m model) Init() tea.Cmd {
@@ -174,41 +216,33 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.containerMenu.SetSize(m.width, m.height)
 		m.commandPalette.SetSize(m.width, m.height)
 		m.quickPeek.SetSize(m.width, m.height)
+		m.columnPicker.SetSize(m.width, m.height)
+		m.form.SetSize(m.width, m.height)
+		m.files.SetSize(m.width, m.height)
 		return m, nil
 
 	case dockerConnectedMsg:
-		m.daemon = msg.daemon
 		m.ready = m.splashDone
-		if info, err := m.daemon.Info(); err == nil {
-			m.swarmMode = info.Swarm.LocalNodeState == swarm.LocalNodeStateActive
-		}
-		m.monitor.SetDaemon(m.daemon)
-		m.tasks.SetDaemon(m.daemon)
-		m.resizeContentModels()
-		m.header = appui.NewHeaderModel(m.daemon, m.width)
-		eventsCtx, eventsCancel := context.WithCancel(context.Background())
-		eventsCh, err := m.daemon.Events(eventsCtx)
-		if err != nil {
-			eventsCancel()
-			m.messageBar.SetMessage(fmt.Sprintf("Docker events error: %s", err), 5*time.Second)
-			return m, tea.Batch(
-				loadContainersCmd(m.daemon, m.containers.ShowAll(), m.containers.SortMode()),
-				loadHeaderInfoCmd(m.daemon),
-				detectComposeCmd(m.daemon.DockerEnv()),
-			)
+		return m.useDaemon(msg.daemon, true)
+
+	case contextConnectedMsg:
+		m.releaseDaemon()
+		m.contextName = msg.name
+		m.messageBar.SetMessage(fmt.Sprintf("Switched to context %s", msg.name), 3*time.Second)
+		return m.useDaemon(msg.daemon, false)
+
+	case hostsConnectedMsg:
+		return m.useHosts(msg)
+
+	case hostEventMsg:
+		if m.hosts == nil {
+			return m, nil
 		}
-		m.eventsChan = eventsCh
-		m.eventsCancel = eventsCancel
-		if m.config.MonitorMode {
-			m2, cmd := m.switchView(Monitor)
-			return m2, tea.Batch(cmd, listenDockerEvents(m.eventsChan), loadHeaderInfoCmd(m.daemon), detectComposeCmd(m.daemon.DockerEnv()))
+		var refresh tea.Cmd
+		if docker.SourceType(msg.event.Type) == docker.ContainerSource {
+			refresh = m.scheduleRefresh(docker.ContainerSource)
 		}
-		return m, tea.Batch(
-			loadContainersCmd(m.daemon, m.containers.ShowAll(), m.containers.SortMode()),
-			listenDockerEvents(m.eventsChan),
-			loadHeaderInfoCmd(m.daemon),
-			detectComposeCmd(m.daemon.DockerEnv()),
-		)
+		return m, tea.Batch(listenHostEvents(msg.ch), refresh)
 
 	case headerInfoMsg:
 		m.header.SetDockerInfo(msg.info, msg.infoErr, msg.ver, msg.verErr)
@@ -256,6 +290,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case appui.MonitorStatsMsg:
 		prevCount := m.monitor.StatsCount()
 		cmd := m.monitor.UpdateStats(msg.CID, msg.Stats, msg.StatsCh)
+		alertCmd := m.observeAlerts(msg.CID, msg.Stats)
 		newContainer := m.monitor.StatsCount() != prevCount
 		if newContainer {
 			m.monitor.FlushTable()
@@ -263,10 +298,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.resizeContentModels()
 			}
 		}
-		cmds := []tea.Cmd{cmd}
+		cmds := []tea.Cmd{cmd, alertCmd}
 		if !m.monitorStatsTimer {
 			m.monitorStatsTimer = true
-			cmds = append(cmds, tea.Tick(500*time.Millisecond, func(time.Time) tea.Msg {
+			cmds = append(cmds, tea.Tick(m.monitorRefresh(), func(time.Time) tea.Msg {
 				return flushMonitorStatsMsg{}
 			}))
 		}
@@ -274,6 +309,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case appui.MonitorErrorMsg:
 		prevRows := m.monitor.RowCount()
+		if m.alerts != nil {
+			m.alerts.Forget(msg.CID)
+		}
 		m.monitor.RemoveContainer(msg.CID)
 		if m.workspaceEnabled() && m.monitor.RowCount() != prevRows {
 			m.resizeContentModels()
@@ -374,6 +412,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case eventsClosedMsg:
+		if msg.ch != m.eventsChan {
+			return m, nil
+		}
 		// Events channel was closed (daemon restart, network error).
 		// Try to re-establish the events listener after a short delay.
 		m.messageBar.SetMessage("Docker events disconnected, reconnecting...", 3*time.Second)
@@ -404,19 +445,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, listenDockerEvents(m.eventsChan)
 
 	case dockerEventMsg:
+		if msg.ch != m.eventsChan {
+			return m, nil
+		}
 		if m.eventsLive && m.overlay == overlayLess {
 			m.less.AppendContent(formatEvent(msg.event) + "\n")
 		}
-		source := docker.SourceType(msg.event.Type)
-		m.pendingRefresh[source] = true
-		cmds := []tea.Cmd{listenDockerEvents(m.eventsChan)}
-		if !m.refreshTimer {
-			m.refreshTimer = true
-			cmds = append(cmds, tea.Tick(250*time.Millisecond, func(time.Time) tea.Msg {
-				return flushRefreshMsg{}
-			}))
+		refresh := m.scheduleRefresh(docker.SourceType(msg.event.Type))
+		if msg.event.Type == events.ContainerEventType &&
+			(msg.event.Action == events.ActionPause || msg.event.Action == events.ActionUnPause) {
+			m.monitor.SetPaused(msg.event.Actor.ID, msg.event.Action == events.ActionPause)
 		}
-		return m, tea.Batch(cmds...)
+		return m, tea.Batch(listenDockerEvents(m.eventsChan), refresh, m.eventAlerts(msg.event), m.healthEvent(msg.event))
 
 	case flushRefreshMsg:
 		m.refreshTimer = false
@@ -425,7 +465,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch source {
 			case docker.ContainerSource:
 				if m.view == Main {
-					cmds = append(cmds, loadContainersCmd(m.daemon, m.containers.ShowAll(), m.containers.SortMode()))
+					cmds = append(cmds, loadContainersCmd(m.containerAPI(), m.containers.ShowAll(), m.containers.SortMode()))
 				}
 				// A compose reload is not free: its ProjectsLoadedMsg starts
 				// a scan/drift cycle of compose subprocesses. Container
@@ -504,6 +544,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case showLessMsg:
+		m.logSource = nil
+		m.healthID = ""
 		m.less = appui.NewLessModel()
 		m.less.SetSize(m.width, m.height)
 		m.less.SetContent(msg.content, msg.title)
@@ -527,8 +569,119 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.less.SetFollowing(true)
 		m.overlay = overlayLess
 		m.streamReader = msg.reader
+		m.logSource = msg.logs
+		m.healthID = ""
+		if msg.logs != nil {
+			m.less.SetLogOptions(msg.logs.opts)
+		}
+		if msg.sources != nil {
+			m.less.SetLogSources(msg.sources)
+		}
+		if msg.message != "" {
+			m.less.SetMessage(msg.message)
+		}
+		return m, readLogStreamCmd(msg.reader)
+
+	case appui.AddLogSourceMsg:
+		if lm, ok := m.streamReader.(*logMerger); ok {
+			return m, addLogSourceCmd(m.containerAPI(), lm, msg.Name, m.logTail())
+		}
+		return m, nil
+
+	case appui.RemoveLogSourceMsg:
+		lm, ok := m.streamReader.(*logMerger)
+		if !ok {
+			return m, nil
+		}
+		if !lm.remove(msg.Name) {
+			m.less.SetMessage(fmt.Sprintf("No logs of %s are shown", msg.Name))
+			return m, nil
+		}
+		m.less.SetLogSources(lm.names())
+		m.less.SetMessage(fmt.Sprintf("Removed the logs of %s", msg.Name))
+		return m, nil
+
+	case bulkDoneMsg:
+		return m.bulkDone(msg)
+
+	case healthMsg:
+		return m.showHealth(msg)
+
+	case appui.FormSubmittedMsg:
+		switch msg.Tag {
+		case runFormTag:
+			return m.submitRunForm(msg)
+		case resourcesFormTag:
+			return m.submitResourcesForm(msg)
+		case commitFormTag:
+			return m.submitCommitForm(msg)
+		}
+		return m, nil
+
+	case imageRunMsg:
+		return m.imageRun(msg)
+
+	case resourcesUpdatedMsg:
+		return m.resourcesUpdated(msg)
+
+	case containerCommittedMsg:
+		return m.containerCommitted(msg)
+
+	case transferMsg:
+		return m.transferred(msg)
+
+	case filesTreeMsg, filesChangesMsg, filesNoticeMsg, appui.FilesReloadMsg,
+		appui.FileViewMsg, appui.FileCopyOutMsg, appui.FileCopyInMsg:
+		return m.updateFiles(msg)
+
+	case logSourcesMsg:
+		if m.overlay == overlayLess && msg.merger == m.streamReader {
+			m.less.SetLogSources(msg.merger.names())
+			m.less.SetMessage(msg.text)
+		}
+		return m, nil
+
+	case appui.ReopenLogsMsg:
+		if m.logSource == nil {
+			return m, nil
+		}
+		// The old stream is closed first; its last chunks and close notice
+		// are then dropped as coming from a superseded reader.
+		if m.streamReader != nil {
+			_ = m.streamReader.Close()
+			m.streamReader = nil
+		}
+		src := *m.logSource
+		src.opts = msg.Options
+		m.logSource = &src
+		return m, reopenLogsCmd(src)
+
+	case logsReopenedMsg:
+		if m.overlay != overlayLess || m.logSource == nil {
+			_ = msg.reader.Close()
+			return m, nil
+		}
+		if m.streamReader != nil {
+			_ = m.streamReader.Close()
+		}
+		m.streamReader = msg.reader
+		m.less.ReplaceContent("")
 		return m, readLogStreamCmd(msg.reader)
 
+	case appui.WriteBufferMsg:
+		return m, writeBufferCmd(msg.Path, msg.Content)
+
+	case appui.PipeBufferMsg:
+		return m, pipeBufferCmd(msg.Command, msg.Content)
+
+	case lessStatusMsg:
+		if m.overlay == overlayLess {
+			m.less.SetMessage(msg.text)
+			return m, nil
+		}
+		m.messageBar.SetMessage(msg.text, 5*time.Second)
+		return m, nil
+
 	case appendLessMsg:
 		// Only append content from the reader that is currently live;
 		// a chunk from a superseded stream would interleave two containers'
@@ -562,8 +715,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case appui.CloseOverlayMsg:
-		m.overlay = overlayNone
+		if m.filesOpen() {
+			m.closeFilesOverlay()
+		} else {
+			m.overlay = overlayNone
+		}
 		m.eventsLive = false
+		m.healthID = ""
 		var cmds []tea.Cmd
 		if m.streamReader != nil {
 			err := m.streamReader.Close()
@@ -584,6 +742,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case appui.PromptResultMsg:
 		m.overlay = overlayNone
+		if msg.Tag == "bulk" {
+			b := m.pendingBulk
+			m.pendingBulk = nil
+			if msg.Confirmed && b != nil {
+				return m, m.executeBulkOp(*b, "")
+			}
+			return m, nil
+		}
 		if msg.Confirmed {
 			return m, m.executeContainerOp(msg.Tag, msg.ID)
 		}
@@ -591,11 +757,28 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case appui.InputPromptResultMsg:
 		m.overlay = overlayNone
+		if m.filesOpen() {
+			m.overlay = overlayFiles
+		}
+		if msg.Tag == "bulk" {
+			b := m.pendingBulk
+			m.pendingBulk = nil
+			if !msg.Cancelled && b != nil {
+				return m, m.executeBulkOp(*b, msg.Value)
+			}
+			return m, nil
+		}
 		if !msg.Cancelled {
 			return m, m.executeInputOp(msg.Tag, msg.ID, msg.Value)
 		}
 		return m, nil
 
+	case appui.ColumnsChosenMsg:
+		return m.chooseColumns(msg.Choices)
+
+	case viewSavedMsg:
+		return m.viewSaved(msg)
+
 	case appui.ContainerMenuCommandMsg:
 		m.overlay = overlayNone
 		return m.executeMenuCommand(msg.ContainerID, msg.Command)
@@ -642,6 +825,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	// Configured key overrides apply everywhere except while typing into
+	// a filter, where every key is literal text.
+	if !m.filterActive() {
+		var ok bool
+		if msg, ok = m.keyOverrides.translate(msg); !ok {
+			return m, nil
+		}
+	}
+
 	// Quit keys always handled regardless of filter state
 	switch msg.String() {
 	case "ctrl+c", "Q":
@@ -688,6 +880,14 @@ func (m model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case "ctrl+0":
 		m.rotateTheme()
 		return m, nil
+	case "ctrl+x":
+		if cm, ok := m.cancelTransfers(); ok {
+			return cm, nil
+		}
+	case "C":
+		return m.openContextPicker()
+	case "ctrl+o":
+		return m.openColumnPicker()
 	case "1":
 		return m.switchView(Main)
 	case "?", "h", "H":
@@ -730,6 +930,9 @@ func (m model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case "8":
 		return m.switchView(ComposeProjects)
 	case "esc":
+		if m.clearMarks(m.view) {
+			return m, nil
+		}
 		if m.workspaceEnabled() && m.pinnedContext != nil {
 			cleared := m.clearPinnedContext()
 			return cleared, cleared.workspaceSelectionActivityCmd()
@@ -854,6 +1057,12 @@ func (m model) View() tea.View {
 		content = m.commandPalette.View()
 	} else if m.overlay == overlayQuickPeek {
 		content = m.quickPeek.View()
+	} else if m.overlay == overlayColumnPicker {
+		content = m.columnPicker.View()
+	} else if m.overlay == overlayForm {
+		content = m.form.View()
+	} else if m.overlay == overlayFiles {
+		content = m.files.View()
 	} else {
 		content = m.renderMainScreen()
 	}
@@ -898,11 +1107,25 @@ func (m *model) closeActivityReader() {
 	}
 }
 
+// scheduleRefresh marks the given source for reload, arming the timer
+// that batches the reloads of events arriving close together.
+func (m *model) scheduleRefresh(source docker.SourceType) tea.Cmd {
+	m.pendingRefresh[source] = true
+	if m.refreshTimer {
+		return nil
+	}
+	m.refreshTimer = true
+	return tea.Tick(250*time.Millisecond, func(time.Time) tea.Msg {
+		return flushRefreshMsg{}
+	})
+}
+
 func (m model) findContainerByID(id string) (*docker.Container, bool) {
-	if c := m.daemon.ContainerByID(id); c != nil {
+	containers := m.containerAPI()
+	if c := containers.ContainerByID(id); c != nil {
 		return c, true
 	}
-	for _, c := range m.daemon.Containers(nil, docker.NoSort) {
+	for _, c := range containers.Containers(nil, docker.NoSort) {
 		if c.ID == id {
 			return c, true
 		}
@@ -971,7 +1194,7 @@ func (m model) loadViewData(v viewMode) tea.Cmd {
 	}
 	switch v {
 	case Main:
-		return loadContainersCmd(m.daemon, m.containers.ShowAll(), m.containers.SortMode())
+		return loadContainersCmd(m.containerAPI(), m.containers.ShowAll(), m.containers.SortMode())
 	case Images:
 		return loadImagesCmd(m.daemon)
 	case Networks:
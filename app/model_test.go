@@ -248,6 +248,33 @@ type stubStreamReader struct {
 func (s *stubStreamReader) Read(p []byte) (int, error) { return 0, io.EOF }
 func (s *stubStreamReader) Close() error               { s.closed = true; return nil }
 
+func TestModel_ReopenLogsInPlace(t *testing.T) {
+	m := newTestModel()
+	first := &stubStreamReader{}
+	second := &stubStreamReader{}
+	logs := &logSource{id: "abc", opts: docker.LogOptions{Tail: 100, Follow: true}}
+	result, _ := m.Update(showStreamingLessMsg{content: "old\n", title: "Logs: abc", reader: first, logs: logs})
+	m = result.(model)
+
+	result, cmd := m.Update(appui.ReopenLogsMsg{Options: docker.LogOptions{Since: "1h", Follow: true}})
+	m = result.(model)
+	if !first.closed || m.streamReader != nil {
+		t.Fatal("expected the stream being shown to be closed")
+	}
+	if cmd == nil || m.logSource.opts.Since != "1h" || m.logSource.id != "abc" {
+		t.Fatalf("expected the logs to be read again since 1h, got %+v", m.logSource)
+	}
+
+	result, cmd = m.Update(logsReopenedMsg{reader: second})
+	m = result.(model)
+	if m.overlay != overlayLess || m.streamReader != second || cmd == nil {
+		t.Fatal("expected the new stream to be read in the same viewer")
+	}
+	if got := m.less.BufferText(true); got != "" {
+		t.Fatalf("expected the old lines to be replaced, got %q", got)
+	}
+}
+
 func TestModel_StreamingLessClosesSupersededReader(t *testing.T) {
 	m := newTestModel()
 	first := &stubStreamReader{}
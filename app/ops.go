@@ -19,11 +19,11 @@ import (
 func (m model) executeMenuCommand(containerID string, cmd docker.Command) (model, tea.Cmd) {
 	switch cmd {
 	case docker.INSPECT:
-		return m, inspectContainerCmd(m.daemon, containerID)
+		return m, inspectContainerCmd(m.containerAPI(), containerID)
 	case docker.LOGS:
-		return m, showContainerLogsCmd(m.daemon, containerID)
+		return m, showContainerLogsCmd(m.containerAPI(), containerID, m.logTail())
 	case docker.ATTACH:
-		return m, attachContainerCmd(m.daemon, containerID)
+		return m, attachContainerCmd(m.daemonFor(containerID), containerID)
 	case docker.EXEC:
 		var cmd tea.Cmd
 		m.inputPrompt, cmd = appui.NewInputPromptModelWithLimit(
@@ -53,38 +53,102 @@ func (m model) executeMenuCommand(containerID string, cmd docker.Command) (model
 			fmt.Sprintf("Remove container %s?", shortID(containerID)),
 			"rm", containerID,
 		), nil
+	case docker.PAUSE:
+		return m, m.executeContainerOp("pause", containerID)
+	case docker.UNPAUSE:
+		return m, m.executeContainerOp("unpause", containerID)
+	case docker.RENAME:
+		return m.showRenamePrompt(m.containerAPI().ContainerByID(containerID))
+	case docker.UPDATE:
+		return m.showResourcesForm(m.containerAPI().ContainerByID(containerID))
+	case docker.FILES:
+		return m.showFiles(m.containerAPI().ContainerByID(containerID))
+	case docker.COMMIT:
+		return m.showCommitForm(m.containerAPI().ContainerByID(containerID))
+	case docker.EXPORT:
+		return m.showExportPrompt(m.containerAPI().ContainerByID(containerID))
 	case docker.STATS:
-		return m, showContainerStatsCmd(m.daemon, containerID)
+		return m, showContainerStatsCmd(m.daemonFor(containerID), containerID)
+	case docker.HEALTH:
+		return m, showContainerHealthCmd(m.containerAPI(), containerID, false)
 	case docker.HISTORY:
-		if c := m.daemon.ContainerByID(containerID); c != nil {
-			return m, showImageHistoryCmd(m.daemon, c.ImageID)
+		if c := m.containerAPI().ContainerByID(containerID); c != nil {
+			return m, showImageHistoryCmd(m.daemonFor(containerID), c.ImageID)
 		}
 		return m, nil
 	}
 	return m, nil
 }
 
+// showRenamePrompt asks for the new name of the given container.
+func (m model) showRenamePrompt(c *docker.Container) (model, tea.Cmd) {
+	if c == nil {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.inputPrompt, cmd = appui.NewInputPromptModelWithLimit(
+		fmt.Sprintf("Rename %s to:", paletteContainerLabel(c)),
+		paletteContainerLabel(c), "rename", c.ID, 128,
+	)
+	m.inputPrompt.SetSize(m.width, m.height)
+	m.overlay = overlayInputPrompt
+	return m, cmd
+}
+
+// togglePause pauses the marked containers, or the selected one, unpausing
+// them instead if they are all paused.
+func (m model) togglePause() (tea.Model, tea.Cmd) {
+	marked := m.containers.MarkedContainers()
+	if len(marked) == 0 {
+		c := m.containers.SelectedContainer()
+		if c == nil {
+			return m, nil
+		}
+		if docker.IsContainerPaused(c) {
+			return m, m.executeContainerOp("unpause", c.ID)
+		}
+		return m, m.executeContainerOp("pause", c.ID)
+	}
+	for _, c := range marked {
+		if !docker.IsContainerPaused(c) {
+			bm, _ := m.showBulkPrompt("pause", "Pause", "container")
+			return bm, nil
+		}
+	}
+	bm, _ := m.showBulkPrompt("unpause", "Unpause", "container")
+	return bm, nil
+}
+
 func (m model) executeContainerOp(tag, id string) tea.Cmd {
 	daemon := m.daemon
+	// Container operations go through containerAPI so that, with all hosts
+	// shown, they reach the daemon owning the container.
+	containers := m.containerAPI()
 	return func() tea.Msg {
 		var err error
 		var successMsg string
 		switch tag {
 		case "kill":
-			err = daemon.Kill(id)
+			err = containers.Kill(id)
 			successMsg = fmt.Sprintf("Container %s killed", shortID(id))
 		case "stop":
-			err = daemon.StopContainer(id)
+			err = containers.StopContainer(id)
 			successMsg = fmt.Sprintf("Container %s stopped", shortID(id))
 		case "restart":
-			err = daemon.RestartContainer(id)
+			err = containers.RestartContainer(id)
 			successMsg = fmt.Sprintf("Container %s restarted", shortID(id))
+		case "pause":
+			err = containers.PauseContainer(id)
+			successMsg = fmt.Sprintf("Container %s paused", shortID(id))
+		case "unpause":
+			err = containers.UnpauseContainer(id)
+			successMsg = fmt.Sprintf("Container %s unpaused", shortID(id))
 		case "rm":
-			err = daemon.Rm(id)
+			err = containers.Rm(id)
 			successMsg = fmt.Sprintf("Container %s removed", shortID(id))
 		case "rm-all-stopped":
 			var count int
-			count, err = daemon.RemoveAllStoppedContainers()
+			count, err = containers.RemoveAllStoppedContainers()
 			successMsg = fmt.Sprintf("Removed %d stopped containers", count)
 		case "rmi":
 			_, err = daemon.Rmi(id, false)
@@ -196,7 +260,30 @@ func (m model) executeInputOp(tag, id, value string) tea.Cmd {
 			value = "/bin/sh"
 		}
 		command := strings.Fields(value)
-		return execContainerCmd(daemon, id, command)
+		return execContainerCmd(m.daemonFor(id), id, command)
+	case "save-view":
+		return m.saveViewCmd(value)
+	case "files-copy-out", "files-copy-in":
+		return m.copyFilesCmd(tag == "files-copy-out", id, strings.TrimSpace(value))
+	case "export", "image-save", "image-load":
+		return m.archiveCmd(tag, id, value)
+	case "rename":
+		name := strings.TrimPrefix(strings.TrimSpace(value), "/")
+		if name == "" {
+			return nil
+		}
+		containers := m.containerAPI()
+		return func() tea.Msg {
+			if err := containers.RenameContainer(id, name); err != nil {
+				return statusMessageMsg{
+					text:   fmt.Sprintf("Rename error: %s", err),
+					expiry: 5 * time.Second,
+				}
+			}
+			return operationSuccessMsg{
+				message: fmt.Sprintf("Container %s renamed to %s", shortID(id), name),
+			}
+		}
 	case "service-scale":
 		var replicas uint64
 		if _, err := fmt.Sscanf(value, "%d", &replicas); err != nil {
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/mocks"
+)
+
+// opsRecordingDaemon records the pause, unpause and rename operations it
+// is asked for.
+type opsRecordingDaemon struct {
+	mocks.DockerDaemonMock
+	ops []string
+}
+
+func (d *opsRecordingDaemon) PauseContainer(id string) error {
+	d.ops = append(d.ops, "pause "+id)
+	return nil
+}
+
+func (d *opsRecordingDaemon) UnpauseContainer(id string) error {
+	d.ops = append(d.ops, "unpause "+id)
+	return nil
+}
+
+func (d *opsRecordingDaemon) RenameContainer(id, name string) error {
+	d.ops = append(d.ops, "rename "+id+" "+name)
+	return nil
+}
+
+func TestModel_PauseKeyTogglesPause(t *testing.T) {
+	m := newTestModel()
+	daemon := &opsRecordingDaemon{}
+	m.daemon = daemon
+	running := &docker.Container{Summary: container.Summary{ID: "c1", Names: []string{"/web"}, Status: "Up 1 minute"}}
+	m.containers.SetContainers([]*docker.Container{running})
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'z', Text: "z"})
+	if cmd == nil {
+		t.Fatal("expected z to pause the container")
+	}
+	if msg, ok := cmd().(operationSuccessMsg); !ok || msg.message != "Container c1 paused" {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+
+	running.State = container.StatePaused
+	running.Status = "Up 1 minute (Paused)"
+	m.containers.SetContainers([]*docker.Container{running})
+	_, cmd = m.Update(tea.KeyPressMsg{Code: 'z', Text: "z"})
+	cmd()
+	if len(daemon.ops) != 2 || daemon.ops[0] != "pause c1" || daemon.ops[1] != "unpause c1" {
+		t.Fatalf("unexpected operations %v", daemon.ops)
+	}
+}
+
+func TestModel_RenameContainer(t *testing.T) {
+	m := newTestModel()
+	daemon := &opsRecordingDaemon{}
+	m.daemon = daemon
+	m.containers.SetContainers([]*docker.Container{
+		{Summary: container.Summary{ID: "c1", Names: []string{"/web"}, Status: "Up 1 minute"}},
+	})
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: 'R', Text: "R"})
+	m = result.(model)
+	if m.overlay != overlayInputPrompt {
+		t.Fatal("expected R to ask for the new name")
+	}
+
+	_, cmd := m.Update(appui.InputPromptResultMsg{Tag: "rename", ID: "c1", Value: " /api "})
+	if cmd == nil {
+		t.Fatal("expected the container to be renamed")
+	}
+	if msg, ok := cmd().(operationSuccessMsg); !ok || msg.message != "Container c1 renamed to api" {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+	if len(daemon.ops) != 1 || daemon.ops[0] != "rename c1 api" {
+		t.Fatalf("unexpected operations %v", daemon.ops)
+	}
+	if cmd := m.executeInputOp("rename", "c1", "  "); cmd != nil {
+		t.Fatal("expected an empty name to be ignored")
+	}
+}
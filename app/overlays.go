@@ -18,6 +18,9 @@ const (
 	overlayContainerMenu
 	overlayCommandPalette
 	overlayQuickPeek
+	overlayColumnPicker
+	overlayForm
+	overlayFiles
 )
 
 func (m model) handleOverlayKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
@@ -46,6 +49,24 @@ func (m model) handleOverlayKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.quickPeek, cmd = m.quickPeek.Update(msg)
 		return m, cmd
+	case overlayColumnPicker:
+		var cmd tea.Cmd
+		m.columnPicker, cmd = m.columnPicker.Update(msg)
+		return m, cmd
+	case overlayForm:
+		var cmd tea.Cmd
+		m.form, cmd = m.form.Update(msg)
+		return m, cmd
+	case overlayFiles:
+		// Copies are cancelled from the browser as other transfers are.
+		if msg.String() == "ctrl+x" {
+			if cm, ok := m.cancelTransfers(); ok {
+				return cm, nil
+			}
+		}
+		var cmd tea.Cmd
+		m.files, cmd = m.files.Update(msg)
+		return m, cmd
 	}
 	return m, nil
 }
@@ -69,7 +90,7 @@ func (m model) openQuickPeek() (tea.Model, tea.Cmd) {
 	if m.daemon == nil {
 		return m, nil
 	}
-	return m, loadQuickPeekCmd(m.daemon, ctx)
+	return m, loadQuickPeekCmd(m.daemonFor(ctx.containerID), ctx)
 }
 
 func (m model) showPrompt(message, tag, id string) model {
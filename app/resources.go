@@ -0,0 +1,99 @@
+package app
+
+// Resources form: changes the resource limits and restart policy of a
+// container, as docker update does.
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+// resourcesFormTag tags the resources form.
+const resourcesFormTag = "update"
+
+// resourcesFormFields are the fields of the resources form, holding the
+// given options.
+func resourcesFormFields(o docker.ResourceOptions) []appui.FormField {
+	return []appui.FormField{
+		{Key: "memory", Label: "Memory", Placeholder: "512m, none for unlimited", Value: o.Memory},
+		{Key: "memory-swap", Label: "Memory + swap", Placeholder: "1g, none for unlimited swap", Value: o.MemorySwap},
+		{Key: "cpu-shares", Label: "CPU shares", Placeholder: "1024", Value: o.CPUShares},
+		{Key: "cpu-quota", Label: "CPU quota", Placeholder: "50000 (µs per 100ms period), none for unlimited", Value: o.CPUQuota},
+		{Key: "cpuset-cpus", Label: "CPUs allowed", Placeholder: "0-3 or 0,1", Value: o.CpusetCPUs},
+		{Key: "pids-limit", Label: "PIDs limit", Placeholder: "200, none for unlimited", Value: o.PidsLimit},
+		{Key: "restart", Label: "Restart", Placeholder: "no, always, unless-stopped or on-failure[:retries]", Value: o.Restart},
+	}
+}
+
+// resourceOptions reads the options of docker update from the resources
+// form.
+func resourceOptions(v appui.FormValues) docker.ResourceOptions {
+	return docker.ResourceOptions{
+		Memory:     v.String("memory"),
+		MemorySwap: v.String("memory-swap"),
+		CPUShares:  v.String("cpu-shares"),
+		CPUQuota:   v.String("cpu-quota"),
+		CpusetCPUs: v.String("cpuset-cpus"),
+		PidsLimit:  v.String("pids-limit"),
+		Restart:    v.String("restart"),
+	}
+}
+
+// resourcesUpdatedMsg reports the update of the resources of a container.
+type resourcesUpdatedMsg struct {
+	id  string
+	err error
+}
+
+// showResourcesForm opens the resources form for the given container, with
+// its current limits filled in.
+func (m model) showResourcesForm(c *docker.Container) (model, tea.Cmd) {
+	if c == nil {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.form, cmd = appui.NewFormModel(
+		fmt.Sprintf("Update resources of %s", paletteContainerLabel(c)),
+		resourcesFormTag, c.ID, resourcesFormFields(docker.ResourceOptionsOf(c.Detail.HostConfig)))
+	m.form.SetNote("An empty field is left as it is; none removes the memory, swap, CPU quota or PIDs limit.")
+	m.form.SetSize(m.width, m.height)
+	m.overlay = overlayForm
+	return m, cmd
+}
+
+// submitResourcesForm updates the container with the options of the
+// resources form, keeping the form open until it is done.
+func (m model) submitResourcesForm(msg appui.FormSubmittedMsg) (tea.Model, tea.Cmd) {
+	m.form.SetBusy(fmt.Sprintf("Updating container %s...", shortID(msg.ID)))
+	containers := m.containerAPI()
+	id, opts := msg.ID, resourceOptions(msg.Values)
+	return m, func() tea.Msg {
+		return resourcesUpdatedMsg{id: id, err: containers.UpdateContainerResources(id, opts)}
+	}
+}
+
+// resourcesUpdated closes the resources form once the container is updated,
+// showing its new memory limit in the monitor, or shows in the form why it
+// was not.
+func (m model) resourcesUpdated(msg resourcesUpdatedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if m.overlay == overlayForm {
+			m.form.SetError(msg.err.Error())
+			return m, nil
+		}
+		m.messageBar.SetMessage(fmt.Sprintf("Update error: %s", msg.err), 8*time.Second)
+		return m, nil
+	}
+	if m.overlay == overlayForm {
+		m.overlay = overlayNone
+	}
+	if c := m.containerAPI().ContainerByID(msg.id); c != nil && c.Detail.HostConfig != nil {
+		m.monitor.SetMemoryLimit(msg.id, c.Detail.HostConfig.Memory)
+	}
+	m.messageBar.SetMessage(fmt.Sprintf("Container %s resources updated", shortID(msg.id)), 5*time.Second)
+	return m, m.loadViewData(m.view)
+}
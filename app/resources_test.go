@@ -0,0 +1,80 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/mocks"
+)
+
+// resourcesRecordingDaemon records the resource updates it is asked for,
+// failing them with err if set.
+type resourcesRecordingDaemon struct {
+	mocks.DockerDaemonMock
+	c       *docker.Container
+	updates []docker.ResourceOptions
+	err     error
+}
+
+func (d *resourcesRecordingDaemon) ContainerByID(id string) *docker.Container {
+	return d.c
+}
+
+func (d *resourcesRecordingDaemon) UpdateContainerResources(id string, opts docker.ResourceOptions) error {
+	d.updates = append(d.updates, opts)
+	return d.err
+}
+
+func TestModel_UpdateContainerResources(t *testing.T) {
+	m := newTestModel()
+	c := &docker.Container{
+		Summary: container.Summary{ID: "c1", Names: []string{"/web"}, Status: "Up 1 minute"},
+		Detail: container.InspectResponse{HostConfig: &container.HostConfig{
+			Resources: container.Resources{Memory: 256 * 1024 * 1024},
+		}},
+	}
+	daemon := &resourcesRecordingDaemon{c: c, err: errors.New("Minimum memory limit allowed is 6MB")}
+	m.daemon = daemon
+	m.containers.SetContainers([]*docker.Container{c})
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: 'u', Text: "u"})
+	m = result.(model)
+	if m.overlay != overlayForm {
+		t.Fatal("expected u to open the resources form")
+	}
+	if got := m.form.Values().String("memory"); got != "256m" {
+		t.Fatalf("expected the current memory limit to be filled in, got %q", got)
+	}
+	if !strings.Contains(m.form.View(), "none removes") {
+		t.Fatal("expected the form to tell how to remove a limit")
+	}
+
+	submit := func() {
+		result, cmd := m.Update(appui.FormSubmittedMsg{Tag: resourcesFormTag, ID: "c1", Values: m.form.Values()})
+		m = result.(model)
+		result, _ = m.Update(cmd())
+		m = result.(model)
+	}
+	submit()
+	if m.overlay != overlayForm || len(daemon.updates) != 1 || daemon.updates[0].Memory != "256m" {
+		t.Fatalf("expected the form to stay open on errors, got %v", daemon.updates)
+	}
+
+	daemon.err = nil
+	m.monitor.SetSize(200, 25)
+	result, _ = m.Update(appui.MonitorStatsMsg{CID: "c1", Stats: &docker.Stats{CID: "c1", Name: "web", Memory: 64 * 1024 * 1024, MemoryLimit: 256 * 1024 * 1024}})
+	m = result.(model)
+	c.Detail.HostConfig.Memory = 512 * 1024 * 1024
+	submit()
+	if m.overlay != overlayNone {
+		t.Fatal("expected the form to close once the container is updated")
+	}
+	if s := m.monitor.StatsByID("c1"); s == nil || s.MemoryLimit != 512*1024*1024 || s.MemoryPercentage != 12.5 {
+		t.Fatalf("expected the monitor to show the new memory limit, got %+v", s)
+	}
+}
@@ -0,0 +1,141 @@
+package app
+
+// Run form: creates and starts a container from an image with the options
+// of docker run, attaching to it unless detached.
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+// runFormTag tags the run form.
+const runFormTag = "run"
+
+// runFormFields are the fields of the run form, in the order of the
+// options of docker run they stand for.
+var runFormFields = []appui.FormField{
+	{Key: "name", Label: "Name", Placeholder: "generated if empty"},
+	{Key: "command", Label: "Command", Placeholder: "the image command if empty"},
+	{Key: "entrypoint", Label: "Entrypoint", Placeholder: "the image entrypoint if empty"},
+	{Key: "env", Label: "Env", Placeholder: "KEY=value, KEY, ..."},
+	{Key: "env-file", Label: "Env files", Placeholder: "./app.env, ..."},
+	{Key: "ports", Label: "Ports", Placeholder: "8080:80, 127.0.0.1:8443:443/tcp, ... (the exposed ports if empty)"},
+	{Key: "volumes", Label: "Volumes", Placeholder: "data:/var/lib/data, /srv/conf:/etc/app:ro, ..."},
+	{Key: "network", Label: "Network", Placeholder: "bridge"},
+	{Key: "restart", Label: "Restart", Placeholder: "no, always, unless-stopped or on-failure[:retries]"},
+	{Key: "labels", Label: "Labels", Placeholder: "key=value, ..."},
+	{Key: "memory", Label: "Memory", Placeholder: "512m"},
+	{Key: "cpus", Label: "CPUs", Placeholder: "1.5"},
+	{Key: "user", Label: "User", Placeholder: "name|uid[:group|gid]"},
+	{Key: "workdir", Label: "Workdir", Placeholder: "/"},
+	{Key: "detach", Label: "Detach", Toggle: true, On: true},
+	{Key: "interactive", Label: "Interactive", Toggle: true},
+	{Key: "tty", Label: "TTY", Toggle: true},
+	{Key: "rm", Label: "Auto remove", Toggle: true},
+}
+
+// runOptions reads the options of docker run from the run form.
+func runOptions(v appui.FormValues) docker.RunOptions {
+	return docker.RunOptions{
+		Name:        v.String("name"),
+		Command:     v.String("command"),
+		Entrypoint:  v.String("entrypoint"),
+		Env:         v.List("env"),
+		EnvFiles:    v.List("env-file"),
+		Ports:       v.List("ports"),
+		Volumes:     v.List("volumes"),
+		Network:     v.String("network"),
+		Restart:     v.String("restart"),
+		Labels:      v.List("labels"),
+		Memory:      v.String("memory"),
+		CPUs:        v.String("cpus"),
+		User:        v.String("user"),
+		Workdir:     v.String("workdir"),
+		Detach:      v.Bool("detach"),
+		Interactive: v.Bool("interactive"),
+		TTY:         v.Bool("tty"),
+		AutoRemove:  v.Bool("rm"),
+	}
+}
+
+// imageRunMsg reports the container run from an image, and the session
+// attached to it unless detached.
+type imageRunMsg struct {
+	id      string
+	err     error
+	session *docker.AttachSession
+}
+
+// showRunForm opens the run form for the selected image.
+func (m model) showRunForm() (tea.Model, tea.Cmd) {
+	img := m.images.SelectedImage()
+	if img == nil {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.form, cmd = appui.NewFormModel(fmt.Sprintf("Run %s", imageLabel(*img)), runFormTag, img.ID, runFormFields)
+	m.form.SetSize(m.width, m.height)
+	m.overlay = overlayForm
+	return m, cmd
+}
+
+// submitRunForm runs a container with the options of the run form,
+// keeping the form open until the container starts.
+func (m model) submitRunForm(msg appui.FormSubmittedMsg) (tea.Model, tea.Cmd) {
+	img, err := m.daemon.ImageByID(msg.ID)
+	if err != nil {
+		m.form.SetError(err.Error())
+		return m, nil
+	}
+	m.form.SetBusy(fmt.Sprintf("Starting a container of %s...", imageLabel(img)))
+	return m, runImageCmd(m.daemon, img, runOptions(msg.Values))
+}
+
+func runImageCmd(daemon docker.ImageAPI, img image.Summary, opts docker.RunOptions) tea.Cmd {
+	return func() tea.Msg {
+		if !opts.Detach {
+			session, err := daemon.RunImageAttached(img, opts)
+			if err != nil || session == nil {
+				return imageRunMsg{err: err}
+			}
+			return imageRunMsg{id: session.ID, session: session}
+		}
+		id, err := daemon.RunImage(img, opts)
+		return imageRunMsg{id: id, err: err}
+	}
+}
+
+// imageRun closes the run form once the container started, attaching to
+// it unless detached, or shows in the form why it did not.
+func (m model) imageRun(msg imageRunMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if m.overlay == overlayForm {
+			m.form.SetError(strings.TrimPrefix(msg.err.Error(), "run image: "))
+			return m, nil
+		}
+		m.messageBar.SetMessage(fmt.Sprintf("Run error: %s", msg.err), 8*time.Second)
+		return m, nil
+	}
+	if m.overlay == overlayForm {
+		m.overlay = overlayNone
+	}
+	if msg.session != nil {
+		return m, attachSessionCmd(msg.session)
+	}
+	m.messageBar.SetMessage(fmt.Sprintf("Container %s started", shortID(msg.id)), 5*time.Second)
+	return m, nil
+}
+
+// imageLabel names an image by its first tag, or its ID if it has none.
+func imageLabel(img image.Summary) string {
+	if len(img.RepoTags) > 0 && img.RepoTags[0] != "<none>:<none>" {
+		return img.RepoTags[0]
+	}
+	return docker.TruncateID(docker.ImageID(img.ID))
+}
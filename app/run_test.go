@@ -0,0 +1,56 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moncho/dry/appui"
+)
+
+func TestModel_RunFormFromImages(t *testing.T) {
+	m := newTestModel()
+	m.view = Images
+	imgs, err := m.daemon.Images()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.images.SetImages(imgs)
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	m = result.(model)
+	if m.overlay != overlayForm || !strings.Contains(m.View().Content, "Run dry/dry:") {
+		t.Fatalf("expected the run form to open:\n%s", m.View().Content)
+	}
+
+	result, cmd := m.Update(appui.FormSubmittedMsg{Tag: runFormTag, ID: m.images.SelectedImage().ID, Values: m.form.Values()})
+	m = result.(model)
+	if cmd == nil || m.overlay != overlayForm {
+		t.Fatal("expected the form to stay open while the container starts")
+	}
+	run, ok := cmd().(imageRunMsg)
+	if !ok || run.session != nil {
+		t.Fatalf("expected a detached run, got %+v", run)
+	}
+
+	result, _ = m.Update(imageRunMsg{err: errors.New(`run image: invalid port "x"`)})
+	m = result.(model)
+	if m.overlay != overlayForm || !strings.Contains(m.form.View(), `invalid port "x"`) {
+		t.Fatal("expected the error to be shown in the form")
+	}
+
+	result, _ = m.Update(imageRunMsg{id: "0123456789abcdef"})
+	m = result.(model)
+	if m.overlay != overlayNone || m.messageBar.Message() != "Container 0123456789ab started" {
+		t.Fatalf("expected the form to close, message %q", m.messageBar.Message())
+	}
+}
+
+func TestRunOptionsFromForm(t *testing.T) {
+	form, _ := appui.NewFormModel("Run", runFormTag, "", runFormFields)
+	opts := runOptions(form.Values())
+	if !opts.Detach || opts.TTY || opts.Ports != nil || opts.Name != "" {
+		t.Fatalf("unexpected defaults %+v", opts)
+	}
+}
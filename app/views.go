@@ -0,0 +1,301 @@
+package app
+
+// Saved views: named filter, sort and column sets of a list, kept in the
+// [[views]] tables of the configuration file and recalled from the command
+// palette.
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/BurntSushi/toml"
+	"github.com/moncho/dry/appui"
+)
+
+// SavedView is a named filter, sort and column set of a list view.
+type SavedView struct {
+	Name    string   `toml:"name"`
+	View    string   `toml:"view"`
+	Filter  string   `toml:"filter,omitempty"`
+	Sort    string   `toml:"sort,omitempty"`
+	Columns []string `toml:"columns,omitempty"`
+}
+
+// viewList is what a saved view sets on the list it is recalled on.
+type viewList interface {
+	FilterText() string
+	SetFilterText(text string) error
+	SortColumn() string
+	SortBy(column string) bool
+}
+
+// savedViewLists maps the view names a saved view can be taken on to their
+// list. Containers are sorted by the daemon and are handled apart.
+var savedViewLists = map[string]func(m *model) viewList{
+	"images":   func(m *model) viewList { return &m.images },
+	"networks": func(m *model) viewList { return &m.networks },
+	"volumes":  func(m *model) viewList { return &m.volumes },
+	"nodes":    func(m *model) viewList { return &m.nodes },
+	"services": func(m *model) viewList { return &m.services },
+	"stacks":   func(m *model) viewList { return &m.stacks },
+	"compose":  func(m *model) viewList { return &m.composeProjects },
+}
+
+// swarmViews lists the views only shown in Swarm mode.
+var swarmViews = []string{"nodes", "services", "stacks"}
+
+func isSavedViewName(view string) bool {
+	_, ok := savedViewLists[view]
+	return ok || view == "containers"
+}
+
+func (v SavedView) validate() error {
+	if v.Name == "" {
+		return errors.New("[[views]]: name is required")
+	}
+	if !isSavedViewName(v.View) {
+		return fmt.Errorf("[[views]] %s: unknown view %q", v.Name, v.View)
+	}
+	if v.View == "containers" && v.Sort != "" {
+		if _, ok := containerSortModes[strings.ToLower(v.Sort)]; !ok {
+			return fmt.Errorf("[[views]] %s: unknown container sort %q", v.Name, v.Sort)
+		}
+	}
+	if len(v.Columns) > 0 {
+		if _, ok := columnViews[v.View]; !ok {
+			return fmt.Errorf("[[views]] %s: the %s view takes no columns", v.Name, v.View)
+		}
+		if _, err := parseColumnChoices(v.View, v.Columns); err != nil {
+			return fmt.Errorf("[[views]] %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// currentViewName returns the configuration name of the view shown.
+func (m model) currentViewName() string {
+	for name, v := range viewNames {
+		if v == m.view {
+			return name
+		}
+	}
+	return ""
+}
+
+// captureView returns the filter, sort and columns of the view shown, saved
+// under the given name.
+func (m *model) captureView(name string) (SavedView, bool) {
+	view := m.currentViewName()
+	if !isSavedViewName(view) {
+		return SavedView{}, false
+	}
+	v := SavedView{Name: name, View: view}
+	if view == "containers" {
+		v.Filter = m.containers.FilterText()
+		for s, mode := range containerSortModes {
+			if mode == m.containers.SortMode() {
+				v.Sort = s
+			}
+		}
+	} else {
+		list := savedViewLists[view](m)
+		v.Filter = list.FilterText()
+		v.Sort = strings.ToLower(list.SortColumn())
+	}
+	if layout, ok := columnViews[view]; ok {
+		for _, c := range layout(m).ColumnChoices() {
+			v.Columns = append(v.Columns, c.String())
+		}
+	}
+	return v, true
+}
+
+// recallView shows the view a saved view was taken on, with its columns,
+// sort and filter.
+func (m model) recallView(name string) (tea.Model, tea.Cmd) {
+	i := slices.IndexFunc(m.config.Views, func(v SavedView) bool { return v.Name == name })
+	if i < 0 {
+		return m, nil
+	}
+	v := m.config.Views[i]
+	var problems []string
+	if layout, ok := columnViews[v.View]; ok && len(v.Columns) > 0 {
+		choices, err := parseColumnChoices(v.View, v.Columns)
+		if err == nil {
+			err = layout(&m).SetColumnChoices(choices)
+		}
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	var filterErr error
+	if v.View == "containers" {
+		if mode, ok := containerSortModes[strings.ToLower(v.Sort)]; ok {
+			m.containers.SetSortMode(mode)
+		}
+		filterErr = m.containers.SetFilterText(v.Filter)
+	} else {
+		list := savedViewLists[v.View](&m)
+		if v.Sort != "" && !list.SortBy(v.Sort) {
+			problems = append(problems, fmt.Sprintf("unknown sort column %q", v.Sort))
+		}
+		filterErr = list.SetFilterText(v.Filter)
+	}
+	if filterErr != nil {
+		problems = append(problems, "filter: "+filterErr.Error())
+	}
+	if len(problems) > 0 {
+		m.messageBar.SetMessage(
+			fmt.Sprintf("View %s: %s", v.Name, strings.Join(problems, "; ")), 5*time.Second)
+	}
+	target := viewNames[v.View]
+	if m.view == target {
+		return m, m.loadViewData(target)
+	}
+	return m.switchView(target)
+}
+
+// openSaveViewPrompt asks for the name to save the view shown under.
+func (m model) openSaveViewPrompt() (tea.Model, tea.Cmd) {
+	if !isSavedViewName(m.currentViewName()) {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.inputPrompt, cmd = appui.NewInputPromptModelWithLimit(
+		"Save view as:", "on-call exited", "save-view", "", 60)
+	m.inputPrompt.SetSize(m.width, m.height)
+	m.overlay = overlayInputPrompt
+	return m, cmd
+}
+
+// saveViewCmd saves the view shown under the given name, writing it to the
+// configuration file when there is one.
+func (m *model) saveViewCmd(name string) tea.Cmd {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return func() tea.Msg {
+			return statusMessageMsg{text: "A saved view needs a name", expiry: 5 * time.Second}
+		}
+	}
+	v, ok := m.captureView(name)
+	if !ok {
+		return nil
+	}
+	path := m.config.ConfigFile
+	return func() tea.Msg {
+		if path == "" {
+			return viewSavedMsg{view: v}
+		}
+		return viewSavedMsg{view: v, path: path, err: writeSavedView(path, v)}
+	}
+}
+
+// viewSaved keeps a saved view for the session, replacing any view saved
+// under the same name.
+func (m model) viewSaved(msg viewSavedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.messageBar.SetMessage(fmt.Sprintf("Could not save view %s: %s", msg.view.Name, msg.err), 5*time.Second)
+		return m, nil
+	}
+	views := slices.DeleteFunc(slices.Clone(m.config.Views), func(v SavedView) bool {
+		return v.Name == msg.view.Name
+	})
+	m.config.Views = append(views, msg.view)
+	if msg.path == "" {
+		m.messageBar.SetMessage(fmt.Sprintf("View %s saved until dry exits, there is no configuration file", msg.view.Name), 5*time.Second)
+	} else {
+		m.messageBar.SetMessage(fmt.Sprintf("View %s saved to %s", msg.view.Name, msg.path), 3*time.Second)
+	}
+	return m, nil
+}
+
+// savedViewActions lists the saved views that can be recalled in the
+// command palette.
+func (m model) savedViewActions() []paletteAction {
+	var actions []paletteAction
+	for _, v := range m.config.Views {
+		if !m.swarmMode && slices.Contains(swarmViews, v.View) {
+			continue
+		}
+		desc := v.View
+		if v.Filter != "" {
+			desc += ": " + v.Filter
+		}
+		actions = append(actions, paletteAction{
+			Group:       "Saved Views",
+			ID:          "saved-view:" + v.Name,
+			Title:       v.Name,
+			Description: desc,
+			Search:      strings.Join([]string{"Saved Views view", v.Name, v.View, v.Filter}, " "),
+		})
+	}
+	return actions
+}
+
+// writeSavedView writes a saved view to the configuration file as a
+// [[views]] table, replacing the table of a view with the same name. The
+// rest of the file is left as it is.
+func writeSavedView(path string, v SavedView) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	data = removeSavedView(data, v.Name)
+	var table bytes.Buffer
+	enc := toml.NewEncoder(&table)
+	enc.Indent = ""
+	if err := enc.Encode(struct {
+		Views []SavedView `toml:"views"`
+	}{[]SavedView{v}}); err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		data = append(bytes.TrimRight(data, "\n"), "\n\n"...)
+	}
+	data = append(data, table.Bytes()...)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// removeSavedView removes the [[views]] table of the view with the given
+// name from the contents of a configuration file. A table runs until the
+// next table header.
+func removeSavedView(data []byte, name string) []byte {
+	lines := strings.SplitAfter(string(data), "\n")
+	var out, table []string
+	flush := func() {
+		var v SavedView
+		if _, err := toml.Decode(strings.Join(table[1:], ""), &v); err != nil || v.Name != name {
+			out = append(out, table...)
+		}
+		table = nil
+	}
+	for _, line := range lines {
+		header := strings.HasPrefix(strings.TrimSpace(line), "[")
+		if header && table != nil {
+			flush()
+		}
+		switch {
+		case strings.TrimSpace(line) == "[[views]]":
+			table = []string{line}
+		case table != nil:
+			table = append(table, line)
+		default:
+			out = append(out, line)
+		}
+	}
+	if table != nil {
+		flush()
+	}
+	return []byte(strings.Join(out, ""))
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moncho/dry/appui"
+	"github.com/moncho/dry/docker"
+)
+
+func TestModel_SaveAndRecallView(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dry", "config.toml")
+	m := NewModel(Config{ConfigFile: path})
+	m.view = Images
+	if err := m.images.SetColumnChoices(appui.DefaultColumnChoices("repository", "size")); err != nil {
+		t.Fatal(err)
+	}
+	m.images.SortBy("size")
+	if err := m.images.SetFilterText("dangling=true"); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := m.saveViewCmd("cleanup")()
+	m2, _ := m.Update(msg)
+	m = m2.(model)
+	if len(m.config.Views) != 1 {
+		t.Fatalf("expected the view to be kept, got %+v", m.config.Views)
+	}
+	want := SavedView{
+		Name: "cleanup", View: "images", Filter: "dangling=true", Sort: "size",
+		Columns: []string{"repository", "size"},
+	}
+	if got := m.config.Views[0]; got.Name != want.Name || got.Filter != want.Filter ||
+		got.Sort != want.Sort || strings.Join(got.Columns, ",") != strings.Join(want.Columns, ",") {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	file, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Views) != 1 || file.Views[0].Filter != "dangling=true" {
+		t.Fatalf("expected the view in the configuration file, got %+v", file.Views)
+	}
+
+	m = NewModel(Config{Views: file.Views})
+	m2, _ = m.executePaletteAction("saved-view:cleanup")
+	m = m2.(model)
+	if m.view != Images {
+		t.Fatalf("expected the images view, got %d", m.view)
+	}
+	if m.images.FilterText() != "dangling=true" || m.images.SortColumn() != "SIZE" {
+		t.Fatalf("expected filter and sort to be recalled, got %q sorted by %q",
+			m.images.FilterText(), m.images.SortColumn())
+	}
+	if got := m.images.ColumnChoices(); len(got) != 2 || got[0].Key != "repository" {
+		t.Fatalf("expected the saved columns, got %+v", got)
+	}
+}
+
+func TestModel_RecallContainerView(t *testing.T) {
+	m := NewModel(Config{Views: []SavedView{
+		{Name: "exited", View: "containers", Filter: "status=exited", Sort: "name"},
+	}})
+	m.view = Images
+	m2, _ := m.executePaletteAction("saved-view:exited")
+	m = m2.(model)
+	if m.view != Main {
+		t.Fatalf("expected the container list, got %d", m.view)
+	}
+	if m.containers.SortMode() != docker.SortByName || m.containers.FilterText() != "status=exited" {
+		t.Fatalf("expected sort and filter to be recalled, got %d and %q",
+			m.containers.SortMode(), m.containers.FilterText())
+	}
+}
+
+func TestWriteSavedView_ReplacesSameName(t *testing.T) {
+	path := writeConfigFile(t, `theme = "light"
+
+[[views]]
+name = "a"
+view = "images"
+
+[[views]]
+name = "b"
+view = "volumes"
+
+[sort]
+images = "size"
+`)
+	if err := writeSavedView(path, SavedView{Name: "a", View: "networks", Filter: "driver=bridge"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme != "light" || cfg.Sort["images"] != "size" {
+		t.Fatalf("expected the rest of the file to be kept, got %+v", cfg)
+	}
+	if len(cfg.Views) != 2 || cfg.Views[0].Name != "b" || cfg.Views[1].View != "networks" {
+		t.Fatalf("expected view a to be replaced, got %+v", cfg.Views)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Count(string(data), "[[views]]") != 2 {
+		t.Fatalf("expected two view tables, got:\n%s", data)
+	}
+}
@@ -414,7 +414,7 @@ func (m model) pinWorkspaceContext(ctx workspaceContext) (tea.Model, tea.Cmd) {
 	if m.daemon == nil {
 		return m, nil
 	}
-	return m, loadWorkspaceActivityCmd(m.daemon, ctx, m.workspaceLogs.Width(), m.workspaceLogs.BodyHeight())
+	return m, loadWorkspaceActivityCmd(m.daemonFor(ctx.containerID), ctx, m.workspaceLogs.Width(), m.workspaceLogs.BodyHeight())
 }
 
 func (m *model) clearPinnedContext() model {
@@ -51,6 +51,15 @@ type workspaceContext struct {
 	monitorPct        float64
 	monitorCPUHistory []appui.MonitorPoint
 	monitorMemHistory []appui.MonitorPoint
+	monitorWindow     time.Duration
+	monitorNetRx      float64
+	monitorNetTx      float64
+	monitorBlockRead  float64
+	monitorBlockWrite float64
+	monitorPids       uint64
+	// monitorSeries holds the network, block IO and PIDs series, and the
+	// panel of charts shown.
+	monitorSeries appui.MonitorSeries
 }
 
 type monitorContainerLookup interface {
@@ -76,6 +85,9 @@ func workspaceContextFromContainer(c *docker.Container) workspaceContext {
 		fmt.Sprintf("status: %s", c.Status),
 		fmt.Sprintf("image: %s", c.Image),
 	}
+	if c.Host != "" {
+		lines = append(lines, fmt.Sprintf("host: %s", c.Host))
+	}
 	if c.Created > 0 {
 		lines = append(lines, fmt.Sprintf("created: %s", workspaceFormatUnix(c.Created)))
 	}
@@ -442,6 +454,13 @@ func workspaceContextFromStats(s *docker.Stats, lookup monitorContainerLookup, s
 		monitorPct:        s.MemoryPercentage,
 		monitorCPUHistory: append([]appui.MonitorPoint(nil), series.CPU...),
 		monitorMemHistory: append([]appui.MonitorPoint(nil), series.Memory...),
+		monitorWindow:     series.Window,
+		monitorNetRx:      s.NetworkRx,
+		monitorNetTx:      s.NetworkTx,
+		monitorBlockRead:  s.BlockRead,
+		monitorBlockWrite: s.BlockWrite,
+		monitorPids:       s.PidsCurrent,
+		monitorSeries:     series,
 	}
 }
 
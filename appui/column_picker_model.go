@@ -0,0 +1,201 @@
+package appui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// ColumnsChosenMsg is sent when the column picker is confirmed. No choices
+// means the view goes back to its default columns.
+type ColumnsChosenMsg struct {
+	Choices []ColumnChoice
+}
+
+// columnWidthStep is how much +/- change the width of a column.
+const columnWidthStep = 2
+
+// pickerColumn is a column listed by the picker.
+type pickerColumn struct {
+	option ColumnOption
+	shown  bool
+	width  int
+}
+
+// ColumnPickerModel lets the columns of a view be chosen, reordered and
+// sized.
+type ColumnPickerModel struct {
+	title   string
+	columns []pickerColumn
+	cursor  int
+	warning string
+	width   int
+	height  int
+}
+
+// NewColumnPickerModel creates a column picker listing the chosen columns
+// first, in their order, followed by the rest of the given options.
+func NewColumnPickerModel(title string, options []ColumnOption, chosen []ColumnChoice) ColumnPickerModel {
+	m := ColumnPickerModel{title: title}
+	picked := make(map[string]bool, len(chosen))
+	for _, c := range chosen {
+		for _, o := range options {
+			if o.Key == c.Key {
+				m.columns = append(m.columns, pickerColumn{option: o, shown: true, width: c.Width})
+				picked[o.Key] = true
+			}
+		}
+	}
+	for _, o := range options {
+		if !picked[o.Key] {
+			m.columns = append(m.columns, pickerColumn{option: o})
+		}
+	}
+	return m
+}
+
+// SetSize updates the picker dimensions.
+func (m *ColumnPickerModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// Choices returns the columns currently chosen, in order.
+func (m ColumnPickerModel) Choices() []ColumnChoice {
+	var choices []ColumnChoice
+	for _, c := range m.columns {
+		if c.shown {
+			choices = append(choices, ColumnChoice{Key: c.option.Key, Width: c.width})
+		}
+	}
+	return choices
+}
+
+// Update handles key events for the picker.
+func (m ColumnPickerModel) Update(msg tea.Msg) (ColumnPickerModel, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	m.warning = ""
+	switch key.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseOverlayMsg{} }
+	case "enter":
+		choices := m.Choices()
+		if len(choices) == 0 {
+			m.warning = "Choose at least one column"
+			return m, nil
+		}
+		return m, func() tea.Msg { return ColumnsChosenMsg{Choices: choices} }
+	case "r":
+		return m, func() tea.Msg { return ColumnsChosenMsg{} }
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.columns)-1 {
+			m.cursor++
+		}
+	case "space", " ":
+		if len(m.columns) > 0 {
+			m.columns[m.cursor].shown = !m.columns[m.cursor].shown
+		}
+	case "shift+up", "K":
+		if m.cursor > 0 {
+			m.columns[m.cursor], m.columns[m.cursor-1] = m.columns[m.cursor-1], m.columns[m.cursor]
+			m.cursor--
+		}
+	case "shift+down", "J":
+		if m.cursor < len(m.columns)-1 {
+			m.columns[m.cursor], m.columns[m.cursor+1] = m.columns[m.cursor+1], m.columns[m.cursor]
+			m.cursor++
+		}
+	case "+", "=":
+		m.resize(columnWidthStep)
+	case "-":
+		m.resize(-columnWidthStep)
+	}
+	return m, nil
+}
+
+// resize changes the width of the column under the cursor. A column with
+// its default sizing starts from its default width; shrinking it below
+// that brings the default sizing back.
+func (m *ColumnPickerModel) resize(delta int) {
+	if len(m.columns) == 0 {
+		return
+	}
+	c := &m.columns[m.cursor]
+	base := c.option.Column.Width
+	if base == 0 {
+		base = 10
+	}
+	switch {
+	case c.width == 0 && delta > 0:
+		c.width = base + delta
+	case c.width > 0:
+		c.width += delta
+		if c.width < columnWidthStep {
+			c.width = 0
+		}
+	}
+	if c.width > 0 {
+		c.shown = true
+	}
+}
+
+// View renders the column picker.
+func (m ColumnPickerModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(DryTheme.FgMuted).
+		Bold(true).
+		MarginBottom(1)
+	normal := lipgloss.NewStyle().Foreground(DryTheme.Fg).Padding(0, 0, 0, 2)
+	hidden := lipgloss.NewStyle().Foreground(DryTheme.FgMuted).Padding(0, 0, 0, 2)
+	selected := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(DryTheme.Primary).
+		Foreground(DryTheme.Fg).
+		Padding(0, 0, 0, 1)
+	hintStyle := lipgloss.NewStyle().Foreground(DryTheme.FgSubtle)
+
+	var lines []string
+	for i, c := range m.columns {
+		check := "[ ]"
+		if c.shown {
+			check = "[x]"
+		}
+		width := "auto"
+		if c.width > 0 {
+			width = fmt.Sprintf("%d", c.width)
+		}
+		line := fmt.Sprintf("%s %-14s %-4s", check, c.option.Column.Title, width)
+		switch {
+		case i == m.cursor:
+			line = selected.Render(line)
+		case c.shown:
+			line = normal.Render(line)
+		default:
+			line = hidden.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	parts := []string{titleStyle.Render(m.title), strings.Join(lines, "\n"), ""}
+	if m.warning != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(DryTheme.Warning).Render(m.warning))
+	}
+	parts = append(parts,
+		hintStyle.Render("space show/hide · K/J move · +/- width"),
+		hintStyle.Render("r defaults · esc back · enter apply"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(DryTheme.Border).
+		Padding(1, 2)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		box.Render(lipgloss.JoinVertical(lipgloss.Left, parts...)))
+}
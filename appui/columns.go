@@ -0,0 +1,161 @@
+package appui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/moncho/dry/docker/formatter"
+)
+
+// ColumnDef is a column a list view can show: the key it is chosen by, its
+// header and default sizing, and how an item's value is rendered.
+type ColumnDef[T any] struct {
+	Key    string
+	Column Column
+	Value  func(T) string
+}
+
+// ColumnCatalog lists the columns a view can show, in the order the column
+// picker offers them.
+type ColumnCatalog[T any] []ColumnDef[T]
+
+// ColumnChoice is a column chosen for a view. A non-zero Width fixes the
+// column to that many cells, zero keeps the column's default sizing.
+type ColumnChoice struct {
+	Key   string
+	Width int
+}
+
+// String renders the choice as it is written in the configuration file.
+func (c ColumnChoice) String() string {
+	if c.Width > 0 {
+		return fmt.Sprintf("%s:%d", c.Key, c.Width)
+	}
+	return c.Key
+}
+
+// ParseColumnChoice parses a column choice written as "key" or "key:width".
+func ParseColumnChoice(s string) (ColumnChoice, error) {
+	key, width, hasWidth := strings.Cut(strings.TrimSpace(s), ":")
+	c := ColumnChoice{Key: strings.ToLower(key)}
+	if c.Key == "" {
+		return c, fmt.Errorf("invalid column %q", s)
+	}
+	if hasWidth {
+		w, err := strconv.Atoi(width)
+		if err != nil || w <= 0 {
+			return c, fmt.Errorf("invalid width in column %q", s)
+		}
+		c.Width = w
+	}
+	return c, nil
+}
+
+// DefaultColumnChoices returns the given keys as choices with their
+// default sizing.
+func DefaultColumnChoices(keys ...string) []ColumnChoice {
+	choices := make([]ColumnChoice, len(keys))
+	for i, k := range keys {
+		choices[i] = ColumnChoice{Key: k}
+	}
+	return choices
+}
+
+// ColumnOption is a column offered by the column picker.
+type ColumnOption struct {
+	Key    string
+	Column Column
+}
+
+// ColumnLayout is implemented by the views whose columns can be chosen.
+// Setting no choices brings back the view's default columns.
+type ColumnLayout interface {
+	ColumnOptions() []ColumnOption
+	ColumnChoices() []ColumnChoice
+	SetColumnChoices(choices []ColumnChoice) error
+}
+
+// Options returns the columns of the catalog for the column picker.
+func (c ColumnCatalog[T]) Options() []ColumnOption {
+	options := make([]ColumnOption, len(c))
+	for i, def := range c {
+		options[i] = ColumnOption{Key: def.Key, Column: def.Column}
+	}
+	return options
+}
+
+// Check returns an error if a choice names a column the catalog does not
+// have, or names it twice.
+func (c ColumnCatalog[T]) Check(choices []ColumnChoice) error {
+	return CheckColumnChoices(c.Options(), choices)
+}
+
+// CheckColumnChoices returns an error if a choice names a column that is
+// not among the given options, or names it twice.
+func CheckColumnChoices(options []ColumnOption, choices []ColumnChoice) error {
+	seen := make(map[string]bool, len(choices))
+	for _, choice := range choices {
+		if !slices.ContainsFunc(options, func(o ColumnOption) bool { return o.Key == choice.Key }) {
+			keys := make([]string, len(options))
+			for i, o := range options {
+				keys[i] = o.Key
+			}
+			return fmt.Errorf("unknown column %q, valid columns: %s", choice.Key, strings.Join(keys, ", "))
+		}
+		if seen[choice.Key] {
+			return fmt.Errorf("column %q chosen twice", choice.Key)
+		}
+		seen[choice.Key] = true
+	}
+	return nil
+}
+
+// Columns returns the table columns for the given choices.
+func (c ColumnCatalog[T]) Columns(choices []ColumnChoice) []Column {
+	columns := make([]Column, 0, len(choices))
+	for _, choice := range choices {
+		def, ok := c.def(choice.Key)
+		if !ok {
+			continue
+		}
+		col := def.Column
+		if choice.Width > 0 {
+			col.Width = choice.Width
+			col.Fixed = true
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// Values returns the values of the given item for the chosen columns.
+func (c ColumnCatalog[T]) Values(choices []ColumnChoice, item T) []string {
+	values := make([]string, 0, len(choices))
+	for _, choice := range choices {
+		if def, ok := c.def(choice.Key); ok {
+			values = append(values, def.Value(item))
+		}
+	}
+	return values
+}
+
+func (c ColumnCatalog[T]) def(key string) (ColumnDef[T], bool) {
+	for _, def := range c {
+		if def.Key == key {
+			return def, true
+		}
+	}
+	return ColumnDef[T]{}, false
+}
+
+// formatColumns returns the table columns of a row format, one per cell.
+func formatColumns(f *formatter.RowFormat) []Column {
+	headers := f.Headers()
+	columns := make([]Column, len(headers))
+	for i, h := range headers {
+		columns[i] = Column{Title: h}
+	}
+	return columns
+}
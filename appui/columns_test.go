@@ -0,0 +1,133 @@
+package appui
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moby/moby/api/types/image"
+)
+
+func TestParseColumnChoice(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ColumnChoice
+		wantErr bool
+	}{
+		{"labels", ColumnChoice{Key: "labels"}, false},
+		{" Image:30 ", ColumnChoice{Key: "image", Width: 30}, false},
+		{"image:0", ColumnChoice{}, true},
+		{"image:wide", ColumnChoice{}, true},
+		{":12", ColumnChoice{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseColumnChoice(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.in)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: got %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+		if got.String() != strings.ToLower(strings.TrimSpace(tt.in)) {
+			t.Errorf("%q: unexpected String() %q", tt.in, got.String())
+		}
+	}
+}
+
+func TestColumnCatalog_Check(t *testing.T) {
+	if err := imageColumns.Check(DefaultColumnChoices("size", "id")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := imageColumns.Check(DefaultColumnChoices("ports"))
+	if err == nil || !strings.Contains(err.Error(), `unknown column "ports"`) ||
+		!strings.Contains(err.Error(), "repository") {
+		t.Fatalf("expected unknown column listing the valid ones, got %v", err)
+	}
+	if err := imageColumns.Check(DefaultColumnChoices("id", "id")); err == nil {
+		t.Fatal("expected an error for a column chosen twice")
+	}
+}
+
+func TestImagesModel_SetColumnChoices(t *testing.T) {
+	m := NewImagesModel()
+	m.SetSize(120, 30)
+	m.SetImages([]image.Summary{{ID: "sha256:0123456789abcdef", RepoTags: []string{"nginx:latest"}, Size: 1024}})
+
+	err := m.SetColumnChoices([]ColumnChoice{{Key: "size", Width: 14}, {Key: "repository"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.table.columns) != 2 || m.table.columns[0].Title != "SIZE" || m.table.columns[0].Width != 14 {
+		t.Fatalf("unexpected columns %+v", m.table.columns)
+	}
+	row := m.table.SelectedRow()
+	if row == nil || row.Columns()[1] != "nginx" {
+		t.Fatalf("expected rows rebuilt for the chosen columns, got %v", row)
+	}
+
+	if err := m.SetColumnChoices(DefaultColumnChoices("bogus")); err == nil {
+		t.Fatal("expected an error for an unknown column")
+	}
+	if len(m.table.columns) != 2 {
+		t.Fatal("expected a rejected choice to leave the columns alone")
+	}
+
+	if err := m.SetColumnChoices(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.table.columns) != len(defaultImageColumns) {
+		t.Fatalf("expected the default columns back, got %d", len(m.table.columns))
+	}
+}
+
+func TestColumnPickerModel(t *testing.T) {
+	p := NewColumnPickerModel("Columns", imageColumns.Options(), DefaultColumnChoices("id", "tag"))
+	p.SetSize(80, 30)
+	if got := p.Choices(); len(got) != 2 || got[0].Key != "id" || got[1].Key != "tag" {
+		t.Fatalf("expected chosen columns listed first, got %+v", got)
+	}
+
+	p, _ = p.Update(tea.KeyPressMsg{Code: 'J', Text: "J"}) // move id below tag
+	p, _ = p.Update(tea.KeyPressMsg{Code: '+', Text: "+"}) // widen id
+	p, _ = p.Update(tea.KeyPressMsg{Code: 'j', Text: "j"}) // repository
+	p, _ = p.Update(tea.KeyPressMsg{Code: tea.KeySpace})   // show it
+	_, cmd := p.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected enter to apply the columns")
+	}
+	msg, ok := cmd().(ColumnsChosenMsg)
+	if !ok {
+		t.Fatalf("expected ColumnsChosenMsg, got %T", cmd())
+	}
+	want := []ColumnChoice{{Key: "tag"}, {Key: "id", Width: IDColumnWidth + columnWidthStep}, {Key: "repository"}}
+	if len(msg.Choices) != len(want) {
+		t.Fatalf("got %+v, want %+v", msg.Choices, want)
+	}
+	for i := range want {
+		if msg.Choices[i] != want[i] {
+			t.Fatalf("got %+v, want %+v", msg.Choices, want)
+		}
+	}
+	if p.View() == "" {
+		t.Fatal("View() should not be empty")
+	}
+}
+
+func TestColumnPickerModel_NeedsOneColumn(t *testing.T) {
+	p := NewColumnPickerModel("Columns", imageColumns.Options(), DefaultColumnChoices("id"))
+	p, _ = p.Update(tea.KeyPressMsg{Code: tea.KeySpace})
+	p, cmd := p.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd != nil {
+		t.Fatal("expected no columns to be refused")
+	}
+	if !strings.Contains(p.View(), "at least one column") {
+		t.Fatal("expected a warning about choosing a column")
+	}
+	_, cmd = p.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	if msg, ok := cmd().(ColumnsChosenMsg); !ok || msg.Choices != nil {
+		t.Fatalf("expected reset to send no choices, got %+v", cmd())
+	}
+}
@@ -50,6 +50,11 @@ func NewCommandPaletteModel(items []CommandPaletteItem) (CommandPaletteModel, te
 	return m, cmd
 }
 
+// SetPlaceholder replaces the hint shown while the input is empty.
+func (m *CommandPaletteModel) SetPlaceholder(placeholder string) {
+	m.input.Placeholder = placeholder
+}
+
 // SetSize sets the screen size used to center the palette.
 func (m *CommandPaletteModel) SetSize(w, h int) {
 	m.width = w
@@ -34,10 +34,21 @@ func newProjectHeaderRow(p docker.ComposeProject) projectHeaderRow {
 func (r projectHeaderRow) Columns() []string { return r.columns }
 func (r projectHeaderRow) ID() string        { return r.project.Name }
 
+// QueryFields returns the fields filter queries match the project by.
+func (r projectHeaderRow) QueryFields() appui.QueryFields {
+	return appui.QueryFields{
+		Values: map[string]string{
+			"project": r.project.Name,
+			"status":  string(r.project.Status),
+		},
+	}
+}
+
 // serviceDetailRow is an indented row for a service within a project.
 type serviceDetailRow struct {
 	service     docker.ComposeService
 	projectName string
+	sync        docker.ServiceSync
 	columns     []string
 }
 
@@ -45,6 +56,7 @@ func newServiceDetailRow(s docker.ComposeService, sync docker.ServiceSync) servi
 	return serviceDetailRow{
 		service:     s,
 		projectName: s.Project,
+		sync:        sync,
 		columns: []string{
 			"  " + s.Name,
 			fmt.Sprintf("%d", s.Containers),
@@ -115,6 +127,23 @@ func colorSync(s docker.ServiceSync) string {
 func (r serviceDetailRow) Columns() []string { return r.columns }
 func (r serviceDetailRow) ID() string        { return r.projectName + "/" + r.service.Name }
 
+// QueryFields returns the fields filter queries match the service by.
+func (r serviceDetailRow) QueryFields() appui.QueryFields {
+	return appui.QueryFields{
+		Values: map[string]string{
+			"project": r.projectName,
+			"service": r.service.Name,
+			"image":   r.service.Image,
+			"health":  r.service.Health,
+			"sync":    string(r.sync),
+		},
+	}
+}
+
+// projectQueryFields lists the fields filter queries can match in the
+// projects list. Project rows have a status, service rows the rest.
+var projectQueryFields = []string{"project", "status", "service", "image", "health", "sync"}
+
 // ProjectsLoadedMsg carries the loaded compose projects with services.
 type ProjectsLoadedMsg struct {
 	Projects []docker.ProjectWithServices
@@ -140,10 +169,12 @@ func NewProjectsModel() ProjectsModel {
 		{Title: "SYNC", Width: 7, Fixed: true},
 		{Title: "PORTS"},
 	}
-	return ProjectsModel{
+	m := ProjectsModel{
 		table:  appui.NewTableModel(columns),
 		filter: appui.NewFilterInputModel(),
 	}
+	m.table.SetQueryFields(projectQueryFields...)
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -231,7 +262,7 @@ func (m ProjectsModel) Update(msg tea.Msg) (ProjectsModel, tea.Cmd) {
 	if m.filter.Active() {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -269,6 +300,27 @@ func (m *ProjectsModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
 
+// SortBy sorts the list by the column with the given title.
+func (m *ProjectsModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
+
+// SortColumn returns the title of the column the list is sorted by.
+func (m ProjectsModel) SortColumn() string {
+	return m.table.SortTitle()
+}
+
+// FilterText returns the filter applied to the list.
+func (m ProjectsModel) FilterText() string {
+	return m.table.FilterText()
+}
+
+// SetFilterText filters the list as if text had been typed in the filter
+// input.
+func (m *ProjectsModel) SetFilterText(text string) error {
+	return m.table.SetFilter(text)
+}
+
 func (m ProjectsModel) widgetHeader() string {
 	total := len(m.projects)
 	filtered := total
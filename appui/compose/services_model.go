@@ -11,12 +11,14 @@ import (
 // serviceRow wraps a ComposeService as a TableRow.
 type serviceRow struct {
 	service docker.ComposeService
+	sync    docker.ServiceSync
 	columns []string
 }
 
 func newServiceRow(s docker.ComposeService, sync docker.ServiceSync) serviceRow {
 	return serviceRow{
 		service: s,
+		sync:    sync,
 		columns: []string{
 			"  " + s.Name,
 			fmt.Sprintf("%d", s.Containers),
@@ -33,6 +35,19 @@ func newServiceRow(s docker.ComposeService, sync docker.ServiceSync) serviceRow
 func (r serviceRow) Columns() []string { return r.columns }
 func (r serviceRow) ID() string        { return r.service.Name }
 
+// QueryFields returns the fields filter queries match the service by.
+func (r serviceRow) QueryFields() appui.QueryFields {
+	return appui.QueryFields{
+		Values: map[string]string{
+			"type":   "service",
+			"name":   r.service.Name,
+			"image":  r.service.Image,
+			"health": r.service.Health,
+			"sync":   string(r.sync),
+		},
+	}
+}
+
 // sectionRow is a visual group header separating resource types.
 type sectionRow struct {
 	label   string
@@ -66,6 +81,18 @@ func newNetworkRow(n docker.ComposeNetwork) networkRow {
 func (r networkRow) Columns() []string { return r.columns }
 func (r networkRow) ID() string        { return "net:" + r.network.Name }
 
+// QueryFields returns the fields filter queries match the network by.
+func (r networkRow) QueryFields() appui.QueryFields {
+	return appui.QueryFields{
+		Values: map[string]string{
+			"type":   "network",
+			"name":   r.network.Name,
+			"driver": r.network.Driver,
+			"scope":  r.network.Scope,
+		},
+	}
+}
+
 // volumeRow wraps a ComposeVolume as a TableRow.
 type volumeRow struct {
 	volume  docker.ComposeVolume
@@ -82,6 +109,21 @@ func newVolumeRow(v docker.ComposeVolume) volumeRow {
 func (r volumeRow) Columns() []string { return r.columns }
 func (r volumeRow) ID() string        { return "vol:" + r.volume.Name }
 
+// QueryFields returns the fields filter queries match the volume by.
+func (r volumeRow) QueryFields() appui.QueryFields {
+	return appui.QueryFields{
+		Values: map[string]string{
+			"type":   "volume",
+			"name":   r.volume.Name,
+			"driver": r.volume.Driver,
+		},
+	}
+}
+
+// resourceQueryFields lists the fields filter queries can match in a
+// project's resources. Section headers match free text only.
+var resourceQueryFields = []string{"type", "name", "image", "health", "sync", "driver", "scope"}
+
 // ServicesLoadedMsg carries loaded compose resources for a project.
 type ServicesLoadedMsg struct {
 	Services []docker.ComposeService
@@ -114,10 +156,12 @@ func NewServicesModel() ServicesModel {
 		{Title: "SYNC", Width: 7, Fixed: true},
 		{Title: "PORTS"},
 	}
-	return ServicesModel{
+	m := ServicesModel{
 		table:  appui.NewTableModel(columns),
 		filter: appui.NewFilterInputModel(),
 	}
+	m.table.SetQueryFields(resourceQueryFields...)
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -218,7 +262,7 @@ func (m ServicesModel) Update(msg tea.Msg) (ServicesModel, tea.Cmd) {
 	if m.filter.Active() {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -279,3 +323,8 @@ func (m ServicesModel) countFilteredServices() int {
 func (m *ServicesModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
+
+// SortBy sorts the list by the column with the given title.
+func (m *ServicesModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
@@ -38,9 +38,17 @@ type ContainerMenuModel struct {
 func NewContainerMenuModel(c *docker.Container) ContainerMenuModel {
 	cf := formatter.NewContainerFormatter(c, true)
 
-	items := make([]list.Item, len(docker.ContainerCommands))
-	for i, cmd := range docker.ContainerCommands {
-		items[i] = commandItem{cmd: cmd}
+	// Pause and unpause are only offered when they apply.
+	paused := docker.IsContainerPaused(c)
+	var items []list.Item
+	for _, cmd := range docker.ContainerCommands {
+		switch {
+		case cmd.Command == docker.PAUSE && (paused || !docker.IsContainerRunning(c)):
+			continue
+		case cmd.Command == docker.UNPAUSE && !paused:
+			continue
+		}
+		items = append(items, commandItem{cmd: cmd})
 	}
 
 	delegate := list.NewDefaultDelegate()
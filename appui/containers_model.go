@@ -1,6 +1,10 @@
 package appui
 
 import (
+	"slices"
+	"strings"
+	"time"
+
 	tea "charm.land/bubbletea/v2"
 	"github.com/moncho/dry/docker"
 	"github.com/moncho/dry/docker/formatter"
@@ -12,31 +16,114 @@ type containerRow struct {
 	columns   []string
 }
 
-func newContainerRow(c *docker.Container, compact bool) containerRow {
-	cf := formatter.NewContainerFormatter(c, true)
+func newContainerRow(c *docker.Container, values []string) containerRow {
 	indicator := ColorFg("\u25A0", DryTheme.FgSubtle) // ■ stopped
 	if docker.IsContainerRunning(c) {
 		indicator = ColorFg("\u25B6", DryTheme.Key) // ▶ running
 	}
-	columns := []string{
-		indicator, cf.ID(), cf.Image(), cf.Command(),
-		cf.Status(), cf.Ports(), cf.Names(),
-	}
-	if compact {
-		columns = []string{
-			indicator, cf.ID(), cf.Image(),
-			cf.Status(), cf.Names(),
-		}
+	if docker.IsContainerPaused(c) {
+		indicator = ColorFg("\u23F8", DryTheme.Warning) // ⏸ paused
 	}
 	return containerRow{
 		container: c,
-		columns:   columns,
+		columns:   append([]string{indicator}, values...),
 	}
 }
 
 func (r containerRow) Columns() []string { return r.columns }
 func (r containerRow) ID() string        { return r.container.ID }
 
+// QueryFields returns the fields filter queries match the container by.
+func (r containerRow) QueryFields() QueryFields {
+	c := r.container
+	f := formatter.NewContainerFormatter(c, false)
+	var name string
+	if len(c.Names) > 0 {
+		name = strings.TrimPrefix(c.Names[0], "/")
+	}
+	return QueryFields{
+		Values: map[string]string{
+			"id":      c.ID,
+			"name":    name,
+			"image":   c.Image,
+			"status":  string(c.State),
+			"health":  f.Health(),
+			"command": c.Command,
+			"host":    c.Host,
+		},
+		Labels:  c.Labels,
+		Created: time.Unix(c.Created, 0),
+	}
+}
+
+// containerQueryFields lists the fields filter queries can match in the
+// container list.
+var containerQueryFields = []string{
+	"id", "name", "image", "status", "health", "command", "host",
+	QueryLabel, QuerySince, QueryBefore,
+}
+
+// containerValue renders a container field with a truncating formatter.
+func containerValue(field func(*formatter.ContainerFormatter) string) func(*docker.Container) string {
+	return func(c *docker.Container) string {
+		return field(formatter.NewContainerFormatter(c, true))
+	}
+}
+
+// containerColumns lists the columns the container list can show. The state
+// indicator is not one of them, it always comes first.
+var containerColumns = ColumnCatalog[*docker.Container]{
+	{Key: "container", Column: Column{Title: "CONTAINER", Width: IDColumnWidth, Fixed: true},
+		Value: containerValue((*formatter.ContainerFormatter).ID)},
+	{Key: "host", Column: Column{Title: "HOST", Width: 14, Fixed: true},
+		Value: func(c *docker.Container) string { return c.Host }},
+	{Key: "image", Column: Column{Title: "IMAGE"},
+		Value: containerValue((*formatter.ContainerFormatter).Image)},
+	{Key: "command", Column: Column{Title: "COMMAND"},
+		Value: containerValue((*formatter.ContainerFormatter).Command)},
+	{Key: "created", Column: Column{Title: "CREATED", Width: 16, Fixed: true},
+		Value: containerValue((*formatter.ContainerFormatter).CreatedAt)},
+	{Key: "status", Column: Column{Title: "STATUS", Width: 18, Fixed: true},
+		Value: containerValue((*formatter.ContainerFormatter).Status)},
+	{Key: "health", Column: Column{Title: "HEALTH", Width: 10, Fixed: true},
+		Value: containerValue((*formatter.ContainerFormatter).Health)},
+	{Key: "ports", Column: Column{Title: "PORTS"},
+		Value: containerValue((*formatter.ContainerFormatter).Ports)},
+	{Key: "size", Column: Column{Title: "SIZE", Width: 12, Fixed: true},
+		Value: containerValue((*formatter.ContainerFormatter).Size)},
+	{Key: "mounts", Column: Column{Title: "MOUNTS"},
+		Value: containerValue((*formatter.ContainerFormatter).Mounts)},
+	{Key: "networks", Column: Column{Title: "NETWORKS"},
+		Value: containerValue((*formatter.ContainerFormatter).Networks)},
+	{Key: "labels", Column: Column{Title: "LABELS"},
+		Value: containerValue((*formatter.ContainerFormatter).Labels)},
+	{Key: "names", Column: Column{Title: "NAMES"},
+		Value: containerValue((*formatter.ContainerFormatter).Names)},
+}
+
+var (
+	defaultContainerColumns = DefaultColumnChoices("container", "image", "command", "status", "ports", "names")
+	compactContainerColumns = DefaultColumnChoices("container", "image", "status", "names")
+)
+
+// containerSortColumns maps the daemon-side sort modes to the column
+// showing the field sorted by.
+var containerSortColumns = map[docker.SortMode]string{
+	docker.SortByContainerID: "container",
+	docker.SortByImage:       "image",
+	docker.SortByStatus:      "status",
+	docker.SortByName:        "names",
+}
+
+// containerSortFields maps the daemon-side sort modes to the row format
+// field of the value sorted by.
+var containerSortFields = map[docker.SortMode]string{
+	docker.SortByContainerID: "ID",
+	docker.SortByImage:       "Image",
+	docker.SortByStatus:      "Status",
+	docker.SortByName:        "Names",
+}
+
 // ContainersModel is the container list view sub-model.
 type ContainersModel struct {
 	table    TableModel
@@ -45,36 +132,23 @@ type ContainersModel struct {
 	showAll  bool
 	sortMode docker.SortMode
 	compact  bool
-}
-
-func containerColumns(compact bool) []Column {
-	if compact {
-		return []Column{
-			{Title: "", Width: 2, Fixed: true},
-			{Title: "CONTAINER", Width: IDColumnWidth, Fixed: true},
-			{Title: "IMAGE"},
-			{Title: "STATUS", Width: 18, Fixed: true},
-			{Title: "NAMES"},
-		}
-	}
-	return []Column{
-		{Title: "", Width: 2, Fixed: true},
-		{Title: "CONTAINER", Width: IDColumnWidth, Fixed: true},
-		{Title: "IMAGE"},
-		{Title: "COMMAND"},
-		{Title: "STATUS", Width: 18, Fixed: true},
-		{Title: "PORTS"},
-		{Title: "NAMES"},
-	}
+	showHost bool
+	// unhealthyOnly hides the containers whose health check is not failing.
+	unhealthyOnly bool
+	columns       []ColumnChoice // nil for the default set
+	format        *formatter.RowFormat
 }
 
 // NewContainersModel creates a container list model.
 func NewContainersModel() ContainersModel {
-	return ContainersModel{
-		table:    NewTableModel(containerColumns(false)),
+	m := ContainersModel{
+		table:    NewTableModel(tableColumnsFor(containerColumns.Columns(defaultContainerColumns))),
 		filter:   NewFilterInputModel(),
 		sortMode: docker.SortByContainerID,
 	}
+	m.table.SetQueryFields(containerQueryFields...)
+	m.table.EnableMarks()
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -95,11 +169,43 @@ func (m ContainersModel) ShowAll() bool {
 	return m.showAll
 }
 
+// UnhealthyOnly returns true when only unhealthy containers are listed.
+func (m ContainersModel) UnhealthyOnly() bool {
+	return m.unhealthyOnly
+}
+
+// SetUnhealthyOnly lists only the containers whose health check is
+// failing, or every container again.
+func (m *ContainersModel) SetUnhealthyOnly(only bool) {
+	if m.unhealthyOnly == only {
+		return
+	}
+	m.unhealthyOnly = only
+	m.rebuildRows()
+}
+
 // SortMode returns the current sort mode.
 func (m ContainersModel) SortMode() docker.SortMode {
 	return m.sortMode
 }
 
+// SetSortMode sets the sort mode used on the next container load.
+func (m *ContainersModel) SetSortMode(mode docker.SortMode) {
+	m.sortMode = mode
+	m.applySortIndicator()
+}
+
+// FilterText returns the filter applied to the list.
+func (m ContainersModel) FilterText() string {
+	return m.table.FilterText()
+}
+
+// SetFilterText filters the list as if text had been typed in the filter
+// input.
+func (m *ContainersModel) SetFilterText(text string) error {
+	return m.table.SetFilter(text)
+}
+
 // SetCompact toggles the compact workspace column set.
 func (m *ContainersModel) SetCompact(compact bool) {
 	if m.compact == compact {
@@ -109,6 +215,74 @@ func (m *ContainersModel) SetCompact(compact bool) {
 	m.rebuildTable()
 }
 
+// SetShowHost toggles the HOST column, used when the list holds
+// containers from several daemons.
+func (m *ContainersModel) SetShowHost(show bool) {
+	if m.showHost == show {
+		return
+	}
+	m.showHost = show
+	m.rebuildTable()
+}
+
+// ColumnOptions returns the columns the container list can show.
+func (m ContainersModel) ColumnOptions() []ColumnOption {
+	return containerColumns.Options()
+}
+
+// ColumnChoices returns the columns chosen for the container list.
+func (m ContainersModel) ColumnChoices() []ColumnChoice {
+	if m.columns != nil {
+		return m.columns
+	}
+	if m.compact {
+		return compactContainerColumns
+	}
+	return defaultContainerColumns
+}
+
+// SetColumnChoices sets the columns of the container list; none brings
+// back the default set, which depends on the compact mode.
+func (m *ContainersModel) SetColumnChoices(choices []ColumnChoice) error {
+	if err := containerColumns.Check(choices); err != nil {
+		return err
+	}
+	m.columns = nil
+	if len(choices) > 0 {
+		m.columns = choices
+	}
+	m.format = nil
+	m.rebuildTable()
+	return nil
+}
+
+// SetRowFormat renders the container list with the given row format
+// instead of the chosen columns; nil goes back to the columns.
+func (m *ContainersModel) SetRowFormat(f *formatter.RowFormat) {
+	m.format = f
+	m.rebuildTable()
+}
+
+// shownColumns returns the chosen columns plus, when containers from
+// several hosts are listed, the HOST column if it was not chosen.
+func (m ContainersModel) shownColumns() []ColumnChoice {
+	choices := m.ColumnChoices()
+	if m.showHost && !slices.ContainsFunc(choices, func(c ColumnChoice) bool { return c.Key == "host" }) {
+		choices = append([]ColumnChoice{{Key: "host"}}, choices...)
+	}
+	return choices
+}
+
+// tableColumnsFor prepends the state indicator column to the given columns.
+func tableColumnsFor(columns []Column) []Column {
+	return append([]Column{{Title: "", Width: 2, Fixed: true}}, columns...)
+}
+
+// ShowHost returns true when the HOST column is shown.
+func (m ContainersModel) ShowHost() bool {
+	return m.showHost
+}
+
 // SetContainers replaces the container list with new data.
 func (m *ContainersModel) SetContainers(containers []*docker.Container) {
 	m.rows = containers
@@ -127,6 +301,27 @@ func (m ContainersModel) SelectedContainer() *docker.Container {
 	return nil
 }
 
+// MarkedCount returns the number of containers marked.
+func (m ContainersModel) MarkedCount() int {
+	return m.table.MarkedCount()
+}
+
+// ClearMarks unmarks every container.
+func (m *ContainersModel) ClearMarks() {
+	m.table.ClearMarks()
+}
+
+// MarkedContainers returns the marked containers, in list order.
+func (m ContainersModel) MarkedContainers() []*docker.Container {
+	var containers []*docker.Container
+	for _, row := range m.table.MarkedRows() {
+		if cr, ok := row.(containerRow); ok {
+			containers = append(containers, cr.container)
+		}
+	}
+	return containers
+}
+
 // Update handles container-list-specific key events.
 func (m ContainersModel) Update(msg tea.Msg) (ContainersModel, tea.Cmd) {
 	// When filter input is active, forward everything to it
@@ -134,7 +329,7 @@ func (m ContainersModel) Update(msg tea.Msg) (ContainersModel, tea.Cmd) {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
 		// Apply filter text to table in real-time
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -147,6 +342,9 @@ func (m ContainersModel) Update(msg tea.Msg) (ContainersModel, tea.Cmd) {
 		case "f2":
 			m.showAll = !m.showAll
 			return m, nil // parent handles reload
+		case "f3":
+			m.SetUnhealthyOnly(!m.unhealthyOnly)
+			return m, nil
 		case "f5":
 			return m, nil // parent handles reload
 		case "%":
@@ -172,12 +370,18 @@ func (m ContainersModel) View() string {
 }
 
 func (m ContainersModel) widgetHeader() string {
+	total, filter := m.table.TotalRowCount(), m.table.FilterText()
+	if m.unhealthyOnly {
+		total = len(m.rows)
+		filter = strings.TrimSpace("health=unhealthy " + filter)
+	}
 	return RenderWidgetHeader(WidgetHeaderOpts{
 		Icon:     "🐳",
 		Title:    "Containers",
-		Total:    m.table.TotalRowCount(),
+		Total:    total,
 		Filtered: m.table.RowCount(),
-		Filter:   m.table.FilterText(),
+		Filter:   filter,
+		Marked:   m.table.MarkedCount(),
 		Width:    m.table.Width(),
 		Accent:   DryTheme.Info,
 	})
@@ -198,47 +402,45 @@ func (m *ContainersModel) nextSort() {
 
 func (m *ContainersModel) applySortIndicator() {
 	col := -1
-	switch m.sortMode {
-	case docker.SortByContainerID:
-		col = 1
-	case docker.SortByImage:
-		col = 2
-	case docker.SortByStatus:
-		if m.compact {
-			col = 3
-		} else {
-			col = 4
+	if m.format != nil {
+		if field, ok := containerSortFields[m.sortMode]; ok {
+			if i := slices.Index(m.format.Headers(), formatter.ContainerFields[field]); i >= 0 {
+				col = i + 1
+			}
 		}
-	case docker.SortByName:
-		if m.compact {
-			col = 4
-		} else {
-			col = 6
+	} else if key, ok := containerSortColumns[m.sortMode]; ok {
+		for i, c := range m.shownColumns() {
+			if c.Key == key {
+				col = i + 1 // the state indicator comes first
+				break
+			}
 		}
 	}
 	m.table.SetSortField(col)
 }
 
 func (m *ContainersModel) rebuildRows() {
-	rows := make([]TableRow, len(m.rows))
-	for i, c := range m.rows {
-		rows[i] = newContainerRow(c, m.compact)
+	choices := m.shownColumns()
+	rows := make([]TableRow, 0, len(m.rows))
+	for _, c := range m.rows {
+		if m.unhealthyOnly && !docker.IsContainerUnhealthy(c) {
+			continue
+		}
+		if m.format != nil {
+			rows = append(rows, newContainerRow(c, m.format.Row(formatter.NewContainerFormatter(c, true))))
+		} else {
+			rows = append(rows, newContainerRow(c, containerColumns.Values(choices, c)))
+		}
 	}
 	m.table.SetRows(rows)
 	m.applySortIndicator()
 }
 
 func (m *ContainersModel) rebuildTable() {
-	width := m.table.width
-	height := m.table.height
-	filterText := m.filter.Value()
-
-	m.table = NewTableModel(containerColumns(m.compact))
-	if width > 0 && height > 0 {
-		m.table.SetSize(width, height)
+	columns := containerColumns.Columns(m.shownColumns())
+	if m.format != nil {
+		columns = formatColumns(m.format)
 	}
+	m.table.SetColumns(tableColumnsFor(columns))
 	m.rebuildRows()
-	if filterText != "" {
-		m.table.SetFilter(filterText)
-	}
 }
@@ -1,11 +1,14 @@
 package appui
 
 import (
+	"strings"
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/docker/formatter"
 )
 
 func makeTestContainers(n int) []*docker.Container {
@@ -142,3 +145,144 @@ func TestContainersModel_EmptySelected(t *testing.T) {
 		t.Fatal("expected nil selected container for empty model")
 	}
 }
+
+func TestContainersModel_SetShowHostAddsHostColumn(t *testing.T) {
+	m := NewContainersModel()
+	m.SetSize(120, 30)
+	containers := makeTestContainers(2)
+	containers[0].Host = "default"
+	containers[1].Host = "staging"
+	m.SetContainers(containers)
+
+	m.SetShowHost(true)
+
+	if len(m.table.columns) != 8 {
+		t.Fatalf("expected 8 columns with HOST, got %d", len(m.table.columns))
+	}
+	if m.table.columns[1].Title != "HOST" {
+		t.Fatalf("expected HOST at column 1, got %q", m.table.columns[1].Title)
+	}
+	row := m.table.SelectedRow()
+	if row == nil || row.Columns()[1] != "default" {
+		t.Fatalf("expected host of the selected container in column 1, got %v", row)
+	}
+
+	m.SetSortMode(docker.SortByName)
+	if m.table.SortField() != 7 {
+		t.Fatalf("expected NAMES sort field 7 with HOST, got %d", m.table.SortField())
+	}
+
+	m.SetShowHost(false)
+	if len(m.table.columns) != 7 {
+		t.Fatalf("expected 7 columns without HOST, got %d", len(m.table.columns))
+	}
+}
+
+func TestContainersModel_SetRowFormat(t *testing.T) {
+	m := NewContainersModel()
+	m.SetSize(120, 30)
+	m.SetContainers(makeTestContainers(2))
+	f, err := formatter.ParseRowFormat(`table {{.Status}}\t{{.Names}}`, formatter.ContainerFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m.SetRowFormat(f)
+	if len(m.table.columns) != 3 || m.table.columns[2].Title != "NAMES" {
+		t.Fatalf("expected the indicator and the format's columns, got %+v", m.table.columns)
+	}
+	m.SetSortMode(docker.SortByName)
+	if m.table.SortField() != 2 {
+		t.Fatalf("expected the NAMES cell as sort field, got %d", m.table.SortField())
+	}
+
+	if err := m.SetColumnChoices(DefaultColumnChoices("names")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.table.columns) != 2 {
+		t.Fatalf("expected chosen columns to replace the format, got %+v", m.table.columns)
+	}
+}
+
+func TestContainersModel_FilterQuery(t *testing.T) {
+	m := NewContainersModel()
+	m.SetSize(120, 30)
+	containers := makeTestContainers(3)
+	containers[0].State = "exited"
+	containers[0].Labels = map[string]string{"com.example.team": "payments"}
+	containers[1].State = "exited"
+	containers[2].State = "running"
+	m.SetContainers(containers)
+
+	if err := m.table.SetFilter("status=exited label=com.example.team=payments"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.table.RowCount() != 1 {
+		t.Fatalf("expected 1 matching container, got %d", m.table.RowCount())
+	}
+	if err := m.table.SetFilter("image~image-c"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if row, ok := m.table.SelectedRow().(containerRow); !ok || row.container.ID != containers[2].ID {
+		t.Fatalf("expected image-c container, got %+v", m.table.SelectedRow())
+	}
+}
+
+func TestContainersModel_UnhealthyOnly(t *testing.T) {
+	m := NewContainersModel()
+	m.SetSize(120, 30)
+	containers := makeTestContainers(3)
+	containers[0].Health = &container.HealthSummary{Status: container.Healthy}
+	containers[1].Detail.State = &container.State{Health: &container.Health{Status: container.Unhealthy}}
+	m.SetContainers(containers)
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyF3})
+	if !m.UnhealthyOnly() || m.table.RowCount() != 1 {
+		t.Fatalf("expected only the unhealthy container, got %d rows", m.table.RowCount())
+	}
+	if c := m.SelectedContainer(); c == nil || c.ID != containers[1].ID {
+		t.Fatalf("expected the unhealthy container to be selected, got %+v", c)
+	}
+	m.SetUnhealthyOnly(false)
+	if m.table.RowCount() != 3 {
+		t.Fatalf("expected every container back, got %d rows", m.table.RowCount())
+	}
+}
+
+func TestContainersModel_PausedIndicator(t *testing.T) {
+	m := NewContainersModel()
+	m.SetSize(120, 30)
+	containers := makeTestContainers(2)
+	containers[0].Status = "Up 2 minutes"
+	containers[1].Status = "Up 2 minutes (Paused)"
+	containers[1].State = container.StatePaused
+	m.SetContainers(containers)
+
+	view := ansi.Strip(m.View())
+	if strings.Count(view, "⏸") != 1 || strings.Count(view, "▶") != 1 {
+		t.Fatalf("expected one running and one paused container:\n%s", view)
+	}
+}
+
+func TestContainerMenuModel_PauseEntries(t *testing.T) {
+	commands := func(c *docker.Container) []string {
+		var titles []string
+		for _, item := range NewContainerMenuModel(c).list.Items() {
+			titles = append(titles, item.(commandItem).Title())
+		}
+		return titles
+	}
+	c := makeTestContainers(1)[0]
+	c.Status = "Up 2 minutes"
+	if got := strings.Join(commands(c), ","); !strings.Contains(got, "Pause") || strings.Contains(got, "Unpause") {
+		t.Fatalf("expected only Pause for a running container, got %s", got)
+	}
+	c.Status = "Up 2 minutes (Paused)"
+	if got := strings.Join(commands(c), ","); !strings.Contains(got, "Unpause") || strings.Contains(got, ",Pause") {
+		t.Fatalf("expected only Unpause for a paused container, got %s", got)
+	}
+	c.Status = "Exited (0) 1 hour ago"
+	if got := strings.Join(commands(c), ","); strings.Contains(got, "ause") || !strings.Contains(got, "Rename") {
+		t.Fatalf("expected neither for a stopped container, got %s", got)
+	}
+}
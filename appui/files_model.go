@@ -0,0 +1,401 @@
+package appui
+
+import (
+	"fmt"
+	"image/color"
+	"path"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moncho/dry/docker"
+)
+
+// FileViewMsg asks to show a file of a container.
+type FileViewMsg struct {
+	ContainerID string
+	Path        string
+}
+
+// FileCopyOutMsg asks to copy a file or directory of a container to the
+// host.
+type FileCopyOutMsg struct {
+	ContainerID string
+	Path        string
+}
+
+// FileCopyInMsg asks to copy a host file or directory into a directory of
+// a container.
+type FileCopyInMsg struct {
+	ContainerID string
+	Dir         string
+}
+
+// FilesReloadMsg asks to read the filesystem of a container again.
+type FilesReloadMsg struct {
+	ContainerID string
+}
+
+// fileRow is a line of the browser: a file of the tree, or a change.
+type fileRow struct {
+	path   string
+	entry  docker.FileEntry
+	known  bool // whether the file is in the tree
+	depth  int
+	change *container.ChangeType
+}
+
+// FilesModel browses the filesystem of a container as a tree, or the
+// changes made to it since it was created.
+type FilesModel struct {
+	containerID string
+	title       string
+	tree        *docker.FileTree
+	changes     []container.FilesystemChange
+	changed     map[string]container.ChangeType
+	expanded    map[string]bool
+	showChanges bool
+	rows        []fileRow
+	cursor      int
+	offset      int
+	status      string
+	message     string // shown until the next key press
+	width       int
+	height      int
+}
+
+// NewFilesModel creates a browser of the filesystem of the given container,
+// waiting for SetTree.
+func NewFilesModel(containerID, title string) FilesModel {
+	return FilesModel{
+		containerID: containerID,
+		title:       title,
+		expanded:    make(map[string]bool),
+		changed:     make(map[string]container.ChangeType),
+		status:      "Reading the container filesystem...",
+	}
+}
+
+// ContainerID returns the container browsed.
+func (m FilesModel) ContainerID() string {
+	return m.containerID
+}
+
+// SetSize sets the size of the browser.
+func (m *FilesModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.scroll()
+}
+
+// SetTree sets the filesystem browsed, or why it could not be read.
+func (m *FilesModel) SetTree(tree *docker.FileTree, err error) {
+	if err != nil {
+		m.status = fmt.Sprintf("Error reading the filesystem: %s", err)
+		return
+	}
+	m.tree = tree
+	m.status = ""
+	m.rebuild()
+}
+
+// SetChanges sets the changes made to the filesystem, or why they could
+// not be read.
+func (m *FilesModel) SetChanges(changes []container.FilesystemChange, err error) {
+	if err != nil {
+		m.status = fmt.Sprintf("Error reading the changes: %s", err)
+		return
+	}
+	m.changes = changes
+	m.changed = make(map[string]container.ChangeType, len(changes))
+	for _, c := range changes {
+		m.changed[c.Path] = c.Kind
+	}
+	m.rebuild()
+}
+
+// SetMessage shows a message in the status line until the next key press.
+func (m *FilesModel) SetMessage(message string) {
+	m.message = message
+}
+
+// ShowingChanges returns true if the changes are shown instead of the tree.
+func (m FilesModel) ShowingChanges() bool {
+	return m.showChanges
+}
+
+// SelectedPath returns the path of the file under the cursor, if any.
+func (m FilesModel) SelectedPath() string {
+	if m.cursor < len(m.rows) {
+		return m.rows[m.cursor].path
+	}
+	return ""
+}
+
+// rebuild lists the rows shown, keeping the cursor on the same file.
+func (m *FilesModel) rebuild() {
+	selected := m.SelectedPath()
+	m.rows = nil
+	if m.showChanges {
+		for _, c := range m.changes {
+			kind := c.Kind
+			row := fileRow{path: c.Path, change: &kind}
+			if m.tree != nil {
+				row.entry, row.known = m.tree.Entry(c.Path)
+			}
+			m.rows = append(m.rows, row)
+		}
+	} else if m.tree != nil {
+		m.addDir("/", 0)
+	}
+	m.cursor = 0
+	for i, r := range m.rows {
+		if r.path == selected {
+			m.cursor = i
+			break
+		}
+	}
+	m.scroll()
+}
+
+func (m *FilesModel) addDir(dir string, depth int) {
+	for _, e := range m.tree.Dir(dir) {
+		row := fileRow{path: e.Path, entry: e, known: true, depth: depth}
+		if kind, ok := m.changed[e.Path]; ok {
+			row.change = &kind
+		}
+		m.rows = append(m.rows, row)
+		if e.IsDir() && m.expanded[e.Path] {
+			m.addDir(e.Path, depth+1)
+		}
+	}
+}
+
+// visibleRows is the number of rows that fit between the title and the
+// status line.
+func (m FilesModel) visibleRows() int {
+	return max(m.height-2, 1)
+}
+
+func (m *FilesModel) scroll() {
+	m.cursor = min(max(m.cursor, 0), max(len(m.rows)-1, 0))
+	visible := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+	m.offset = min(m.offset, max(len(m.rows)-visible, 0))
+}
+
+// Update handles key events for the browser.
+func (m FilesModel) Update(msg tea.Msg) (FilesModel, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	m.message = ""
+	switch key.String() {
+	case "esc", "q":
+		return m, func() tea.Msg { return CloseOverlayMsg{} }
+	case "up", "k":
+		m.cursor--
+	case "down", "j":
+		m.cursor++
+	case "pgup":
+		m.cursor -= m.visibleRows()
+	case "pgdown":
+		m.cursor += m.visibleRows()
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.rows) - 1
+	case "c":
+		m.showChanges = !m.showChanges
+		m.rebuild()
+	case "r":
+		m.status = "Reading the container filesystem..."
+		id := m.containerID
+		return m, func() tea.Msg { return FilesReloadMsg{ContainerID: id} }
+	case "enter", "right", "l":
+		return m.open(key.String() == "enter")
+	case "left", "h":
+		m.close()
+	case "o":
+		if row, ok := m.selected(); ok && !row.deleted() {
+			id := m.containerID
+			return m, func() tea.Msg { return FileCopyOutMsg{ContainerID: id, Path: row.path} }
+		}
+	case "i":
+		if row, ok := m.selected(); ok || m.tree != nil {
+			dir := "/"
+			if ok {
+				dir = path.Dir(row.path)
+				if row.entry.IsDir() {
+					dir = row.path
+				}
+			}
+			id := m.containerID
+			return m, func() tea.Msg { return FileCopyInMsg{ContainerID: id, Dir: dir} }
+		}
+	}
+	m.scroll()
+	return m, nil
+}
+
+func (m FilesModel) selected() (fileRow, bool) {
+	if m.cursor < len(m.rows) {
+		return m.rows[m.cursor], true
+	}
+	return fileRow{}, false
+}
+
+// open expands the directory under the cursor, collapsing it if toggle is
+// set and it is expanded, or asks to show the file under the cursor.
+func (m FilesModel) open(toggle bool) (FilesModel, tea.Cmd) {
+	row, ok := m.selected()
+	if !ok || row.deleted() || !row.known {
+		return m, nil
+	}
+	if row.entry.IsDir() {
+		if m.showChanges {
+			// Changed directories are shown in the tree.
+			m.showChanges = false
+			for dir := row.path; dir != "/"; dir = path.Dir(dir) {
+				m.expanded[dir] = true
+			}
+			m.rebuild()
+			return m, nil
+		}
+		m.expanded[row.path] = !(toggle && m.expanded[row.path])
+		m.rebuild()
+		return m, nil
+	}
+	if !row.entry.Mode.IsRegular() {
+		return m, nil
+	}
+	id := m.containerID
+	return m, func() tea.Msg { return FileViewMsg{ContainerID: id, Path: row.path} }
+}
+
+// close collapses the directory under the cursor, or moves the cursor to
+// the directory of the file under it.
+func (m *FilesModel) close() {
+	row, ok := m.selected()
+	if !ok || m.showChanges {
+		return
+	}
+	if row.entry.IsDir() && m.expanded[row.path] {
+		delete(m.expanded, row.path)
+		m.rebuild()
+		return
+	}
+	parent := path.Dir(row.path)
+	for i := m.cursor - 1; i >= 0; i-- {
+		if m.rows[i].path == parent {
+			m.cursor = i
+			break
+		}
+	}
+}
+
+func (r fileRow) deleted() bool {
+	return r.change != nil && *r.change == container.ChangeDelete
+}
+
+// changeMark returns the mark and color of a change.
+func changeMark(kind container.ChangeType) (string, color.Color) {
+	switch kind {
+	case container.ChangeAdd:
+		return "A", DryTheme.Success
+	case container.ChangeDelete:
+		return "D", DryTheme.Error
+	default:
+		return "C", DryTheme.Warning
+	}
+}
+
+func (m FilesModel) renderRow(r fileRow, selected bool) string {
+	mode, size := strings.Repeat(" ", 10), ""
+	if r.known {
+		mode = r.entry.Mode.String()
+		if !r.entry.IsDir() {
+			size = units.HumanSize(float64(r.entry.Size))
+		}
+	}
+	name := path.Base(r.path)
+	if m.showChanges {
+		name = r.path
+	}
+	marker := "  "
+	if r.known && r.entry.IsDir() {
+		name += "/"
+		if !m.showChanges {
+			marker = "▸ "
+			if m.expanded[r.path] {
+				marker = "▾ "
+			}
+		}
+	}
+	if r.entry.LinkTarget != "" {
+		name += " -> " + r.entry.LinkTarget
+	}
+	change := " "
+	if r.change != nil {
+		mark, c := changeMark(*r.change)
+		change = ColorFg(mark, c)
+		name = ColorFg(name, c)
+	}
+	line := fmt.Sprintf(" %s %s %9s  %s%s%s", change, ColorFg(mode, DryTheme.FgMuted), size,
+		strings.Repeat("  ", r.depth), ColorFg(marker, DryTheme.Primary), name)
+	line = ansi.Truncate(line, m.width, "…")
+	style := lipgloss.NewStyle().Width(m.width)
+	if selected {
+		style = style.Background(DryTheme.CursorLineBg)
+	}
+	return style.Render(line)
+}
+
+// View renders the browser full screen.
+func (m FilesModel) View() string {
+	title := m.title
+	if m.showChanges {
+		title += fmt.Sprintf(" — changes (%d)", len(m.changes))
+	}
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(DryTheme.Fg).
+		Background(DryTheme.Primary).
+		Width(m.width)
+	lines := []string{titleStyle.Render(title)}
+
+	visible := m.visibleRows()
+	for i := m.offset; i < len(m.rows) && i < m.offset+visible; i++ {
+		lines = append(lines, m.renderRow(m.rows[i], i == m.cursor))
+	}
+	if len(m.rows) == 0 && m.status == "" {
+		empty := "Empty filesystem"
+		if m.showChanges {
+			empty = "No changes since the container was created"
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(DryTheme.FgMuted).Render(" "+empty))
+	}
+	for len(lines) < visible+1 {
+		lines = append(lines, "")
+	}
+
+	status := m.status
+	if m.message != "" {
+		status = m.message
+	}
+	if status == "" {
+		status = "esc back · enter open · ←/→ collapse/expand · c changes/tree · o copy to host · i copy from host · r reload"
+	}
+	statusStyle := lipgloss.NewStyle().Foreground(DryTheme.FgSubtle).Width(m.width)
+	lines = append(lines, statusStyle.Render(ansi.Truncate(status, m.width, "…")))
+	return strings.Join(lines, "\n")
+}
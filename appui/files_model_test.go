@@ -0,0 +1,102 @@
+package appui
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moncho/dry/docker"
+)
+
+func newTestFilesModel() FilesModel {
+	m := NewFilesModel("c1", "Files: web")
+	m.SetSize(100, 20)
+	m.SetTree(docker.NewFileTree(
+		docker.FileEntry{Path: "/etc/hostname", Size: 13, Mode: 0o644},
+		docker.FileEntry{Path: "/etc/app.conf", Size: 42, Mode: 0o644},
+		docker.FileEntry{Path: "/bin/sh", Mode: os.ModeSymlink | 0o777, LinkTarget: "busybox"},
+	), nil)
+	m.SetChanges([]container.FilesystemChange{
+		{Kind: container.ChangeAdd, Path: "/etc/app.conf"},
+		{Kind: container.ChangeDelete, Path: "/tmp/cache"},
+	}, nil)
+	return m
+}
+
+func pressFileKeys(m FilesModel, keys ...string) (FilesModel, tea.Msg) {
+	var msg tea.Msg
+	for _, k := range keys {
+		var cmd tea.Cmd
+		key := tea.KeyPressMsg{Code: []rune(k)[0], Text: k}
+		switch k {
+		case "enter":
+			key = tea.KeyPressMsg{Code: tea.KeyEnter}
+		case "left":
+			key = tea.KeyPressMsg{Code: tea.KeyLeft}
+		case "esc":
+			key = tea.KeyPressMsg{Code: tea.KeyEscape}
+		}
+		m, cmd = m.Update(key)
+		msg = nil
+		if cmd != nil {
+			msg = cmd()
+		}
+	}
+	return m, msg
+}
+
+func TestFilesModel_BrowsesTheTree(t *testing.T) {
+	m := newTestFilesModel()
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "▸ bin/") || !strings.Contains(view, "▸ etc/") || strings.Contains(view, "hostname") {
+		t.Fatalf("expected the root directories collapsed:\n%s", view)
+	}
+
+	// etc is the second directory; its files are listed once expanded.
+	m, _ = pressFileKeys(m, "j", "enter")
+	view = ansi.Strip(m.View())
+	if !strings.Contains(view, "▾ etc/") || !strings.Contains(view, "hostname") {
+		t.Fatalf("expected etc expanded:\n%s", view)
+	}
+	m, msg := pressFileKeys(m, "j", "enter")
+	if v, ok := msg.(FileViewMsg); !ok || v.Path != "/etc/app.conf" || v.ContainerID != "c1" {
+		t.Fatalf("expected to view app.conf, got %#v", msg)
+	}
+	if _, msg = pressFileKeys(m, "i"); msg.(FileCopyInMsg).Dir != "/etc" {
+		t.Fatalf("expected to copy into the directory of the file, got %#v", msg)
+	}
+	if _, msg = pressFileKeys(m, "o"); msg.(FileCopyOutMsg).Path != "/etc/app.conf" {
+		t.Fatalf("expected to copy the file out, got %#v", msg)
+	}
+
+	// left goes up to the directory, then collapses it.
+	m, _ = pressFileKeys(m, "left", "left")
+	if m.SelectedPath() != "/etc" || strings.Contains(ansi.Strip(m.View()), "hostname") {
+		t.Fatalf("expected etc collapsed, at %s", m.SelectedPath())
+	}
+}
+
+func TestFilesModel_ShowsChanges(t *testing.T) {
+	m := newTestFilesModel()
+	m, _ = pressFileKeys(m, "c")
+	if !m.ShowingChanges() {
+		t.Fatal("expected c to show the changes")
+	}
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "changes (2)") || !strings.Contains(view, "A -rw-r--r--") ||
+		!strings.Contains(view, "D") || !strings.Contains(view, "/tmp/cache") {
+		t.Fatalf("unexpected changes view:\n%s", view)
+	}
+	if _, msg := pressFileKeys(m, "enter"); msg.(FileViewMsg).Path != "/etc/app.conf" {
+		t.Fatalf("expected to view the added file, got %#v", msg)
+	}
+	if _, msg := pressFileKeys(m, "j", "o"); msg != nil {
+		t.Fatalf("expected deleted files not to be copied, got %#v", msg)
+	}
+	if _, msg := pressFileKeys(m, "esc"); msg != (CloseOverlayMsg{}) {
+		t.Fatalf("expected esc to close the browser, got %#v", msg)
+	}
+}
@@ -11,13 +11,14 @@ type FilterInputModel struct {
 	input  textinput.Model
 	active bool
 	width  int
+	err    error
 }
 
 // NewFilterInputModel creates a new filter input.
 func NewFilterInputModel() FilterInputModel {
 	ti := textinput.New()
 	ti.Prompt = "Filter: "
-	ti.Placeholder = "type to filter..."
+	ti.Placeholder = "type to filter, or field=value, field~value, label=key=value, since=2h..."
 	ti.CharLimit = 256
 	return FilterInputModel{input: ti}
 }
@@ -37,9 +38,15 @@ func (m *FilterInputModel) SetWidth(w int) {
 	m.width = w
 }
 
+// SetError shows why the filter text could not be applied; nil clears it.
+func (m *FilterInputModel) SetError(err error) {
+	m.err = err
+}
+
 // Activate shows and focuses the filter input.
 func (m *FilterInputModel) Activate() tea.Cmd {
 	m.active = true
+	m.err = nil
 	m.input.SetValue("")
 	return m.input.Focus()
 }
@@ -54,6 +61,7 @@ func (m *FilterInputModel) Deactivate() string {
 // Clear resets and hides the filter input.
 func (m *FilterInputModel) Clear() {
 	m.active = false
+	m.err = nil
 	m.input.SetValue("")
 	m.input.Blur()
 }
@@ -86,5 +94,9 @@ func (m FilterInputModel) View() string {
 		return ""
 	}
 	style := lipgloss.NewStyle().Width(m.width)
+	if m.err != nil {
+		errStyle := lipgloss.NewStyle().Foreground(DryTheme.Error)
+		return style.Render(m.input.View() + "  " + errStyle.Render(m.err.Error()))
+	}
 	return style.Render(m.input.View())
 }
@@ -0,0 +1,256 @@
+package appui
+
+import (
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// FormField is a field of a form: a line of text or, when Toggle is set, a
+// choice between yes and no.
+type FormField struct {
+	Key         string
+	Label       string
+	Placeholder string
+	Value       string
+	Toggle      bool
+	On          bool
+}
+
+// FormValues are the values of the fields of a form, by key.
+type FormValues struct {
+	text    map[string]string
+	toggles map[string]bool
+}
+
+// String returns the text of a field, trimmed.
+func (v FormValues) String(key string) string {
+	return strings.TrimSpace(v.text[key])
+}
+
+// List returns the comma-separated entries of a field, trimmed, skipping
+// empty ones.
+func (v FormValues) List(key string) []string {
+	var list []string
+	for _, entry := range strings.Split(v.text[key], ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// Bool returns whether a toggle is on.
+func (v FormValues) Bool(key string) bool {
+	return v.toggles[key]
+}
+
+// FormSubmittedMsg is sent when a form is submitted. The form stays open
+// until closed, so that errors can be shown in it.
+type FormSubmittedMsg struct {
+	Tag    string // identifies the form
+	ID     string // the resource the form is about
+	Values FormValues
+}
+
+// formField is a field of the form with its input.
+type formField struct {
+	FormField
+	input textinput.Model
+}
+
+// FormModel is a form of text fields and toggles, rendered as a centered
+// floating window.
+type FormModel struct {
+	title  string
+	tag    string
+	id     string
+	fields []formField
+	cursor int
+	err    string
+	busy   string
+	note   string
+	width  int
+	height int
+}
+
+// NewFormModel creates a form with the given fields, the first one focused.
+func NewFormModel(title, tag, id string, fields []FormField) (FormModel, tea.Cmd) {
+	m := FormModel{title: title, tag: tag, id: id}
+	for _, f := range fields {
+		ff := formField{FormField: f}
+		if !f.Toggle {
+			ff.input = textinput.New()
+			ff.input.Placeholder = f.Placeholder
+			ff.input.CharLimit = 1024
+			ff.input.SetValue(f.Value)
+		}
+		m.fields = append(m.fields, ff)
+	}
+	return m, m.focus(0)
+}
+
+// SetSize sets the overall screen size for centering the form.
+func (m *FormModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// SetNote shows a note on how to fill in the form under the fields.
+func (m *FormModel) SetNote(note string) {
+	m.note = note
+}
+
+// SetError shows an error under the fields, ending the busy state.
+func (m *FormModel) SetError(err string) {
+	m.err = err
+	m.busy = ""
+}
+
+// SetBusy shows what the form is waiting for, keys other than esc being
+// ignored until SetError is called.
+func (m *FormModel) SetBusy(text string) {
+	m.busy = text
+	m.err = ""
+}
+
+// Values returns the values of the fields.
+func (m FormModel) Values() FormValues {
+	v := FormValues{text: make(map[string]string), toggles: make(map[string]bool)}
+	for _, f := range m.fields {
+		if f.Toggle {
+			v.toggles[f.Key] = f.On
+		} else {
+			v.text[f.Key] = f.input.Value()
+		}
+	}
+	return v
+}
+
+func (m *FormModel) focus(i int) tea.Cmd {
+	if len(m.fields) == 0 {
+		return nil
+	}
+	if !m.fields[m.cursor].Toggle {
+		m.fields[m.cursor].input.Blur()
+	}
+	m.cursor = (i + len(m.fields)) % len(m.fields)
+	if m.fields[m.cursor].Toggle {
+		return nil
+	}
+	return m.fields[m.cursor].input.Focus()
+}
+
+// Update handles key events for the form.
+func (m FormModel) Update(msg tea.Msg) (FormModel, tea.Cmd) {
+	key, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		if len(m.fields) > 0 && !m.fields[m.cursor].Toggle {
+			var cmd tea.Cmd
+			m.fields[m.cursor].input, cmd = m.fields[m.cursor].input.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+	if key.String() == "esc" {
+		return m, func() tea.Msg { return CloseOverlayMsg{} }
+	}
+	if m.busy != "" {
+		return m, nil
+	}
+	switch key.String() {
+	case "enter", "ctrl+s":
+		m.err = ""
+		values := m.Values()
+		return m, func() tea.Msg {
+			return FormSubmittedMsg{Tag: m.tag, ID: m.id, Values: values}
+		}
+	case "tab", "down":
+		return m, m.focus(m.cursor + 1)
+	case "shift+tab", "up":
+		return m, m.focus(m.cursor - 1)
+	}
+	if len(m.fields) == 0 {
+		return m, nil
+	}
+	f := &m.fields[m.cursor]
+	if f.Toggle {
+		switch key.String() {
+		case "space", " ", "left", "right", "y", "n":
+			f.On = key.String() == "y" || (key.String() != "n" && !f.On)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	return m, cmd
+}
+
+// View renders the form as a centered floating dialog.
+func (m FormModel) View() string {
+	dialogWidth := min(76, m.width-4)
+	if dialogWidth < 40 {
+		dialogWidth = 40
+	}
+	labelWidth := 0
+	for _, f := range m.fields {
+		labelWidth = max(labelWidth, len(f.Label))
+	}
+	inputWidth := max(dialogWidth-labelWidth-8, 10)
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(DryTheme.Fg)
+	labelStyle := lipgloss.NewStyle().Foreground(DryTheme.FgMuted).Width(labelWidth + 2)
+	focusedLabel := labelStyle.Foreground(DryTheme.Primary).Bold(true)
+	hintStyle := lipgloss.NewStyle().Foreground(DryTheme.FgSubtle)
+
+	// Fields scroll with the cursor when they do not all fit.
+	visible := len(m.fields)
+	if m.height > 0 {
+		visible = max(min(visible, m.height-12), 3)
+	}
+	first := min(max(m.cursor-visible/2, 0), max(len(m.fields)-visible, 0))
+
+	lines := []string{titleStyle.Render(m.title), ""}
+	for i := first; i < len(m.fields) && i < first+visible; i++ {
+		f := m.fields[i]
+		label := labelStyle.Render(f.Label)
+		if i == m.cursor {
+			label = focusedLabel.Render(f.Label)
+		}
+		var value string
+		if f.Toggle {
+			value = "[ ] no"
+			if f.On {
+				value = "[x] yes"
+			}
+			if i == m.cursor {
+				value = lipgloss.NewStyle().Foreground(DryTheme.Primary).Render(value)
+			}
+		} else {
+			f.input.SetWidth(inputWidth)
+			value = f.input.View()
+		}
+		lines = append(lines, label+value)
+	}
+	lines = append(lines, "")
+	if m.note != "" {
+		lines = append(lines, hintStyle.Width(dialogWidth-4).Render(m.note))
+	}
+	switch {
+	case m.busy != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(DryTheme.Info).Render(m.busy))
+	case m.err != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(DryTheme.Error).Width(dialogWidth-4).Render(m.err))
+	}
+	lines = append(lines, hintStyle.Render("tab/↑↓ move · space toggle · enter confirm · esc cancel"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(DryTheme.Primary).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, dialog)
+}
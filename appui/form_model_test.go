@@ -0,0 +1,71 @@
+package appui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func typeText(m FormModel, text string) FormModel {
+	for _, r := range text {
+		m, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	return m
+}
+
+func TestFormModel_Submit(t *testing.T) {
+	m, _ := NewFormModel("Run", "run", "img", []FormField{
+		{Key: "name", Label: "Name"},
+		{Key: "ports", Label: "Ports", Value: "80"},
+		{Key: "detach", Label: "Detach", Toggle: true, On: true},
+		{Key: "tty", Label: "TTY", Toggle: true},
+	})
+	m.SetSize(100, 40)
+	m = typeText(m, "web")
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m = typeText(m, ", 443:8443 ,")
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	if !strings.Contains(m.View(), "Run") {
+		t.Fatal("expected the form title in its view")
+	}
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected enter to submit the form")
+	}
+	msg, ok := cmd().(FormSubmittedMsg)
+	if !ok || msg.Tag != "run" || msg.ID != "img" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	v := msg.Values
+	if v.String("name") != "web" || !reflect.DeepEqual(v.List("ports"), []string{"80", "443:8443"}) {
+		t.Fatalf("unexpected values %q %q", v.String("name"), v.List("ports"))
+	}
+	if v.Bool("detach") || !v.Bool("tty") {
+		t.Fatalf("unexpected toggles %v %v", v.Bool("detach"), v.Bool("tty"))
+	}
+}
+
+func TestFormModel_BusyIgnoresKeysButEsc(t *testing.T) {
+	m, _ := NewFormModel("Run", "run", "img", []FormField{{Key: "name", Label: "Name"}})
+	m.SetBusy("Starting...")
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter}); cmd != nil {
+		t.Fatal("expected a busy form not to be submitted again")
+	}
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if cmd == nil {
+		t.Fatal("expected esc to close the form")
+	}
+	if _, ok := cmd().(CloseOverlayMsg); !ok {
+		t.Fatal("expected esc to close the form")
+	}
+	m.SetError("boom")
+	if !strings.Contains(m.View(), "boom") {
+		t.Fatal("expected the error in the form")
+	}
+}
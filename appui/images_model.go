@@ -1,6 +1,10 @@
 package appui
 
 import (
+	"slices"
+	"strconv"
+	"time"
+
 	tea "charm.land/bubbletea/v2"
 	"github.com/moby/moby/api/types/image"
 	"github.com/moncho/dry/docker/formatter"
@@ -12,19 +16,61 @@ type imageRow struct {
 	columns []string
 }
 
-func newImageRow(img image.Summary) imageRow {
-	f := formatter.NewImageFormatter(img, true)
+func newImageRow(img image.Summary, values []string) imageRow {
 	return imageRow{
-		image: img,
-		columns: []string{
-			f.Repository(), f.Tag(), f.ID(), f.CreatedSince(), f.Size(),
-		},
+		image:   img,
+		columns: values,
 	}
 }
 
 func (r imageRow) Columns() []string { return r.columns }
 func (r imageRow) ID() string        { return r.image.ID }
 
+// QueryFields returns the fields filter queries match the image by.
+func (r imageRow) QueryFields() QueryFields {
+	f := formatter.NewImageFormatter(r.image, false)
+	dangling := len(r.image.RepoTags) == 0 || slices.Contains(r.image.RepoTags, "<none>:<none>")
+	return QueryFields{
+		Values: map[string]string{
+			"id":         r.image.ID,
+			"repository": f.Repository(),
+			"tag":        f.Tag(),
+			"dangling":   strconv.FormatBool(dangling),
+		},
+		Labels:  r.image.Labels,
+		Created: time.Unix(r.image.Created, 0),
+	}
+}
+
+// imageQueryFields lists the fields filter queries can match in the image
+// list.
+var imageQueryFields = []string{"id", "repository", "tag", "dangling", QueryLabel, QuerySince, QueryBefore}
+
+// imageValue renders an image field with a truncating formatter.
+func imageValue(field func(*formatter.ImageFormatter) string) func(image.Summary) string {
+	return func(img image.Summary) string {
+		return field(formatter.NewImageFormatter(img, true))
+	}
+}
+
+// imageColumns lists the columns the image list can show.
+var imageColumns = ColumnCatalog[image.Summary]{
+	{Key: "repository", Column: Column{Title: "REPOSITORY"},
+		Value: imageValue((*formatter.ImageFormatter).Repository)},
+	{Key: "tag", Column: Column{Title: "TAG", Width: 20, Fixed: true},
+		Value: imageValue((*formatter.ImageFormatter).Tag)},
+	{Key: "digest", Column: Column{Title: "DIGEST"},
+		Value: imageValue((*formatter.ImageFormatter).Digest)},
+	{Key: "id", Column: Column{Title: "ID", Width: IDColumnWidth, Fixed: true},
+		Value: imageValue((*formatter.ImageFormatter).ID)},
+	{Key: "created", Column: Column{Title: "CREATED", Width: 16, Fixed: true},
+		Value: imageValue((*formatter.ImageFormatter).CreatedSince)},
+	{Key: "size", Column: Column{Title: "SIZE", Width: 10, Fixed: true},
+		Value: imageValue((*formatter.ImageFormatter).Size)},
+}
+
+var defaultImageColumns = DefaultColumnChoices("repository", "tag", "id", "created", "size")
+
 // ImagesLoadedMsg carries the loaded images.
 type ImagesLoadedMsg struct {
 	Images []image.Summary
@@ -32,23 +78,23 @@ type ImagesLoadedMsg struct {
 
 // ImagesModel is the images list view sub-model.
 type ImagesModel struct {
-	table  TableModel
-	filter FilterInputModel
+	table   TableModel
+	filter  FilterInputModel
+	images  []image.Summary
+	columns []ColumnChoice
+	format  *formatter.RowFormat
 }
 
 // NewImagesModel creates an images list model.
 func NewImagesModel() ImagesModel {
-	columns := []Column{
-		{Title: "REPOSITORY"},
-		{Title: "TAG", Width: 20, Fixed: true},
-		{Title: "ID", Width: IDColumnWidth, Fixed: true},
-		{Title: "CREATED", Width: 16, Fixed: true},
-		{Title: "SIZE", Width: 10, Fixed: true},
-	}
-	return ImagesModel{
-		table:  NewTableModel(columns),
-		filter: NewFilterInputModel(),
+	m := ImagesModel{
+		table:   NewTableModel(imageColumns.Columns(defaultImageColumns)),
+		filter:  NewFilterInputModel(),
+		columns: defaultImageColumns,
 	}
+	m.table.SetQueryFields(imageQueryFields...)
+	m.table.EnableMarks()
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -66,13 +112,62 @@ func (m *ImagesModel) SetSize(w, h int) {
 
 // SetImages replaces the image list.
 func (m *ImagesModel) SetImages(images []image.Summary) {
+	m.images = images
 	rows := make([]TableRow, len(images))
 	for i, img := range images {
-		rows[i] = newImageRow(img)
+		rows[i] = newImageRow(img, m.rowValues(img))
 	}
 	m.table.SetRows(rows)
 }
 
+// ColumnOptions returns the columns the image list can show.
+func (m ImagesModel) ColumnOptions() []ColumnOption {
+	return imageColumns.Options()
+}
+
+// ColumnChoices returns the columns chosen for the image list.
+func (m ImagesModel) ColumnChoices() []ColumnChoice {
+	return m.columns
+}
+
+// SetColumnChoices sets the columns of the image list; none brings back
+// the default set.
+func (m *ImagesModel) SetColumnChoices(choices []ColumnChoice) error {
+	if err := imageColumns.Check(choices); err != nil {
+		return err
+	}
+	if len(choices) == 0 {
+		choices = defaultImageColumns
+	}
+	m.columns = choices
+	m.format = nil
+	m.table.SetColumns(m.tableColumns())
+	m.SetImages(m.images)
+	return nil
+}
+
+// SetRowFormat renders the image list with the given row format instead
+// of the chosen columns; nil goes back to the columns.
+func (m *ImagesModel) SetRowFormat(f *formatter.RowFormat) {
+	m.format = f
+	m.table.SetColumns(m.tableColumns())
+	m.SetImages(m.images)
+}
+
+func (m ImagesModel) tableColumns() []Column {
+	if m.format != nil {
+		return formatColumns(m.format)
+	}
+	return imageColumns.Columns(m.columns)
+}
+
+func (m ImagesModel) rowValues(img image.Summary) []string {
+	if m.format != nil {
+		return m.format.Row(formatter.NewImageFormatter(img, true))
+	}
+	return imageColumns.Values(m.columns, img)
+}
+
 // SelectedImage returns the image under the cursor, or nil.
 func (m ImagesModel) SelectedImage() *image.Summary {
 	row := m.table.SelectedRow()
@@ -85,12 +180,33 @@ func (m ImagesModel) SelectedImage() *image.Summary {
 	return nil
 }
 
+// MarkedCount returns the number of images marked.
+func (m ImagesModel) MarkedCount() int {
+	return m.table.MarkedCount()
+}
+
+// ClearMarks unmarks every image.
+func (m *ImagesModel) ClearMarks() {
+	m.table.ClearMarks()
+}
+
+// MarkedImages returns the marked images, in list order.
+func (m ImagesModel) MarkedImages() []image.Summary {
+	var marked []image.Summary
+	for _, row := range m.table.MarkedRows() {
+		if r, ok := row.(imageRow); ok {
+			marked = append(marked, r.image)
+		}
+	}
+	return marked
+}
+
 // Update handles image-list-specific key events.
 func (m ImagesModel) Update(msg tea.Msg) (ImagesModel, tea.Cmd) {
 	if m.filter.Active() {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -128,6 +244,27 @@ func (m *ImagesModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
 
+// SortBy sorts the list by the column with the given title.
+func (m *ImagesModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
+
+// SortColumn returns the title of the column the list is sorted by.
+func (m ImagesModel) SortColumn() string {
+	return m.table.SortTitle()
+}
+
+// FilterText returns the filter applied to the list.
+func (m ImagesModel) FilterText() string {
+	return m.table.FilterText()
+}
+
+// SetFilterText filters the list as if text had been typed in the filter
+// input.
+func (m *ImagesModel) SetFilterText(text string) error {
+	return m.table.SetFilter(text)
+}
+
 func (m ImagesModel) widgetHeader() string {
 	return RenderWidgetHeader(WidgetHeaderOpts{
 		Icon:     "📦",
@@ -135,6 +272,7 @@ func (m ImagesModel) widgetHeader() string {
 		Total:    m.table.TotalRowCount(),
 		Filtered: m.table.RowCount(),
 		Filter:   m.table.FilterText(),
+		Marked:   m.table.MarkedCount(),
 		Width:    m.table.Width(),
 		Accent:   DryTheme.Secondary,
 	})
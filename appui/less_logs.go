@@ -0,0 +1,278 @@
+package appui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/moncho/dry/docker"
+)
+
+// ReopenLogsMsg asks for the logs shown by the less viewer to be read
+// again with other options, replacing the lines shown.
+type ReopenLogsMsg struct {
+	Options docker.LogOptions
+}
+
+// AddLogSourceMsg asks for the logs of a container, given by name or ID,
+// to be merged into the logs shown by the less viewer.
+type AddLogSourceMsg struct {
+	Name string
+}
+
+// RemoveLogSourceMsg asks for the logs of a container to be no longer
+// merged into the logs shown by the less viewer.
+type RemoveLogSourceMsg struct {
+	Name string
+}
+
+// StderrLineMark starts the lines of a log stream that were written to
+// stderr. The less viewer removes it from the lines it shows.
+const StderrLineMark = "\x1e"
+
+// logStreams are the streams of log lines shown: both, stdout or stderr.
+type logStreams int
+
+const (
+	bothStreams logStreams = iota
+	stdoutOnly
+	stderrOnly
+)
+
+func (s logStreams) String() string {
+	switch s {
+	case stdoutOnly:
+		return "stdout"
+	case stderrOnly:
+		return "stderr"
+	}
+	return "stdout+stderr"
+}
+
+// shows returns true if lines of the given stream are shown.
+func (s logStreams) shows(stderr bool) bool {
+	switch s {
+	case stdoutOnly:
+		return !stderr
+	case stderrOnly:
+		return stderr
+	}
+	return true
+}
+
+// logRangeKeys are the keys jumping to the last minutes or hours of logs.
+var logRangeKeys = map[string]string{
+	"1": "5m",
+	"2": "1h",
+	"3": "24h",
+}
+
+// SetLogOptions tells the viewer it shows container logs read with the
+// given options, enabling the keys that read them again differently.
+func (m *LessModel) SetLogOptions(opts docker.LogOptions) {
+	m.logs = &opts
+}
+
+// SetLogSources tells the viewer it shows the merged logs of the named
+// containers, enabling the keys that add and remove containers.
+func (m *LessModel) SetLogSources(names []string) {
+	m.sources = append([]string{}, names...)
+}
+
+// ReplaceContent replaces the lines shown, keeping the title, the filter
+// and the search.
+func (m *LessModel) ReplaceContent(content string) {
+	m.content = content
+	m.lines = strings.Split(content, "\n")
+	m.indexLines(0)
+	m.refresh()
+	if m.following {
+		m.viewport.GotoBottom()
+		m.current = m.nearestMatch()
+	}
+}
+
+// updateLogKeys handles the keys reading the logs again. It returns false
+// for keys that are not about logs.
+func (m LessModel) updateLogKeys(key string) (LessModel, tea.Cmd, bool) {
+	if m.sources != nil && (key == "+" || key == "-") {
+		m.mode = lessAddSource
+		m.bufferInput.Prompt = "Add logs of: "
+		m.bufferInput.Placeholder = "container name or ID"
+		if key == "-" {
+			m.mode = lessRemoveSource
+			m.bufferInput.Prompt = "Remove logs of: "
+			m.bufferInput.Placeholder = strings.Join(m.sources, ", ")
+		}
+		m.bufferInput.SetValue("")
+		return m, m.bufferInput.Focus(), true
+	}
+	if m.logs == nil {
+		return m, nil, false
+	}
+	opts := *m.logs
+	switch key {
+	case "t":
+		opts.Timestamps = !opts.Timestamps
+	case "s":
+		opts.Follow = !opts.Follow
+	case "1", "2", "3":
+		opts.Since, opts.Until, opts.Tail = logRangeKeys[key], "", 0
+	case "r":
+		m.mode = lessLogRange
+		m.bufferInput.Prompt = "Logs: "
+		m.bufferInput.Placeholder = "5m, 500 (last lines), all, or since..until such as 2h..1h or 2026-10-17T10:00..2026-10-17T11:00"
+		m.bufferInput.SetValue("")
+		return m, m.bufferInput.Focus(), true
+	default:
+		return m, nil, false
+	}
+	return m, m.reopenLogs(opts), true
+}
+
+// updateLogRange handles the prompt for the range of logs to read.
+func (m LessModel) updateLogRange(msg tea.Msg) (LessModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyPressMsg); ok {
+		switch key.String() {
+		case "esc":
+			m.mode = lessNormal
+			m.bufferInput.Blur()
+			return m, nil
+		case "enter":
+			m.mode = lessNormal
+			m.bufferInput.Blur()
+			opts, err := parseLogRange(m.bufferInput.Value(), *m.logs)
+			if err != nil {
+				m.message = err.Error()
+				return m, nil
+			}
+			return m, m.reopenLogs(opts)
+		}
+	}
+	var cmd tea.Cmd
+	m.bufferInput, cmd = m.bufferInput.Update(msg)
+	return m, cmd
+}
+
+// updateLogSource handles the prompt for the container whose logs are
+// added to or removed from the merged logs.
+func (m LessModel) updateLogSource(msg tea.Msg) (LessModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyPressMsg); ok {
+		switch key.String() {
+		case "esc":
+			m.mode = lessNormal
+			m.bufferInput.Blur()
+			return m, nil
+		case "enter":
+			mode := m.mode
+			m.mode = lessNormal
+			m.bufferInput.Blur()
+			name := strings.TrimSpace(m.bufferInput.Value())
+			if name == "" {
+				return m, nil
+			}
+			if mode == lessRemoveSource {
+				return m, func() tea.Msg { return RemoveLogSourceMsg{Name: name} }
+			}
+			return m, func() tea.Msg { return AddLogSourceMsg{Name: name} }
+		}
+	}
+	var cmd tea.Cmd
+	m.bufferInput, cmd = m.bufferInput.Update(msg)
+	return m, cmd
+}
+
+func (m *LessModel) reopenLogs(opts docker.LogOptions) tea.Cmd {
+	m.logs = &opts
+	return func() tea.Msg { return ReopenLogsMsg{Options: opts} }
+}
+
+// parseLogRange parses what is typed at the log range prompt into the
+// options to read logs with: "all" for every line, a number for the last
+// lines, a duration for the logs written since, or a since..until range
+// of durations or dates, either end of which can be left out.
+func parseLogRange(s string, opts docker.LogOptions) (docker.LogOptions, error) {
+	s = strings.TrimSpace(s)
+	opts.Since, opts.Until, opts.Tail = "", "", 0
+	switch {
+	case s == "" || s == "all":
+		return opts, nil
+	case strings.Contains(s, ".."):
+		since, until, _ := strings.Cut(s, "..")
+		since, until = strings.TrimSpace(since), strings.TrimSpace(until)
+		if since == "" && until == "" {
+			return opts, errors.New("a range needs a start, an end or both")
+		}
+		for _, t := range []string{since, until} {
+			if t != "" && !isLogTime(t) {
+				return opts, fmt.Errorf("invalid time %q, use 2h or 2006-01-02T15:04", t)
+			}
+		}
+		opts.Since, opts.Until = since, until
+		return opts, nil
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(s, "tail"))); err == nil {
+		if n <= 0 {
+			return opts, fmt.Errorf("invalid number of lines %d", n)
+		}
+		opts.Tail = n
+		return opts, nil
+	}
+	if !isLogTime(s) {
+		return opts, fmt.Errorf("invalid log range %q", s)
+	}
+	opts.Since = s
+	return opts, nil
+}
+
+// logTimeLayouts are the dates accepted for the ends of a log range, as
+// the Docker client understands them.
+var logTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+func isLogTime(s string) bool {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d > 0
+	}
+	for _, layout := range logTimeLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// logRangeLabel describes the logs read with the given options.
+func logRangeLabel(opts docker.LogOptions) string {
+	var parts []string
+	switch {
+	case opts.Tail > 0:
+		parts = append(parts, fmt.Sprintf("last %d lines", opts.Tail))
+	case opts.Since != "" && opts.Until != "":
+		parts = append(parts, opts.Since+".."+opts.Until)
+	case opts.Since != "":
+		if _, err := time.ParseDuration(opts.Since); err == nil {
+			parts = append(parts, "last "+opts.Since)
+		} else {
+			parts = append(parts, "since "+opts.Since)
+		}
+	case opts.Until != "":
+		parts = append(parts, "until "+opts.Until)
+	default:
+		parts = append(parts, "all lines")
+	}
+	if opts.Timestamps {
+		parts = append(parts, "timestamps")
+	}
+	if !opts.Follow {
+		parts = append(parts, "not streaming")
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
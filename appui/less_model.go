@@ -1,6 +1,7 @@
 package appui
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -9,6 +10,11 @@ import (
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/logline"
+	"github.com/moncho/dry/search"
+	"github.com/moncho/dry/terminal"
 )
 
 // CloseOverlayMsg signals that an overlay should be closed.
@@ -20,21 +26,57 @@ const (
 	lessNormal lessMode = iota
 	lessSearching
 	lessFiltering
+	lessWriting
+	lessPiping
+	lessLogRange
+	lessAddSource
+	lessRemoveSource
 )
 
+// WriteBufferMsg asks for the lines shown by the less viewer to be written
+// to a file.
+type WriteBufferMsg struct {
+	Path    string
+	Content string
+}
+
+// PipeBufferMsg asks for the lines shown by the less viewer to be piped to
+// a shell command.
+type PipeBufferMsg struct {
+	Command string
+	Content string
+}
+
 // LessModel is a scrollable text viewer with search and filter support.
 type LessModel struct {
 	viewport    viewport.Model
 	searchInput textinput.Model
 	filterInput textinput.Model
+	bufferInput textinput.Model // file or command the buffer goes to
 
-	content   string   // original full content
-	lines     []string // original lines
-	filtered  []string // lines after filter
+	content   string            // original full content
+	lines     []string          // original lines
+	records   []*logline.Record // each line parsed, nil for lines that are not JSON or logfmt
+	filtered  []string          // lines after filter, structured ones rendered as records
+	parsed    int               // number of structured lines
+	stderr    []bool            // each line was written to stderr
+	errLines  int               // number of lines written to stderr
+	streams   logStreams        // streams of the lines shown
+	rawLogs   bool              // show structured lines as they were written
 	mode      lessMode
 	pattern   string // current search pattern
-	filter    string // current filter pattern
-	following bool   // auto-scroll to bottom
+	filter    string // current filter pattern, !pattern hides matching lines
+	regex     bool   // patterns are regular expressions
+	matchCase bool   // patterns tell upper from lower case
+	matches   []int  // line of each search match, in the filtered lines
+	current   int    // index in matches of the selected match, -1 if none
+	searchErr error
+	filterErr error
+	raw       bool               // write and pipe the buffer with its ANSI escape codes
+	message   string             // outcome of the last write or pipe
+	logs      *docker.LogOptions // options the logs shown were read with, nil if not logs
+	sources   []string           // containers whose logs are merged, nil if not merged logs
+	following bool               // auto-scroll to bottom
 	title     string
 	width     int
 	height    int
@@ -56,13 +98,26 @@ func NewLessModel() LessModel {
 
 	fi := textinput.New()
 	fi.Prompt = "Filter: "
-	fi.Placeholder = "filter pattern..."
+	fi.Placeholder = "pattern, level>=warn, key=value, or !pattern to hide matching lines..."
 	fi.CharLimit = 256
 
+	bi := textinput.New()
+	bi.CharLimit = 512
+
+	vp.HighlightStyle = lipgloss.NewStyle().
+		Foreground(DryTheme.Bg).
+		Background(DryTheme.Tertiary)
+	vp.SelectedHighlightStyle = lipgloss.NewStyle().
+		Foreground(DryTheme.Bg).
+		Background(DryTheme.Warning).
+		Bold(true)
+
 	return LessModel{
 		viewport:    vp,
 		searchInput: si,
 		filterInput: fi,
+		bufferInput: bi,
+		current:     -1,
 	}
 }
 
@@ -79,11 +134,10 @@ func (m *LessModel) SetContent(content string, title string) {
 	m.content = content
 	m.title = title
 	m.lines = strings.Split(content, "\n")
-	m.filtered = m.lines
 	m.filter = ""
 	m.pattern = ""
-	m.viewport.SetContent(content)
-	m.viewport.ClearHighlights()
+	m.indexLines(0)
+	m.refresh()
 }
 
 // AppendContent adds content (for streaming).
@@ -92,6 +146,7 @@ func (m *LessModel) AppendContent(text string) {
 	// Only split the new text and merge with existing lines,
 	// avoiding a full re-split of the entire content.
 	newLines := strings.Split(text, "\n")
+	from := max(len(m.lines)-1, 0)
 	if len(m.lines) > 0 && len(newLines) > 0 {
 		// The last existing line may be a partial line; merge with
 		// the first segment of the new text.
@@ -100,17 +155,18 @@ func (m *LessModel) AppendContent(text string) {
 	} else {
 		m.lines = append(m.lines, newLines...)
 	}
-	if m.filter != "" {
-		m.applyFilter()
-	} else {
-		m.filtered = m.lines
-	}
-	// Preserve scroll position when not following, since SetContent resets it.
+	m.indexLines(from)
+	m.applyFilter()
+	// Preserve scroll position when not following, since SetContent resets
+	// it, and the selected match, which highlighting again resets.
 	yOff := m.viewport.YOffset()
+	selected := m.current
 	m.viewport.SetContent(strings.Join(m.filtered, "\n"))
 	m.applySearch()
+	m.selectMatch(selected)
 	if m.following {
 		m.viewport.GotoBottom()
+		m.current = m.nearestMatch()
 	} else {
 		m.viewport.SetYOffset(yOff)
 	}
@@ -141,6 +197,12 @@ func (m LessModel) Update(msg tea.Msg) (LessModel, tea.Cmd) {
 		return m.updateSearch(msg)
 	case lessFiltering:
 		return m.updateFilter(msg)
+	case lessWriting, lessPiping:
+		return m.updateBufferInput(msg)
+	case lessLogRange:
+		return m.updateLogRange(msg)
+	case lessAddSource, lessRemoveSource:
+		return m.updateLogSource(msg)
 	default:
 		return m.updateNormal(msg)
 	}
@@ -149,6 +211,10 @@ func (m LessModel) Update(msg tea.Msg) (LessModel, tea.Cmd) {
 func (m LessModel) updateNormal(msg tea.Msg) (LessModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyPressMsg:
+		m.message = ""
+		if lm, cmd, ok := m.updateLogKeys(msg.String()); ok {
+			return lm, cmd
+		}
 		switch msg.String() {
 		case "esc", "q":
 			return m, func() tea.Msg { return CloseOverlayMsg{} }
@@ -160,33 +226,85 @@ func (m LessModel) updateNormal(msg tea.Msg) (LessModel, tea.Cmd) {
 			m.mode = lessFiltering
 			m.filterInput.SetValue("")
 			return m, m.filterInput.Focus()
+		case "w":
+			m.mode = lessWriting
+			m.bufferInput.Placeholder = "file to write the lines shown to..."
+			m.bufferInput.SetValue("")
+			m.setBufferPrompt()
+			return m, m.bufferInput.Focus()
+		case "|":
+			m.mode = lessPiping
+			m.bufferInput.Placeholder = "command to pipe the lines shown to..."
+			m.bufferInput.SetValue("")
+			m.setBufferPrompt()
+			return m, m.bufferInput.Focus()
 		case "f":
 			m.following = !m.following
 			if m.following {
 				m.viewport.GotoBottom()
+				m.current = m.nearestMatch()
 			}
 			return m, nil
 		case "g":
+			yOff := m.viewport.YOffset()
 			m.viewport.GotoTop()
+			m.scrolled(yOff)
 			return m, nil
 		case "G":
 			m.viewport.GotoBottom()
+			m.current = m.nearestMatch()
 			return m, nil
 		case "n":
-			m.viewport.HighlightNext()
+			if len(m.matches) > 0 {
+				m.viewport.HighlightNext()
+				m.current = (m.current + 1) % len(m.matches)
+			}
 			return m, nil
 		case "N":
-			m.viewport.HighlightPrevious()
+			if len(m.matches) > 0 {
+				m.viewport.HighlightPrevious()
+				m.current = (m.current - 1 + len(m.matches)) % len(m.matches)
+			}
+			return m, nil
+		case "R":
+			m.regex = !m.regex
+			m.refresh()
+			return m, nil
+		case "i":
+			m.matchCase = !m.matchCase
+			m.refresh()
+			return m, nil
+		case "e":
+			if m.errLines > 0 || m.streams != bothStreams {
+				m.streams = (m.streams + 1) % 3
+				m.refresh()
+			}
+			return m, nil
+		case "J":
+			if m.hasRecords() {
+				m.rawLogs = !m.rawLogs
+				m.refresh()
+			}
 			return m, nil
 		}
 	}
 
 	// Forward to viewport for scrolling (up/down/pgup/pgdown)
+	yOff := m.viewport.YOffset()
 	var cmd tea.Cmd
 	m.viewport, cmd = m.viewport.Update(msg)
+	m.scrolled(yOff)
 	return m, cmd
 }
 
+// scrolled follows the viewport, which selects the first match in view
+// once it has been scrolled from the given offset.
+func (m *LessModel) scrolled(yOff int) {
+	if m.viewport.YOffset() != yOff {
+		m.current = m.nearestMatch()
+	}
+}
+
 func (m LessModel) updateSearch(msg tea.Msg) (LessModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyPressMsg:
@@ -215,18 +333,14 @@ func (m LessModel) updateFilter(msg tea.Msg) (LessModel, tea.Cmd) {
 		case "esc":
 			m.mode = lessNormal
 			m.filter = ""
-			m.filtered = m.lines
-			m.viewport.SetContent(strings.Join(m.filtered, "\n"))
-			m.applySearch()
 			m.filterInput.Blur()
+			m.refresh()
 			return m, nil
 		case "enter":
 			m.mode = lessNormal
 			m.filter = m.filterInput.Value()
 			m.filterInput.Blur()
-			m.applyFilter()
-			m.viewport.SetContent(strings.Join(m.filtered, "\n"))
-			m.applySearch()
+			m.refresh()
 			return m, nil
 		}
 	}
@@ -235,38 +349,279 @@ func (m LessModel) updateFilter(msg tea.Msg) (LessModel, tea.Cmd) {
 	return m, cmd
 }
 
+// updateBufferInput handles the prompt for the file the buffer is written
+// to or the command it is piped to. Tab switches between plain text and
+// raw output.
+func (m LessModel) updateBufferInput(msg tea.Msg) (LessModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyPressMsg); ok {
+		switch key.String() {
+		case "esc":
+			m.mode = lessNormal
+			m.bufferInput.Blur()
+			return m, nil
+		case "tab":
+			m.raw = !m.raw
+			m.setBufferPrompt()
+			return m, nil
+		case "enter":
+			mode := m.mode
+			m.mode = lessNormal
+			m.bufferInput.Blur()
+			target := strings.TrimSpace(m.bufferInput.Value())
+			if target == "" {
+				return m, nil
+			}
+			content := m.BufferText(m.raw)
+			if mode == lessWriting {
+				return m, func() tea.Msg { return WriteBufferMsg{Path: target, Content: content} }
+			}
+			return m, func() tea.Msg { return PipeBufferMsg{Command: target, Content: content} }
+		}
+	}
+	var cmd tea.Cmd
+	m.bufferInput, cmd = m.bufferInput.Update(msg)
+	return m, cmd
+}
+
+func (m *LessModel) setBufferPrompt() {
+	output := "plain"
+	if m.raw {
+		output = "raw"
+	}
+	if m.mode == lessPiping {
+		m.bufferInput.Prompt = "Pipe (" + output + ", tab to switch) to: "
+	} else {
+		m.bufferInput.Prompt = "Write (" + output + ", tab to switch) to: "
+	}
+}
+
+// BufferText returns the lines shown, after any filter, with their ANSI
+// escape codes when raw is true and without them otherwise.
+func (m LessModel) BufferText(raw bool) string {
+	if raw {
+		return strings.Join(m.filtered, "\n")
+	}
+	plain := make([]string, len(m.filtered))
+	for i, line := range m.filtered {
+		for _, l := range terminal.RemoveANSIEscapeCharacters(line) {
+			plain[i] += string(l)
+		}
+	}
+	return strings.Join(plain, "\n")
+}
+
+// SetMessage shows the outcome of writing or piping the buffer on the
+// status line, until the next key is pressed.
+func (m *LessModel) SetMessage(message string) {
+	m.message = message
+}
+
+// searchOptions returns how search and filter patterns are matched.
+func (m LessModel) searchOptions() search.Options {
+	return search.Options{Regexp: m.regex, IgnoreCase: !m.matchCase}
+}
+
+// refresh filters the lines and searches them again, after the filter or
+// the way patterns are matched changed.
+func (m *LessModel) refresh() {
+	m.applyFilter()
+	m.viewport.SetContent(strings.Join(m.filtered, "\n"))
+	m.applySearch()
+}
+
+// applySearch highlights the matches of the search pattern and selects
+// the first one in view, or the first one if none is.
 func (m *LessModel) applySearch() {
+	m.viewport.ClearHighlights()
+	m.matches = nil
+	m.current = -1
+	m.searchErr = nil
 	if m.pattern == "" {
-		m.viewport.ClearHighlights()
 		return
 	}
 
-	re, err := regexp.Compile("(?i)" + regexp.QuoteMeta(m.pattern))
+	re, err := search.Compile(m.pattern, m.searchOptions())
 	if err != nil {
-		m.viewport.ClearHighlights()
+		m.searchErr = err
 		return
 	}
 
-	content := strings.Join(m.filtered, "\n")
-	locs := re.FindAllStringIndex(content, -1)
-	m.viewport.SetHighlights(locs)
-	if len(locs) > 0 {
+	// Lines are matched one by one, so that ^ and $ anchor to them, and
+	// the spans are then offset into the content of the viewport, which
+	// places highlights in the text without its ANSI escape codes.
+	var spans [][]int
+	pos := 0
+	for i, line := range m.filtered {
+		line = ansi.Strip(line)
+		for _, span := range search.Spans(re, line) {
+			spans = append(spans, []int{pos + span[0], pos + span[1]})
+			m.matches = append(m.matches, i)
+		}
+		pos += len(line) + 1
+	}
+	if len(spans) == 0 {
+		return
+	}
+	m.viewport.SetHighlights(spans)
+	m.current = m.nearestMatch()
+	if m.current < 0 {
 		m.viewport.HighlightNext()
+		m.current = 0
 	}
 }
 
-func (m *LessModel) applyFilter() {
-	if m.filter == "" {
-		m.filtered = m.lines
+// nearestMatch returns the first match at or below the top of the
+// viewport, the one the viewport selects when scrolled.
+func (m LessModel) nearestMatch() int {
+	for i, line := range m.matches {
+		if line >= m.viewport.YOffset() {
+			return i
+		}
+	}
+	return -1
+}
+
+// selectMatch moves the selection to the given match, if there is one.
+func (m *LessModel) selectMatch(i int) {
+	if i < 0 || i >= len(m.matches) {
 		return
 	}
-	lower := strings.ToLower(m.filter)
-	m.filtered = nil
-	for _, line := range m.lines {
-		if strings.Contains(strings.ToLower(line), lower) {
-			m.filtered = append(m.filtered, line)
+	for m.current != i {
+		m.viewport.HighlightNext()
+		m.current = (m.current + 1) % len(m.matches)
+	}
+}
+
+// indexLines removes the stderr marks of the lines from the given one on,
+// and parses them as structured log lines. The line at from may have been
+// indexed before, as the start of a line completed since.
+func (m *LessModel) indexLines(from int) {
+	from = min(from, len(m.records))
+	for i := from; i < len(m.records); i++ {
+		if m.records[i] != nil {
+			m.parsed--
+		}
+		if m.stderr[i] {
+			m.errLines--
 		}
 	}
+	continued := from < len(m.stderr) && m.stderr[from]
+	m.records = m.records[:from]
+	m.stderr = m.stderr[:from]
+	for i, line := range m.lines[from:] {
+		line, stderr := strings.CutPrefix(line, StderrLineMark)
+		stderr = stderr || i == 0 && continued
+		m.lines[from+i] = line
+		var rec *logline.Record
+		if r, ok := logline.Parse(line); ok {
+			rec = &r
+			m.parsed++
+		}
+		if stderr {
+			m.errLines++
+		}
+		m.records = append(m.records, rec)
+		m.stderr = append(m.stderr, stderr)
+	}
+}
+
+// hasRecords returns true if any line is a structured log line.
+func (m LessModel) hasRecords() bool {
+	return m.parsed > 0
+}
+
+// applyFilter keeps the lines of the streams shown matching the filter, or
+// those not matching it when it starts with !. Unless shown raw,
+// structured lines are rendered as "time level msg key=val" and the field
+// terms of the filter, such as level>=warn or trace_id=abc, keep the lines
+// with matching fields. An invalid filter keeps every line.
+func (m *LessModel) applyFilter() {
+	m.filterErr = nil
+	structured := !m.rawLogs && m.hasRecords()
+	pattern, hide := strings.CutPrefix(m.filter, "!")
+	var terms []logline.Term
+	if structured {
+		terms, pattern = logline.SplitTerms(pattern)
+	}
+	var re *regexp.Regexp
+	if pattern != "" {
+		var err error
+		if re, err = search.Compile(pattern, m.searchOptions()); err != nil {
+			m.filterErr = err
+			terms = nil
+		}
+	}
+	filtering := re != nil || len(terms) > 0
+	m.filtered = make([]string, 0, len(m.lines))
+	var kinds []lineKind
+	for i, line := range m.lines {
+		if !m.streams.shows(m.stderr[i]) {
+			continue
+		}
+		rec := m.records[i]
+		if structured && rec != nil {
+			line = rec.String()
+		}
+		if filtering && matchesFilter(line, rec, re, terms) == hide {
+			continue
+		}
+		m.filtered = append(m.filtered, line)
+		kind := lineKind{stderr: m.stderr[i]}
+		if structured && rec != nil {
+			kind.level = rec.Level
+		}
+		kinds = append(kinds, kind)
+	}
+	m.viewport.StyleLineFunc = nil
+	if structured || m.errLines > 0 {
+		m.viewport.StyleLineFunc = func(i int) lipgloss.Style {
+			if i < len(kinds) {
+				return kinds[i].style()
+			}
+			return lipgloss.NewStyle()
+		}
+	}
+}
+
+// lineKind is what the style of a line shown depends on.
+type lineKind struct {
+	level  logline.Level
+	stderr bool
+}
+
+// matchesFilter returns true if the line matches the filter pattern, if
+// any, and its record matches every term.
+func matchesFilter(line string, rec *logline.Record, re *regexp.Regexp, terms []logline.Term) bool {
+	if re != nil && !re.MatchString(ansi.Strip(line)) {
+		return false
+	}
+	for _, t := range terms {
+		if rec == nil || !t.Match(*rec) {
+			return false
+		}
+	}
+	return true
+}
+
+// style returns the style of a line: the color of its level, if it has
+// one, or that of stderr lines.
+func (k lineKind) style() lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch k.level {
+	case logline.LevelTrace, logline.LevelDebug:
+		return style.Foreground(DryTheme.FgSubtle)
+	case logline.LevelWarn:
+		return style.Foreground(DryTheme.Warning)
+	case logline.LevelError:
+		return style.Foreground(DryTheme.Error)
+	case logline.LevelFatal:
+		return style.Foreground(DryTheme.Error).Bold(true)
+	case logline.LevelUnknown:
+		if k.stderr {
+			return style.Foreground(DryTheme.Secondary)
+		}
+	}
+	return style
 }
 
 // View renders the less viewer.
@@ -290,30 +645,77 @@ func (m LessModel) View() string {
 		sections = append(sections, m.searchInput.View())
 	case lessFiltering:
 		sections = append(sections, m.filterInput.View())
+	case lessWriting, lessPiping, lessLogRange, lessAddSource, lessRemoveSource:
+		sections = append(sections, m.bufferInput.View())
 	default:
 		status := m.statusLine()
 		statusStyle := lipgloss.NewStyle().
 			Foreground(DryTheme.FgSubtle).
 			Width(m.width)
-		sections = append(sections, statusStyle.Render(status))
+		// The status line lists many keys; truncate it rather than let it
+		// wrap and push the viewport up.
+		sections = append(sections, statusStyle.Render(ansi.Truncate(status, m.width, "…")))
 	}
 
 	return strings.Join(sections, "\n")
 }
 
 func (m LessModel) statusLine() string {
+	if m.message != "" {
+		return m.message
+	}
 	parts := []string{"esc back"}
 	if m.following {
 		parts = append(parts, "f unfollow")
 	} else {
 		parts = append(parts, "f follow")
 	}
-	parts = append(parts, "/ search", "F filter")
-	if m.pattern != "" {
-		parts = append(parts, "n/N next/prev")
+	parts = append(parts, "/ search", "F filter", "w write", "| pipe")
+	if m.sources != nil {
+		parts = append(parts, "+/- add/remove containers", "["+strings.Join(m.sources, ", ")+"]")
+	}
+	if m.logs != nil {
+		parts = append(parts, "1/2/3 last 5m/1h/24h", "r range", "t timestamps", "s stream",
+			logRangeLabel(*m.logs))
+	}
+	if m.errLines > 0 || m.streams != bothStreams {
+		parts = append(parts, "e streams ["+m.streams.String()+"]")
+	}
+	if m.hasRecords() {
+		if m.rawLogs {
+			parts = append(parts, "J structured")
+		} else {
+			parts = append(parts, "J raw")
+		}
+	}
+	if m.regex {
+		parts = append(parts, "R literal")
+	} else {
+		parts = append(parts, "R regex")
+	}
+	if m.matchCase {
+		parts = append(parts, "i ignore case")
+	} else {
+		parts = append(parts, "i match case")
+	}
+	if m.pattern != "" && m.searchErr == nil {
+		switch {
+		case len(m.matches) == 0:
+			parts = append(parts, "[no matches]")
+		case m.current < 0:
+			parts = append(parts, "n/N next/prev", fmt.Sprintf("[%d matches]", len(m.matches)))
+		default:
+			parts = append(parts, "n/N next/prev",
+				fmt.Sprintf("[match %d/%d]", m.current+1, len(m.matches)))
+		}
 	}
 	if m.filter != "" {
 		parts = append(parts, "[filter: "+m.filter+"]")
 	}
+	for _, err := range []error{m.searchErr, m.filterErr} {
+		if err != nil {
+			parts = append(parts, lipgloss.NewStyle().Foreground(DryTheme.Error).Render(err.Error()))
+		}
+	}
 	return strings.Join(parts, "  ")
 }
@@ -14,10 +14,17 @@ import (
 	"github.com/charmbracelet/x/ansi"
 	"github.com/docker/go-units"
 	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/metrics"
 )
 
 const monitorHistoryWindow = 3 * time.Minute
 
+// monitorChartWindows are the spans of time charts cycle through when
+// stats are kept on disk.
+var monitorChartWindows = []time.Duration{
+	monitorHistoryWindow, time.Hour, 6 * time.Hour, 24 * time.Hour, 7 * 24 * time.Hour,
+}
+
 // MonitorStatsMsg carries stats update for a container.
 type MonitorStatsMsg struct {
 	CID     string
@@ -43,6 +50,73 @@ func (r monitorRow) ID() string        { return r.cid }
 type MonitorSeries struct {
 	CPU    []MonitorPoint
 	Memory []MonitorPoint
+	// Network and block IO, in bytes per second.
+	NetworkRx  []MonitorPoint
+	NetworkTx  []MonitorPoint
+	BlockRead  []MonitorPoint
+	BlockWrite []MonitorPoint
+	Pids       []MonitorPoint
+	// Window is the span of time the series are charted over.
+	Window time.Duration
+	// Panel is the panel of charts shown.
+	Panel MonitorPanel
+}
+
+// MonitorPanel is a set of charts of the monitor details.
+type MonitorPanel int
+
+// Monitor panels, in the order they are cycled through.
+const (
+	MonitorPanelUsage MonitorPanel = iota
+	MonitorPanelNetwork
+	MonitorPanelBlockIO
+	MonitorPanelPids
+)
+
+// MonitorPanels lists the monitor panels, in the order they are cycled
+// through.
+var MonitorPanels = []MonitorPanel{MonitorPanelUsage, MonitorPanelNetwork, MonitorPanelBlockIO, MonitorPanelPids}
+
+func (p MonitorPanel) String() string {
+	switch p {
+	case MonitorPanelNetwork:
+		return "Network"
+	case MonitorPanelBlockIO:
+		return "Block IO"
+	case MonitorPanelPids:
+		return "PIDs"
+	}
+	return "CPU/Memory"
+}
+
+// addSample adds the points of a sample to the series with the given
+// function, network and block IO rates being taken since prev, if any.
+func (s *MonitorSeries) addSample(sample metrics.Sample, prev *metrics.Sample, add func([]MonitorPoint, MonitorPoint) []MonitorPoint) {
+	point := func(v float64) MonitorPoint {
+		return MonitorPoint{At: sample.At, Value: v}
+	}
+	s.CPU = add(s.CPU, point(sample.CPU))
+	s.Memory = add(s.Memory, point(sample.Memory))
+	s.Pids = add(s.Pids, point(sample.Pids))
+	if prev == nil {
+		return
+	}
+	rx, tx, read, write := sample.Rates(*prev)
+	s.NetworkRx = add(s.NetworkRx, point(rx))
+	s.NetworkTx = add(s.NetworkTx, point(tx))
+	s.BlockRead = add(s.BlockRead, point(read))
+	s.BlockWrite = add(s.BlockWrite, point(write))
+}
+
+// appendSeries appends the points of o to the series.
+func (s *MonitorSeries) appendSeries(o MonitorSeries) {
+	s.CPU = append(s.CPU, o.CPU...)
+	s.Memory = append(s.Memory, o.Memory...)
+	s.NetworkRx = append(s.NetworkRx, o.NetworkRx...)
+	s.NetworkTx = append(s.NetworkTx, o.NetworkTx...)
+	s.BlockRead = append(s.BlockRead, o.BlockRead...)
+	s.BlockWrite = append(s.BlockWrite, o.BlockWrite...)
+	s.Pids = append(s.Pids, o.Pids...)
 }
 
 type MonitorPoint struct {
@@ -63,10 +137,18 @@ type MonitorModel struct {
 	daemon  ContainerStatsSource
 	stats   map[string]*docker.Stats
 	history map[string]MonitorSeries
+	last    map[string]metrics.Sample // last sample of each container
 	cancels map[string]context.CancelFunc
-	active  bool
-	width   int
-	height  int
+	paused  map[string]bool  // containers whose processes are paused
+	store   *metrics.History // stats kept on disk, if any
+	window  time.Duration    // span of time charted
+	panel   MonitorPanel     // charts shown
+	// alerting tells whether an alert is firing for a container, whose
+	// row is then highlighted.
+	alerting func(cid string) bool
+	active   bool
+	width    int
+	height   int
 }
 
 var monitorSortableFields = []int{0, 1, 2, 3, 4, 5}
@@ -86,10 +168,49 @@ func NewMonitorModel() MonitorModel {
 		table:   NewTableModel(columns),
 		stats:   make(map[string]*docker.Stats),
 		history: make(map[string]MonitorSeries),
+		last:    make(map[string]metrics.Sample),
 		cancels: make(map[string]context.CancelFunc),
+		paused:  make(map[string]bool),
+		window:  monitorHistoryWindow,
 	}
 }
 
+// SetHistory keeps the stats of the containers monitored in the given
+// history, letting charts span as much time as it holds.
+func (m *MonitorModel) SetHistory(h *metrics.History) {
+	m.store = h
+}
+
+// SetAlerting sets the function telling whether an alert is firing for a
+// container.
+func (m *MonitorModel) SetAlerting(alerting func(cid string) bool) {
+	m.alerting = alerting
+}
+
+// ChartWindow returns the span of time charts show.
+func (m MonitorModel) ChartWindow() time.Duration {
+	return m.window
+}
+
+// nextChartWindow makes charts span the next of monitorChartWindows the
+// history holds stats for.
+func (m *MonitorModel) nextChartWindow() {
+	if m.store == nil {
+		return
+	}
+	i := slices.Index(monitorChartWindows, m.window)
+	next := monitorChartWindows[(i+1)%len(monitorChartWindows)]
+	if next > m.store.Retention() {
+		next = monitorChartWindows[0]
+	}
+	m.window = next
+}
+
+// ChartPanel returns the panel of charts shown.
+func (m MonitorModel) ChartPanel() MonitorPanel {
+	return m.panel
+}
+
 // SetDaemon sets the Docker daemon reference.
 func (m *MonitorModel) SetDaemon(d ContainerStatsSource) {
 	m.daemon = d
@@ -114,7 +235,9 @@ func (m *MonitorModel) Start() []tea.Cmd {
 	m.active = true
 	m.stats = make(map[string]*docker.Stats)
 	m.history = make(map[string]MonitorSeries)
+	m.last = make(map[string]metrics.Sample)
 	m.cancels = make(map[string]context.CancelFunc)
+	m.paused = make(map[string]bool)
 
 	if m.daemon == nil {
 		return nil
@@ -132,6 +255,9 @@ func (m *MonitorModel) Start() []tea.Cmd {
 			continue
 		}
 		m.cancels[c.ID] = cancel
+		if docker.IsContainerPaused(c) {
+			m.paused[c.ID] = true
+		}
 		cmds = append(cmds, listenContainerStats(c.ID, ch))
 	}
 	return cmds
@@ -145,6 +271,7 @@ func (m *MonitorModel) StopAll() {
 	}
 	m.active = false
 	m.history = make(map[string]MonitorSeries)
+	m.last = make(map[string]metrics.Sample)
 }
 
 func (m *MonitorModel) startContainerStats(c *docker.Container) (<-chan *docker.Stats, context.CancelFunc, error) {
@@ -172,6 +299,31 @@ func (m *MonitorModel) FlushTable() {
 	m.refreshTable()
 }
 
+// SetPaused records whether the processes of a monitored container are
+// paused, which its row then shows.
+func (m *MonitorModel) SetPaused(cid string, paused bool) {
+	if paused {
+		m.paused[cid] = true
+	} else {
+		delete(m.paused, cid)
+	}
+	m.refreshTable()
+}
+
+// SetMemoryLimit shows a new memory limit of a monitored container without
+// waiting for its next stats.
+func (m *MonitorModel) SetMemoryLimit(cid string, limit int64) {
+	s, ok := m.stats[cid]
+	if !ok || s == nil || limit <= 0 {
+		return
+	}
+	updated := *s
+	updated.MemoryLimit = float64(limit)
+	updated.MemoryPercentage = updated.Memory / updated.MemoryLimit * 100
+	m.stats[cid] = &updated
+	m.refreshTable()
+}
+
 // RemoveContainer removes a container from monitoring.
 func (m *MonitorModel) RemoveContainer(cid string) {
 	if cancel, ok := m.cancels[cid]; ok {
@@ -180,6 +332,11 @@ func (m *MonitorModel) RemoveContainer(cid string) {
 	}
 	delete(m.stats, cid)
 	delete(m.history, cid)
+	delete(m.last, cid)
+	delete(m.paused, cid)
+	if m.store != nil {
+		m.store.Forget(cid)
+	}
 	m.refreshTable()
 }
 
@@ -189,9 +346,16 @@ func (m *MonitorModel) refreshTable() {
 		if s == nil || s.Error != nil {
 			continue
 		}
+		columns := monitorRowColumns(s)
+		if m.paused[cid] {
+			columns[1] = ColorFg("⏸ "+s.Name, DryTheme.Warning)
+		}
+		if m.alerting != nil && m.alerting(cid) {
+			columns[1] = ColorFg("⚠ "+s.Name, DryTheme.Error)
+		}
 		rows = append(rows, monitorRow{
 			cid:     cid,
-			columns: monitorRowColumns(s),
+			columns: columns,
 		})
 	}
 	m.table.SetRows(rows)
@@ -224,14 +388,30 @@ func (m MonitorModel) SelectedSeries() MonitorSeries {
 }
 
 func (m MonitorModel) SeriesFor(cid string) MonitorSeries {
-	series, ok := m.history[cid]
-	if !ok {
-		return MonitorSeries{}
-	}
-	return MonitorSeries{
-		CPU:    append([]MonitorPoint(nil), series.CPU...),
-		Memory: append([]MonitorPoint(nil), series.Memory...),
+	series := m.history[cid]
+	result := MonitorSeries{Window: m.window, Panel: m.panel}
+	if m.window > monitorHistoryWindow && m.store != nil {
+		// Stats older than the ones in memory come from disk.
+		until := time.Now()
+		if len(series.CPU) > 0 {
+			until = series.CPU[0].At
+		}
+		samples, _ := m.store.Samples(cid, time.Now().Add(-m.window))
+		for i, s := range samples {
+			if !s.At.Before(until) {
+				break
+			}
+			var prev *metrics.Sample
+			if i > 0 {
+				prev = &samples[i-1]
+			}
+			result.addSample(s, prev, func(points []MonitorPoint, p MonitorPoint) []MonitorPoint {
+				return append(points, p)
+			})
+		}
 	}
+	result.appendSeries(series)
+	return result
 }
 
 func (m MonitorModel) StatsByID(cid string) *docker.Stats {
@@ -255,6 +435,12 @@ func (m MonitorModel) Update(msg tea.Msg) (MonitorModel, tea.Cmd) {
 		case "f1":
 			m.nextSort()
 			return m, nil
+		case "w":
+			m.nextChartWindow()
+			return m, nil
+		case "g":
+			m.panel = (m.panel + 1) % MonitorPanel(len(MonitorPanels))
+			return m, nil
 		}
 	}
 	var cmd tea.Cmd
@@ -293,6 +479,11 @@ func (m *MonitorModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
 
+// SortBy sorts the list by the column with the given title.
+func (m *MonitorModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
+
 // monitorBar renders a half-block progress bar with a numeric label for a
 // percentage value (0–100). Using ▌/█ gives double the resolution of a
 // full-block bar and guarantees a visible indicator when pct > 0.
@@ -490,11 +681,30 @@ func (m *MonitorModel) recordHistory(cid string, stats *docker.Stats) {
 	if stats == nil {
 		return
 	}
+	sample := metrics.Sample{
+		At:         time.Now(),
+		CPU:        stats.CPUPercentage,
+		Memory:     stats.MemoryPercentage,
+		MemoryUsed: stats.Memory,
+		NetworkRx:  stats.NetworkRx,
+		NetworkTx:  stats.NetworkTx,
+		BlockRead:  stats.BlockRead,
+		BlockWrite: stats.BlockWrite,
+		Pids:       float64(stats.PidsCurrent),
+	}
+	var prev *metrics.Sample
+	if last, ok := m.last[cid]; ok {
+		prev = &last
+	}
 	series := m.history[cid]
-	now := time.Now()
-	series.CPU = appendMonitorSample(series.CPU, MonitorPoint{At: now, Value: stats.CPUPercentage})
-	series.Memory = appendMonitorSample(series.Memory, MonitorPoint{At: now, Value: stats.MemoryPercentage})
+	series.addSample(sample, prev, appendMonitorSample)
 	m.history[cid] = series
+	m.last[cid] = sample
+	if m.store != nil {
+		// A sample that cannot be written is dropped, charts still have
+		// the ones in memory.
+		_ = m.store.Record(cid, sample)
+	}
 }
 
 func appendMonitorSample(samples []MonitorPoint, value MonitorPoint) []MonitorPoint {
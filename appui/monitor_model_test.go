@@ -8,6 +8,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/metrics"
 )
 
 func TestMonitorBar_VisualWidth(t *testing.T) {
@@ -401,3 +402,91 @@ func TestAppendMonitorSampleKeepsOnlyRecentWindow(t *testing.T) {
 		t.Fatalf("expected history to keep only the recent window, got %+v", samples)
 	}
 }
+
+func TestMonitor_ChartWindowsReachIntoTheHistoryOnDisk(t *testing.T) {
+	h, err := metrics.NewHistory(t.TempDir(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two samples ten seconds apart are written as one, two hours ago.
+	past := time.Now().Add(-2 * time.Hour)
+	for i := range 2 {
+		if err := h.Record("abc", metrics.Sample{At: past.Add(time.Duration(i) * 10 * time.Second), CPU: 40, Memory: 20}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewMonitorModel()
+	m.SetHistory(h)
+	m.SetSize(120, 20)
+	m.UpdateStats("abc", &docker.Stats{CID: "abc", CPUPercentage: 10, MemoryPercentage: 5}, make(chan *docker.Stats))
+	m.FlushTable()
+
+	press := func() {
+		m, _ = m.Update(tea.KeyPressMsg{Code: 'w', Text: "w"})
+	}
+	if series := m.SelectedSeries(); len(series.CPU) != 1 || series.Window != 3*time.Minute {
+		t.Fatalf("expected the samples in memory over 3 minutes, got %+v", series)
+	}
+	press()
+	if series := m.SelectedSeries(); len(series.CPU) != 1 || series.Window != time.Hour {
+		t.Fatalf("expected no sample on disk in the last hour, got %+v", series)
+	}
+	press()
+	series := m.SelectedSeries()
+	if len(series.CPU) != 2 || series.CPU[0].Value != 40 || series.Memory[0].Value != 20 || series.CPU[1].Value != 10 {
+		t.Fatalf("expected the sample on disk before the one in memory, got %+v", series)
+	}
+	press() // 24h
+	press() // 7d is longer than the history keeps stats for
+	if got := m.ChartWindow(); got != 3*time.Minute {
+		t.Fatalf("expected the windows to cycle back to 3m, got %s", got)
+	}
+}
+
+func TestMonitor_SeriesChartIORatesAndPids(t *testing.T) {
+	m := NewMonitorModel()
+	m.SetSize(120, 20)
+	ch := make(chan *docker.Stats)
+	m.UpdateStats("abc", &docker.Stats{CID: "abc", NetworkRx: 1000, BlockWrite: 500, PidsCurrent: 3}, ch)
+	time.Sleep(10 * time.Millisecond)
+	m.UpdateStats("abc", &docker.Stats{CID: "abc", NetworkRx: 3000, BlockWrite: 100, PidsCurrent: 4}, ch)
+	m.FlushTable()
+
+	series := m.SelectedSeries()
+	if len(series.Pids) != 2 || series.Pids[1].Value != 4 {
+		t.Fatalf("expected a PIDs point per sample, got %+v", series.Pids)
+	}
+	// Rates need a previous sample: one point for two samples.
+	if len(series.NetworkRx) != 1 || series.NetworkRx[0].Value <= 0 {
+		t.Fatalf("expected a positive network rate, got %+v", series.NetworkRx)
+	}
+	if len(series.BlockWrite) != 1 || series.BlockWrite[0].Value != 0 {
+		t.Fatalf("expected a total going down to chart as no IO, got %+v", series.BlockWrite)
+	}
+
+	for _, want := range []MonitorPanel{MonitorPanelNetwork, MonitorPanelBlockIO, MonitorPanelPids, MonitorPanelUsage} {
+		m, _ = m.Update(tea.KeyPressMsg{Code: 'g', Text: "g"})
+		if got := m.SelectedSeries().Panel; got != want {
+			t.Fatalf("expected the %s panel, got %s", want, got)
+		}
+	}
+}
+
+func TestMonitor_PausedContainersAreMarked(t *testing.T) {
+	stats := map[string]*docker.Stats{
+		"aaa": {CID: "aaa", Name: "nginx-proxy"},
+		"bbb": {CID: "bbb", Name: "postgres-db"},
+	}
+	m := newTestMonitor(stats)
+
+	m.SetPaused("bbb", true)
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "⏸ postgres-db") || strings.Contains(view, "⏸ nginx-proxy") {
+		t.Fatalf("expected only postgres-db to be marked paused:\n%s", view)
+	}
+	m.SetPaused("bbb", false)
+	if view := ansi.Strip(m.View()); strings.Contains(view, "⏸") {
+		t.Fatalf("expected the mark to go once unpaused:\n%s", view)
+	}
+}
@@ -12,19 +12,63 @@ type networkRow struct {
 	columns []string
 }
 
-func newNetworkRow(n network.Inspect) networkRow {
-	f := formatter.NewNetworkFormatter(n, true)
+func newNetworkRow(n network.Inspect, values []string) networkRow {
 	return networkRow{
 		network: n,
-		columns: []string{
-			f.ID(), f.Name(), f.Driver(), f.Containers(), f.Scope(), f.Subnet(),
-		},
+		columns: values,
 	}
 }
 
 func (r networkRow) Columns() []string { return r.columns }
 func (r networkRow) ID() string        { return r.network.ID }
 
+// QueryFields returns the fields filter queries match the network by.
+func (r networkRow) QueryFields() QueryFields {
+	return QueryFields{
+		Values: map[string]string{
+			"id":     r.network.ID,
+			"name":   r.network.Name,
+			"driver": r.network.Driver,
+			"scope":  r.network.Scope,
+		},
+		Labels:  r.network.Labels,
+		Created: r.network.Created,
+	}
+}
+
+// networkQueryFields lists the fields filter queries can match in the
+// network list.
+var networkQueryFields = []string{"id", "name", "driver", "scope", QueryLabel, QuerySince, QueryBefore}
+
+// networkValue renders a network field with a truncating formatter.
+func networkValue(field func(*formatter.NetworkFormatter) string) func(network.Inspect) string {
+	return func(n network.Inspect) string {
+		return field(formatter.NewNetworkFormatter(n, true))
+	}
+}
+
+// networkColumns lists the columns the network list can show.
+var networkColumns = ColumnCatalog[network.Inspect]{
+	{Key: "id", Column: Column{Title: "ID", Width: IDColumnWidth, Fixed: true},
+		Value: networkValue((*formatter.NetworkFormatter).ID)},
+	{Key: "name", Column: Column{Title: "NAME"},
+		Value: networkValue((*formatter.NetworkFormatter).Name)},
+	{Key: "driver", Column: Column{Title: "DRIVER", Width: 12, Fixed: true},
+		Value: networkValue((*formatter.NetworkFormatter).Driver)},
+	{Key: "containers", Column: Column{Title: "CONTAINERS", Width: 12, Fixed: true},
+		Value: networkValue((*formatter.NetworkFormatter).Containers)},
+	{Key: "services", Column: Column{Title: "SERVICES", Width: 10, Fixed: true},
+		Value: networkValue((*formatter.NetworkFormatter).Services)},
+	{Key: "scope", Column: Column{Title: "SCOPE", Width: 8, Fixed: true},
+		Value: networkValue((*formatter.NetworkFormatter).Scope)},
+	{Key: "subnet", Column: Column{Title: "SUBNET"},
+		Value: networkValue((*formatter.NetworkFormatter).Subnet)},
+	{Key: "gateway", Column: Column{Title: "GATEWAY"},
+		Value: networkValue((*formatter.NetworkFormatter).Gateway)},
+}
+
+var defaultNetworkColumns = DefaultColumnChoices("id", "name", "driver", "containers", "scope", "subnet")
+
 // NetworksLoadedMsg carries the loaded networks.
 type NetworksLoadedMsg struct {
 	Networks []network.Inspect
@@ -32,24 +76,23 @@ type NetworksLoadedMsg struct {
 
 // NetworksModel is the networks list view sub-model.
 type NetworksModel struct {
-	table  TableModel
-	filter FilterInputModel
+	table    TableModel
+	filter   FilterInputModel
+	networks []network.Inspect
+	columns  []ColumnChoice
+	format   *formatter.RowFormat
 }
 
 // NewNetworksModel creates a networks list model.
 func NewNetworksModel() NetworksModel {
-	columns := []Column{
-		{Title: "ID", Width: IDColumnWidth, Fixed: true},
-		{Title: "NAME"},
-		{Title: "DRIVER", Width: 12, Fixed: true},
-		{Title: "CONTAINERS", Width: 12, Fixed: true},
-		{Title: "SCOPE", Width: 8, Fixed: true},
-		{Title: "SUBNET"},
-	}
-	return NetworksModel{
-		table:  NewTableModel(columns),
-		filter: NewFilterInputModel(),
+	m := NetworksModel{
+		table:   NewTableModel(networkColumns.Columns(defaultNetworkColumns)),
+		filter:  NewFilterInputModel(),
+		columns: defaultNetworkColumns,
 	}
+	m.table.SetQueryFields(networkQueryFields...)
+	m.table.EnableMarks()
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -67,13 +110,62 @@ func (m *NetworksModel) SetSize(w, h int) {
 
 // SetNetworks replaces the network list.
 func (m *NetworksModel) SetNetworks(networks []network.Inspect) {
+	m.networks = networks
 	rows := make([]TableRow, len(networks))
 	for i, n := range networks {
-		rows[i] = newNetworkRow(n)
+		rows[i] = newNetworkRow(n, m.rowValues(n))
 	}
 	m.table.SetRows(rows)
 }
 
+// ColumnOptions returns the columns the network list can show.
+func (m NetworksModel) ColumnOptions() []ColumnOption {
+	return networkColumns.Options()
+}
+
+// ColumnChoices returns the columns chosen for the network list.
+func (m NetworksModel) ColumnChoices() []ColumnChoice {
+	return m.columns
+}
+
+// SetColumnChoices sets the columns of the network list; none brings back
+// the default set.
+func (m *NetworksModel) SetColumnChoices(choices []ColumnChoice) error {
+	if err := networkColumns.Check(choices); err != nil {
+		return err
+	}
+	if len(choices) == 0 {
+		choices = defaultNetworkColumns
+	}
+	m.columns = choices
+	m.format = nil
+	m.table.SetColumns(m.tableColumns())
+	m.SetNetworks(m.networks)
+	return nil
+}
+
+// SetRowFormat renders the network list with the given row format instead
+// of the chosen columns; nil goes back to the columns.
+func (m *NetworksModel) SetRowFormat(f *formatter.RowFormat) {
+	m.format = f
+	m.table.SetColumns(m.tableColumns())
+	m.SetNetworks(m.networks)
+}
+
+func (m NetworksModel) tableColumns() []Column {
+	if m.format != nil {
+		return formatColumns(m.format)
+	}
+	return networkColumns.Columns(m.columns)
+}
+
+func (m NetworksModel) rowValues(n network.Inspect) []string {
+	if m.format != nil {
+		return m.format.Row(formatter.NewNetworkFormatter(n, true))
+	}
+	return networkColumns.Values(m.columns, n)
+}
+
 // SelectedNetwork returns the network under the cursor, or nil.
 func (m NetworksModel) SelectedNetwork() *network.Inspect {
 	row := m.table.SelectedRow()
@@ -86,12 +178,33 @@ func (m NetworksModel) SelectedNetwork() *network.Inspect {
 	return nil
 }
 
+// MarkedCount returns the number of networks marked.
+func (m NetworksModel) MarkedCount() int {
+	return m.table.MarkedCount()
+}
+
+// ClearMarks unmarks every network.
+func (m *NetworksModel) ClearMarks() {
+	m.table.ClearMarks()
+}
+
+// MarkedNetworks returns the marked networks, in list order.
+func (m NetworksModel) MarkedNetworks() []network.Inspect {
+	var marked []network.Inspect
+	for _, row := range m.table.MarkedRows() {
+		if r, ok := row.(networkRow); ok {
+			marked = append(marked, r.network)
+		}
+	}
+	return marked
+}
+
 // Update handles network-list-specific key events.
 func (m NetworksModel) Update(msg tea.Msg) (NetworksModel, tea.Cmd) {
 	if m.filter.Active() {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -129,6 +242,27 @@ func (m *NetworksModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
 
+// SortBy sorts the list by the column with the given title.
+func (m *NetworksModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
+
+// SortColumn returns the title of the column the list is sorted by.
+func (m NetworksModel) SortColumn() string {
+	return m.table.SortTitle()
+}
+
+// FilterText returns the filter applied to the list.
+func (m NetworksModel) FilterText() string {
+	return m.table.FilterText()
+}
+
+// SetFilterText filters the list as if text had been typed in the filter
+// input.
+func (m *NetworksModel) SetFilterText(text string) error {
+	return m.table.SetFilter(text)
+}
+
 func (m NetworksModel) widgetHeader() string {
 	return RenderWidgetHeader(WidgetHeaderOpts{
 		Icon:     "🔗",
@@ -136,6 +270,7 @@ func (m NetworksModel) widgetHeader() string {
 		Total:    m.table.TotalRowCount(),
 		Filtered: m.table.RowCount(),
 		Filter:   m.table.FilterText(),
+		Marked:   m.table.MarkedCount(),
 		Width:    m.table.Width(),
 		Accent:   DryTheme.Tertiary,
 	})
@@ -1,10 +1,13 @@
 package appui
 
 import (
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/moncho/dry/docker"
 )
 
 // --- PromptModel tests ---
@@ -170,6 +173,182 @@ func TestLessModel_FollowToggle(t *testing.T) {
 	}
 }
 
+// lessType types text into the active input of the less viewer and
+// presses enter.
+func lessType(m LessModel, text string) LessModel {
+	for _, r := range text {
+		m, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	return m
+}
+
+func TestLessModel_SearchRegexAndCase(t *testing.T) {
+	m := NewLessModel()
+	m.SetSize(80, 24)
+	m.SetContent("GET /health 200\nPOST /orders 500\nget /orders 201", "Test")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: '/'})
+	m = lessType(m, "get")
+	if len(m.matches) != 2 {
+		t.Fatalf("expected 2 case insensitive matches, got %d", len(m.matches))
+	}
+	if !strings.Contains(m.statusLine(), "[match 1/2]") {
+		t.Fatalf("expected a match counter, got %q", m.statusLine())
+	}
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	if m.current != 1 {
+		t.Fatalf("expected the second match after n, got %d", m.current)
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'i', Text: "i"})
+	if len(m.matches) != 1 || m.matches[0] != 2 {
+		t.Fatalf("expected one case sensitive match on line 2, got %v", m.matches)
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'R', Text: "R"})
+	m, _ = m.Update(tea.KeyPressMsg{Code: '/'})
+	m = lessType(m, `5\d\d$`)
+	if len(m.matches) != 1 || m.matches[0] != 1 {
+		t.Fatalf("expected the 500 line to match, got %v", m.matches)
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: '/'})
+	m = lessType(m, "(")
+	if m.searchErr == nil || len(m.matches) != 0 {
+		t.Fatal("expected an invalid regular expression to be reported")
+	}
+}
+
+func TestLessModel_InvertedFilter(t *testing.T) {
+	m := NewLessModel()
+	m.SetSize(80, 24)
+	m.SetContent("GET /health 200\nPOST /orders 500\nGET /health 200", "Test")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'F'})
+	m = lessType(m, "!/health")
+	if len(m.filtered) != 1 || m.filtered[0] != "POST /orders 500" {
+		t.Fatalf("expected matching lines to be hidden, got %q", m.filtered)
+	}
+}
+
+func TestLessModel_MatchCountWhileStreaming(t *testing.T) {
+	m := NewLessModel()
+	m.SetSize(80, 24)
+	m.SetContent("error one\nok\n", "Test")
+	m, _ = m.Update(tea.KeyPressMsg{Code: '/'})
+	m = lessType(m, "error")
+
+	m.AppendContent("error two\nok\n")
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	m.AppendContent("err")
+	m.AppendContent("or three\n")
+	if len(m.matches) != 3 {
+		t.Fatalf("expected 3 matches after streaming, got %d", len(m.matches))
+	}
+	if m.current != 1 {
+		t.Fatalf("expected the selected match to stay, got %d", m.current)
+	}
+}
+
+func TestLessModel_WriteBuffer(t *testing.T) {
+	m := NewLessModel()
+	m.SetSize(80, 24)
+	m.SetContent("\x1b[31mERROR\x1b[0m disk full\nok\n", "Test")
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'w', Text: "w"})
+	if m.mode != lessWriting {
+		t.Fatalf("expected lessWriting, got %d", m.mode)
+	}
+	for _, r := range "out.log" {
+		m, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	m, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected cmd from enter")
+	}
+	msg, ok := cmd().(WriteBufferMsg)
+	if !ok {
+		t.Fatalf("expected WriteBufferMsg, got %T", cmd())
+	}
+	if msg.Path != "out.log" || msg.Content != "ERROR disk full\nok\n" {
+		t.Fatalf("expected plain text written to out.log, got %+v", msg)
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: '|', Text: "|"})
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	for _, r := range "cat" {
+		m, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	pipe, ok := cmd().(PipeBufferMsg)
+	if !ok || pipe.Command != "cat" || !strings.Contains(pipe.Content, "\x1b[31m") {
+		t.Fatalf("expected raw content piped to cat, got %+v", pipe)
+	}
+}
+
+func TestParseLogRange(t *testing.T) {
+	base := docker.LogOptions{Tail: 100, Follow: true, Timestamps: true}
+	tests := []struct {
+		in   string
+		want docker.LogOptions
+		err  bool
+	}{
+		{in: "all", want: docker.LogOptions{Follow: true, Timestamps: true}},
+		{in: "500", want: docker.LogOptions{Tail: 500, Follow: true, Timestamps: true}},
+		{in: "tail 50", want: docker.LogOptions{Tail: 50, Follow: true, Timestamps: true}},
+		{in: "15m", want: docker.LogOptions{Since: "15m", Follow: true, Timestamps: true}},
+		{in: "2h..1h", want: docker.LogOptions{Since: "2h", Until: "1h", Follow: true, Timestamps: true}},
+		{in: "2026-10-17T10:00..", want: docker.LogOptions{Since: "2026-10-17T10:00", Follow: true, Timestamps: true}},
+		{in: "..2026-10-17", want: docker.LogOptions{Until: "2026-10-17", Follow: true, Timestamps: true}},
+		{in: "0", err: true},
+		{in: "yesterday", err: true},
+		{in: "..", err: true},
+		{in: "1h..noon", err: true},
+	}
+	for _, tt := range tests {
+		got, err := parseLogRange(tt.in, base)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.in)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: expected %+v, got %+v (%v)", tt.in, tt.want, got, err)
+		}
+	}
+}
+
+func TestLessModel_LogKeysReopenLogs(t *testing.T) {
+	m := NewLessModel()
+	m.SetSize(120, 24)
+	m.SetContent("line\n", "Logs")
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: '2', Text: "2"}); cmd != nil {
+		t.Fatal("expected no log keys without log options")
+	}
+
+	m.SetLogOptions(docker.LogOptions{Tail: 100, Follow: true})
+	m, cmd := m.Update(tea.KeyPressMsg{Code: '2', Text: "2"})
+	msg, ok := cmd().(ReopenLogsMsg)
+	if !ok || msg.Options.Since != "1h" || msg.Options.Tail != 0 || !msg.Options.Follow {
+		t.Fatalf("expected the last hour of logs, got %+v", msg)
+	}
+	m, cmd = m.Update(tea.KeyPressMsg{Code: 't', Text: "t"})
+	if msg = cmd().(ReopenLogsMsg); !msg.Options.Timestamps || msg.Options.Since != "1h" {
+		t.Fatalf("expected timestamps on the same range, got %+v", msg)
+	}
+	if !strings.Contains(m.statusLine(), "[last 1h, timestamps]") {
+		t.Fatalf("expected the range on the status line, got %q", m.statusLine())
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	m = lessType(m, "noon")
+	if m.mode != lessNormal || !strings.Contains(m.statusLine(), "invalid log range") {
+		t.Fatalf("expected an invalid range to be reported, got %q", m.statusLine())
+	}
+}
+
 func TestQuickPeekModel_SpaceCloses(t *testing.T) {
 	m := NewQuickPeekModel()
 	m.SetSize(120, 40)
@@ -503,3 +682,93 @@ func TestCommandPaletteModel_ViewShowsGroupAndFitsNarrowWidth(t *testing.T) {
 		t.Fatal("expected group label in palette view")
 	}
 }
+
+func TestLessModel_StructuredLogs(t *testing.T) {
+	m := NewLessModel()
+	m.SetSize(120, 24)
+	m.SetContent(`{"level":"info","msg":"started","trace_id":"abc"}
+plain line
+level=error msg="query failed" trace_id=def`, "Test")
+
+	want := []string{"INFO  started trace_id=abc", "plain line", `ERROR query failed trace_id=def`}
+	if !reflect.DeepEqual(m.filtered, want) {
+		t.Fatalf("expected records to be rendered, got %q", m.filtered)
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'F'})
+	m = lessType(m, "level>=warn")
+	if len(m.filtered) != 1 || m.filtered[0] != want[2] {
+		t.Fatalf("expected a level filter, got %q", m.filtered)
+	}
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'F'})
+	m = lessType(m, "trace_id=abc")
+	if len(m.filtered) != 1 || m.filtered[0] != want[0] {
+		t.Fatalf("expected a field filter, got %q", m.filtered)
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'F'})
+	m = lessType(m, "trace_id=def")
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'J', Text: "J"})
+	if len(m.filtered) != 1 || m.filtered[0] != `level=error msg="query failed" trace_id=def` {
+		t.Fatalf("expected raw lines matched as text, got %q", m.filtered)
+	}
+	if !strings.Contains(m.statusLine(), "J structured") {
+		t.Errorf("expected the status line to offer structured lines, got %q", m.statusLine())
+	}
+}
+
+func TestLessModel_StderrStreams(t *testing.T) {
+	m := NewLessModel()
+	m.SetSize(80, 24)
+	m.SetContent("starting\n"+StderrLineMark+"warning: low ", "Test")
+	m.AppendContent("memory\nlistening\n")
+
+	want := []string{"starting", "warning: low memory", "listening", ""}
+	if !reflect.DeepEqual(m.filtered, want) {
+		t.Fatalf("expected marks to be removed, got %q", m.filtered)
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'e', Text: "e"})
+	if len(m.filtered) != 3 || slices.Contains(m.filtered, "warning: low memory") {
+		t.Fatalf("expected stdout lines only, got %q", m.filtered)
+	}
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'e', Text: "e"})
+	if len(m.filtered) != 1 || m.filtered[0] != "warning: low memory" {
+		t.Fatalf("expected stderr lines only, got %q", m.filtered)
+	}
+	if !strings.Contains(m.statusLine(), "e streams [stderr]") {
+		t.Errorf("expected the status line to show the stream, got %q", m.statusLine())
+	}
+	m, _ = m.Update(tea.KeyPressMsg{Code: 'e', Text: "e"})
+	if len(m.filtered) != 4 {
+		t.Fatalf("expected both streams, got %q", m.filtered)
+	}
+}
+
+func TestLessModel_LogSourceKeys(t *testing.T) {
+	m := NewLessModel()
+	m.SetSize(120, 24)
+	m.SetContent("api | ready", "Logs")
+	m.SetLogSources([]string{"api", "db"})
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: '+', Text: "+"})
+	for _, r := range "web" {
+		m, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	m, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if msg, ok := cmd().(AddLogSourceMsg); !ok || msg.Name != "web" {
+		t.Fatalf("expected an AddLogSourceMsg for web, got %#v", msg)
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: '-', Text: "-"})
+	for _, r := range "db" {
+		m, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if msg, ok := cmd().(RemoveLogSourceMsg); !ok || msg.Name != "db" {
+		t.Fatalf("expected a RemoveLogSourceMsg for db, got %#v", msg)
+	}
+	if !strings.Contains(m.statusLine(), "[api, db]") {
+		t.Errorf("expected the status line to list the containers, got %q", m.statusLine())
+	}
+}
@@ -0,0 +1,278 @@
+package appui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Fields with a meaning of their own in filter queries. A view lists them
+// among its query fields when its rows carry labels or a creation time.
+const (
+	QueryLabel  = "label"
+	QuerySince  = "since"
+	QueryBefore = "before"
+)
+
+// QueryFields are the values of a row that filter queries match field
+// terms against.
+type QueryFields struct {
+	// Values maps field names to values.
+	Values map[string]string
+	// Labels are matched by label terms.
+	Labels map[string]string
+	// Created is matched by since and before terms.
+	Created time.Time
+}
+
+// QueryRow is implemented by rows that can be matched by field terms.
+// Rows that do not only match free text.
+type QueryRow interface {
+	QueryFields() QueryFields
+}
+
+// Query is a parsed filter query: space separated terms that a row must
+// all match. A term is either free text, found in any column of the row,
+// or a field term such as status=exited, image~nginx, health!=healthy,
+// label=com.example.team=payments or since=2h. Field terms compare with
+// = (equals), != (differs), ~ (contains) and !~ (does not contain),
+// ignoring case. Quoted terms, and terms naming a field the view does not
+// have, are free text. A query without any field term is looked up as a
+// whole, spaces included.
+type Query struct {
+	terms []queryTerm
+}
+
+type queryTerm struct {
+	text     string // free text, when field is empty
+	field    string
+	op       string
+	value    string
+	duration time.Duration // since and before
+}
+
+// queryOps lists the operators of field terms, longest first.
+var queryOps = []string{"!=", "!~", "=", "~"}
+
+// ParseQuery parses a filter query. Fields lists the field names the rows
+// of the view expose; a term naming any other field is free text.
+func ParseQuery(s string, fields []string) (Query, error) {
+	if !hasFieldTerm(s, fields) {
+		return textQuery(s), nil
+	}
+	tokens, err := queryTokens(s)
+	if err != nil {
+		return Query{}, err
+	}
+	var q Query
+	for _, tok := range tokens {
+		if tok.quoted {
+			q.terms = append(q.terms, queryTerm{text: strings.ToLower(tok.text)})
+			continue
+		}
+		field, op, value, ok := splitQueryTerm(tok.text)
+		field = strings.ToLower(field)
+		if !ok || !slices.Contains(fields, field) {
+			q.terms = append(q.terms, queryTerm{text: strings.ToLower(tok.text)})
+			continue
+		}
+		if value == "" {
+			return Query{}, fmt.Errorf("missing value after %s%s", field, op)
+		}
+		t := queryTerm{field: field, op: op, value: strings.ToLower(value)}
+		if field == QuerySince || field == QueryBefore {
+			if op != "=" {
+				return Query{}, fmt.Errorf("%s only takes =, as in %s=2h", field, field)
+			}
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return Query{}, fmt.Errorf("invalid duration %q, use values such as 90s, 15m or 2h", value)
+			}
+			t.duration = d
+		}
+		q.terms = append(q.terms, t)
+	}
+	return q, nil
+}
+
+// hasFieldTerm returns true if a word of s is a term on one of fields.
+func hasFieldTerm(s string, fields []string) bool {
+	for _, w := range strings.Fields(s) {
+		field, _, _, ok := splitQueryTerm(w)
+		if ok && slices.Contains(fields, strings.ToLower(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// textQuery returns a query looking up s, without the quotes around it,
+// in any column.
+func textQuery(s string) Query {
+	s = strings.TrimSpace(s)
+	if len(s) > 1 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		s = s[1 : len(s)-1]
+	}
+	if s == "" {
+		return Query{}
+	}
+	return Query{terms: []queryTerm{{text: strings.ToLower(s)}}}
+}
+
+type queryToken struct {
+	text   string
+	quoted bool
+}
+
+// queryTokens splits s on spaces, keeping double quoted text together.
+func queryTokens(s string) ([]queryToken, error) {
+	var tokens []queryToken
+	var cur strings.Builder
+	inQuote, quoted, started := false, false, false
+	flush := func() {
+		if started {
+			tokens = append(tokens, queryToken{text: cur.String(), quoted: quoted})
+		}
+		cur.Reset()
+		inQuote, quoted, started = false, false, false
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			if !started {
+				quoted = true
+			}
+			started = true
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			started = true
+			cur.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("missing closing quote")
+	}
+	flush()
+	return tokens, nil
+}
+
+// splitQueryTerm splits a field term at its first operator. Text whose
+// part before the operator is not a field name is not a field term.
+func splitQueryTerm(s string) (field, op, value string, ok bool) {
+	i := strings.IndexAny(s, "=~!")
+	if i <= 0 {
+		return "", "", "", false
+	}
+	for _, r := range s[:i] {
+		if !unicode.IsLetter(r) && r != '-' && r != '_' {
+			return "", "", "", false
+		}
+	}
+	for _, o := range queryOps {
+		if strings.HasPrefix(s[i:], o) {
+			return s[:i], o, s[i+len(o):], true
+		}
+	}
+	return "", "", "", false
+}
+
+// IsZero returns true if the query matches every row.
+func (q Query) IsZero() bool {
+	return len(q.terms) == 0
+}
+
+// Match returns true if the row matches every term of the query.
+func (q Query) Match(row TableRow) bool {
+	var fields *QueryFields
+	for _, t := range q.terms {
+		if t.field == "" {
+			if !rowContains(row, t.text) {
+				return false
+			}
+			continue
+		}
+		if fields == nil {
+			qr, ok := row.(QueryRow)
+			if !ok {
+				return false
+			}
+			f := qr.QueryFields()
+			fields = &f
+		}
+		if !t.match(*fields) {
+			return false
+		}
+	}
+	return true
+}
+
+func rowContains(row TableRow, text string) bool {
+	for _, col := range row.Columns() {
+		if strings.Contains(strings.ToLower(col), text) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t queryTerm) match(f QueryFields) bool {
+	switch t.field {
+	case QuerySince:
+		return !f.Created.IsZero() && time.Since(f.Created) <= t.duration
+	case QueryBefore:
+		return !f.Created.IsZero() && time.Since(f.Created) > t.duration
+	case QueryLabel:
+		return t.matchLabels(f.Labels)
+	}
+	v, ok := f.Values[t.field]
+	if !ok {
+		return false
+	}
+	return compare(strings.ToLower(v), t.op, t.value)
+}
+
+// matchLabels matches label terms: label=key is true when the row has the
+// label, label=key=value when it has it with that value, and ~ looks for
+// the text in any key=value pair.
+func (t queryTerm) matchLabels(labels map[string]string) bool {
+	found := false
+	switch t.op {
+	case "=", "!=":
+		key, value, hasValue := strings.Cut(t.value, "=")
+		for k, v := range labels {
+			if strings.EqualFold(k, key) && (!hasValue || strings.EqualFold(v, value)) {
+				found = true
+				break
+			}
+		}
+	case "~", "!~":
+		for k, v := range labels {
+			if strings.Contains(strings.ToLower(k+"="+v), t.value) {
+				found = true
+				break
+			}
+		}
+	}
+	if strings.HasPrefix(t.op, "!") {
+		return !found
+	}
+	return found
+}
+
+func compare(v, op, value string) bool {
+	switch op {
+	case "=":
+		return v == value
+	case "!=":
+		return v != value
+	case "~":
+		return strings.Contains(v, value)
+	case "!~":
+		return !strings.Contains(v, value)
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package appui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type queryTestRow struct {
+	testRow
+	fields QueryFields
+}
+
+func (r queryTestRow) QueryFields() QueryFields { return r.fields }
+
+var queryTestFields = []string{"name", "status", "image", QueryLabel, QuerySince, QueryBefore}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"status=", "missing value after status="},
+		{"since~2h", "since only takes ="},
+		{"since=yesterday", `invalid duration "yesterday"`},
+		{"before=-2h", `invalid duration "-2h"`},
+		{`status=exited "web`, "missing closing quote"},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query, queryTestFields)
+		if err == nil {
+			t.Errorf("ParseQuery(%q): expected an error", tt.query)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseQuery(%q) error = %q, want it to contain %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	row := queryTestRow{
+		testRow: testRow{id: "1", cols: []string{"web-1", "Exited (0) 2 hours ago"}},
+		fields: QueryFields{
+			Values: map[string]string{
+				"name":   "web-1",
+				"status": "exited",
+				"image":  "nginx:1.27",
+			},
+			Labels:  map[string]string{"com.example.team": "payments"},
+			Created: time.Now().Add(-time.Hour),
+		},
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"web", true},
+		{"WEB", true},
+		{"db", false},
+		{"status=exited", true},
+		{"status=Exited", true},
+		{"status=running", false},
+		{"status!=running", true},
+		{"image~nginx", true},
+		{"image!~nginx", false},
+		{"label=com.example.team", true},
+		{"label=com.example.team=payments", true},
+		{"label=com.example.team=search", false},
+		{"label!=com.example.team=search", true},
+		{"label~team=pay", true},
+		{"since=2h", true},
+		{"since=30m", false},
+		{"before=30m", true},
+		{"before=2h", false},
+		{"status=exited image~nginx since=2h", true},
+		{"status=exited image~redis", false},
+		{`"2 hours"`, true},
+		{`"status=exited"`, false},
+		{"a=b=c", false},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query, append(queryTestFields, "a"))
+		if err != nil {
+			t.Fatalf("ParseQuery(%q): %v", tt.query, err)
+		}
+		if got := q.Match(row); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQuery_FieldTermsNeedQueryRow(t *testing.T) {
+	q, err := ParseQuery("name=A", []string{"name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Match(testRow{id: "a", cols: []string{"A"}}) {
+		t.Error("expected a field term not to match a row without query fields")
+	}
+}
+
+func TestQuery_UnknownFieldsAreFreeText(t *testing.T) {
+	row := testRow{id: "a", cols: []string{"web-1", "foo=bar key:value"}}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"foo=bar", true},
+		{"FOO=bar key:value", true},
+		{"key:value foo=bar", false},
+		{"foo=bar status=exited", false},
+		{`"web`, false},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query, queryTestFields)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", tt.query, err)
+			continue
+		}
+		if got := q.Match(row); got != tt.want {
+			t.Errorf("ParseQuery(%q).Match = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQuery_TextWithOperatorIsFreeText(t *testing.T) {
+	q, err := ParseQuery("0.0.0.0:8080=80", nil)
+	if err != nil {
+		t.Fatalf("expected text that does not start with a field name to be free text: %v", err)
+	}
+	if !q.Match(testRow{id: "a", cols: []string{"0.0.0.0:8080=80"}}) {
+		t.Error("expected free text to match the column containing it")
+	}
+}
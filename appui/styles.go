@@ -48,6 +48,7 @@ type WidgetHeaderOpts struct {
 	Total    int         // total row count; negative hides counts entirely
 	Filtered int         // visible (filtered) row count; same as Total when no filter
 	Filter   string      // active filter text, empty if none
+	Marked   int         // marked row count, hidden when zero
 	Width    int         // full terminal width for padding
 	Accent   color.Color // icon/filter accent color
 	HeaderBg color.Color // optional header background override
@@ -102,6 +103,13 @@ func RenderWidgetHeader(o WidgetHeaderOpts) string {
 		b.WriteString(filterStyle.Render(o.Filter))
 	}
 
+	if o.Marked > 0 {
+		b.WriteString(bg.Render("  "))
+		b.WriteString(sepStyle.Render("│"))
+		b.WriteString(bg.Render(" "))
+		b.WriteString(filterStyle.Render(fmt.Sprintf("%d marked", o.Marked)))
+	}
+
 	line := b.String()
 	w := ansi.StringWidth(line)
 	if w > o.Width {
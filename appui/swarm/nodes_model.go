@@ -7,6 +7,7 @@ import (
 	"github.com/moby/moby/api/types/swarm"
 	"github.com/moncho/dry/appui"
 	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/docker/formatter"
 )
 
 // nodeRow wraps a swarm node as a TableRow.
@@ -15,24 +16,68 @@ type nodeRow struct {
 	columns []string
 }
 
-func newNodeRow(n swarm.Node) nodeRow {
-	role := string(n.Spec.Role)
-	status := string(n.Status.State)
-	availability := string(n.Spec.Availability)
-	hostname := n.Description.Hostname
-	cpu := fmt.Sprintf("%d", n.Description.Resources.NanoCPUs/1e9)
-	mem := fmt.Sprintf("%d MB", n.Description.Resources.MemoryBytes/(1024*1024))
+func newNodeRow(n swarm.Node, choices []appui.ColumnChoice) nodeRow {
 	return nodeRow{
-		node: n,
-		columns: []string{
-			docker.TruncateID(n.ID), hostname, role, availability, status, cpu, mem,
-		},
+		node:    n,
+		columns: nodeColumns.Values(choices, n),
 	}
 }
 
 func (r nodeRow) Columns() []string { return r.columns }
 func (r nodeRow) ID() string        { return r.node.ID }
 
+// QueryFields returns the fields filter queries match the node by.
+func (r nodeRow) QueryFields() appui.QueryFields {
+	n := r.node
+	return appui.QueryFields{
+		Values: map[string]string{
+			"id":           n.ID,
+			"hostname":     n.Description.Hostname,
+			"role":         string(n.Spec.Role),
+			"availability": string(n.Spec.Availability),
+			"status":       string(n.Status.State),
+		},
+		Labels:  n.Spec.Labels,
+		Created: n.CreatedAt,
+	}
+}
+
+// nodeQueryFields lists the fields filter queries can match in the node
+// list.
+var nodeQueryFields = []string{
+	"id", "hostname", "role", "availability", "status",
+	appui.QueryLabel, appui.QuerySince, appui.QueryBefore,
+}
+
+// nodeColumns lists the columns the node list can show.
+var nodeColumns = appui.ColumnCatalog[swarm.Node]{
+	{Key: "id", Column: appui.Column{Title: "ID", Width: appui.IDColumnWidth, Fixed: true},
+		Value: func(n swarm.Node) string { return docker.TruncateID(n.ID) }},
+	{Key: "hostname", Column: appui.Column{Title: "HOSTNAME"},
+		Value: func(n swarm.Node) string { return n.Description.Hostname }},
+	{Key: "role", Column: appui.Column{Title: "ROLE", Width: 10, Fixed: true},
+		Value: func(n swarm.Node) string { return string(n.Spec.Role) }},
+	{Key: "availability", Column: appui.Column{Title: "AVAILABILITY", Width: 14, Fixed: true},
+		Value: func(n swarm.Node) string { return string(n.Spec.Availability) }},
+	{Key: "status", Column: appui.Column{Title: "STATUS", Width: 10, Fixed: true},
+		Value: func(n swarm.Node) string { return string(n.Status.State) }},
+	{Key: "cpu", Column: appui.Column{Title: "CPU", Width: 6, Fixed: true},
+		Value: func(n swarm.Node) string { return fmt.Sprintf("%d", n.Description.Resources.NanoCPUs/1e9) }},
+	{Key: "memory", Column: appui.Column{Title: "MEMORY", Width: 10, Fixed: true},
+		Value: func(n swarm.Node) string {
+			return fmt.Sprintf("%d MB", n.Description.Resources.MemoryBytes/(1024*1024))
+		}},
+	{Key: "address", Column: appui.Column{Title: "ADDRESS", Width: 16, Fixed: true},
+		Value: func(n swarm.Node) string { return n.Status.Addr }},
+	{Key: "engine", Column: appui.Column{Title: "ENGINE", Width: 10, Fixed: true},
+		Value: func(n swarm.Node) string { return n.Description.Engine.EngineVersion }},
+	{Key: "labels", Column: appui.Column{Title: "LABELS"},
+		Value: func(n swarm.Node) string { return formatter.FormatLabels(n.Spec.Labels) }},
+}
+
+var defaultNodeColumns = appui.DefaultColumnChoices(
+	"id", "hostname", "role", "availability", "status", "cpu", "memory")
+
 // NodesLoadedMsg carries the loaded nodes.
 type NodesLoadedMsg struct {
 	Nodes []swarm.Node
@@ -40,25 +85,21 @@ type NodesLoadedMsg struct {
 
 // NodesModel is the swarm nodes list view.
 type NodesModel struct {
-	table  appui.TableModel
-	filter appui.FilterInputModel
+	table   appui.TableModel
+	filter  appui.FilterInputModel
+	nodes   []swarm.Node
+	columns []appui.ColumnChoice
 }
 
 // NewNodesModel creates a nodes list model.
 func NewNodesModel() NodesModel {
-	columns := []appui.Column{
-		{Title: "ID", Width: appui.IDColumnWidth, Fixed: true},
-		{Title: "HOSTNAME"},
-		{Title: "ROLE", Width: 10, Fixed: true},
-		{Title: "AVAILABILITY", Width: 14, Fixed: true},
-		{Title: "STATUS", Width: 10, Fixed: true},
-		{Title: "CPU", Width: 6, Fixed: true},
-		{Title: "MEMORY", Width: 10, Fixed: true},
-	}
-	return NodesModel{
-		table:  appui.NewTableModel(columns),
-		filter: appui.NewFilterInputModel(),
+	m := NodesModel{
+		table:   appui.NewTableModel(nodeColumns.Columns(defaultNodeColumns)),
+		filter:  appui.NewFilterInputModel(),
+		columns: defaultNodeColumns,
 	}
+	m.table.SetQueryFields(nodeQueryFields...)
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -76,13 +117,39 @@ func (m *NodesModel) SetSize(w, h int) {
 
 // SetNodes replaces the node list.
 func (m *NodesModel) SetNodes(nodes []swarm.Node) {
+	m.nodes = nodes
 	rows := make([]appui.TableRow, len(nodes))
 	for i, n := range nodes {
-		rows[i] = newNodeRow(n)
+		rows[i] = newNodeRow(n, m.columns)
 	}
 	m.table.SetRows(rows)
 }
 
+// ColumnOptions returns the columns the node list can show.
+func (m NodesModel) ColumnOptions() []appui.ColumnOption {
+	return nodeColumns.Options()
+}
+
+// ColumnChoices returns the columns chosen for the node list.
+func (m NodesModel) ColumnChoices() []appui.ColumnChoice {
+	return m.columns
+}
+
+// SetColumnChoices sets the columns of the node list; none brings back
+// the default set.
+func (m *NodesModel) SetColumnChoices(choices []appui.ColumnChoice) error {
+	if err := nodeColumns.Check(choices); err != nil {
+		return err
+	}
+	if len(choices) == 0 {
+		choices = defaultNodeColumns
+	}
+	m.columns = choices
+	m.table.SetColumns(nodeColumns.Columns(choices))
+	m.SetNodes(m.nodes)
+	return nil
+}
+
 // SelectedNode returns the node under the cursor, or nil.
 func (m NodesModel) SelectedNode() *swarm.Node {
 	row := m.table.SelectedRow()
@@ -100,7 +167,7 @@ func (m NodesModel) Update(msg tea.Msg) (NodesModel, tea.Cmd) {
 	if m.filter.Active() {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -138,6 +205,27 @@ func (m *NodesModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
 
+// SortBy sorts the list by the column with the given title.
+func (m *NodesModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
+
+// SortColumn returns the title of the column the list is sorted by.
+func (m NodesModel) SortColumn() string {
+	return m.table.SortTitle()
+}
+
+// FilterText returns the filter applied to the list.
+func (m NodesModel) FilterText() string {
+	return m.table.FilterText()
+}
+
+// SetFilterText filters the list as if text had been typed in the filter
+// input.
+func (m *NodesModel) SetFilterText(text string) error {
+	return m.table.SetFilter(text)
+}
+
 func (m NodesModel) widgetHeader() string {
 	return appui.RenderWidgetHeader(appui.WidgetHeaderOpts{
 		Icon:     "🖥️",
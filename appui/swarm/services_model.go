@@ -7,6 +7,7 @@ import (
 	"github.com/moby/moby/api/types/swarm"
 	"github.com/moncho/dry/appui"
 	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/docker/formatter"
 )
 
 // serviceRow wraps a swarm service as a TableRow.
@@ -15,30 +16,79 @@ type serviceRow struct {
 	columns []string
 }
 
-func newServiceRow(s swarm.Service) serviceRow {
-	var replicas string
-	if s.Spec.Mode.Replicated != nil && s.Spec.Mode.Replicated.Replicas != nil {
-		replicas = fmt.Sprintf("%d", *s.Spec.Mode.Replicated.Replicas)
-	} else if s.Spec.Mode.Replicated != nil {
-		replicas = "0"
-	} else {
-		replicas = "global"
-	}
-	var img string
-	if s.Spec.TaskTemplate.ContainerSpec != nil {
-		img = s.Spec.TaskTemplate.ContainerSpec.Image
-	}
+func newServiceRow(s swarm.Service, choices []appui.ColumnChoice) serviceRow {
 	return serviceRow{
 		service: s,
-		columns: []string{
-			docker.TruncateID(s.ID), s.Spec.Name, replicas, img,
-		},
+		columns: serviceColumns.Values(choices, s),
 	}
 }
 
 func (r serviceRow) Columns() []string { return r.columns }
 func (r serviceRow) ID() string        { return r.service.ID }
 
+// QueryFields returns the fields filter queries match the service by.
+func (r serviceRow) QueryFields() appui.QueryFields {
+	s := r.service
+	return appui.QueryFields{
+		Values: map[string]string{
+			"id":    s.ID,
+			"name":  s.Spec.Name,
+			"mode":  serviceMode(s),
+			"image": serviceImage(s),
+		},
+		Labels:  s.Spec.Labels,
+		Created: s.CreatedAt,
+	}
+}
+
+// serviceQueryFields lists the fields filter queries can match in the
+// service list.
+var serviceQueryFields = []string{
+	"id", "name", "mode", "image",
+	appui.QueryLabel, appui.QuerySince, appui.QueryBefore,
+}
+
+func serviceReplicas(s swarm.Service) string {
+	if s.Spec.Mode.Replicated != nil && s.Spec.Mode.Replicated.Replicas != nil {
+		return fmt.Sprintf("%d", *s.Spec.Mode.Replicated.Replicas)
+	} else if s.Spec.Mode.Replicated != nil {
+		return "0"
+	}
+	return "global"
+}
+
+func serviceMode(s swarm.Service) string {
+	if s.Spec.Mode.Global != nil {
+		return "global"
+	}
+	return "replicated"
+}
+
+func serviceImage(s swarm.Service) string {
+	if s.Spec.TaskTemplate.ContainerSpec != nil {
+		return s.Spec.TaskTemplate.ContainerSpec.Image
+	}
+	return ""
+}
+
+// serviceColumns lists the columns the service list can show.
+var serviceColumns = appui.ColumnCatalog[swarm.Service]{
+	{Key: "id", Column: appui.Column{Title: "ID", Width: appui.IDColumnWidth, Fixed: true},
+		Value: func(s swarm.Service) string { return docker.TruncateID(s.ID) }},
+	{Key: "name", Column: appui.Column{Title: "NAME"},
+		Value: func(s swarm.Service) string { return s.Spec.Name }},
+	{Key: "mode", Column: appui.Column{Title: "MODE", Width: 12, Fixed: true},
+		Value: serviceMode},
+	{Key: "replicas", Column: appui.Column{Title: "REPLICAS", Width: 10, Fixed: true},
+		Value: serviceReplicas},
+	{Key: "image", Column: appui.Column{Title: "IMAGE"},
+		Value: serviceImage},
+	{Key: "ports", Column: appui.Column{Title: "PORTS"},
+		Value: func(s swarm.Service) string { return formatter.FormatPorts(s.Endpoint.Ports) }},
+}
+
+var defaultServiceColumns = appui.DefaultColumnChoices("id", "name", "replicas", "image")
+
 // ServicesLoadedMsg carries the loaded services.
 type ServicesLoadedMsg struct {
 	Services []swarm.Service
@@ -46,22 +96,22 @@ type ServicesLoadedMsg struct {
 
 // ServicesModel is the swarm services list view.
 type ServicesModel struct {
-	table  appui.TableModel
-	filter appui.FilterInputModel
+	table    appui.TableModel
+	filter   appui.FilterInputModel
+	services []swarm.Service
+	columns  []appui.ColumnChoice
 }
 
 // NewServicesModel creates a services list model.
 func NewServicesModel() ServicesModel {
-	columns := []appui.Column{
-		{Title: "ID", Width: appui.IDColumnWidth, Fixed: true},
-		{Title: "NAME"},
-		{Title: "REPLICAS", Width: 10, Fixed: true},
-		{Title: "IMAGE"},
-	}
-	return ServicesModel{
-		table:  appui.NewTableModel(columns),
-		filter: appui.NewFilterInputModel(),
+	m := ServicesModel{
+		table:   appui.NewTableModel(serviceColumns.Columns(defaultServiceColumns)),
+		filter:  appui.NewFilterInputModel(),
+		columns: defaultServiceColumns,
 	}
+	m.table.SetQueryFields(serviceQueryFields...)
+	m.table.EnableMarks()
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -79,13 +129,39 @@ func (m *ServicesModel) SetSize(w, h int) {
 
 // SetServices replaces the service list.
 func (m *ServicesModel) SetServices(services []swarm.Service) {
+	m.services = services
 	rows := make([]appui.TableRow, len(services))
 	for i, s := range services {
-		rows[i] = newServiceRow(s)
+		rows[i] = newServiceRow(s, m.columns)
 	}
 	m.table.SetRows(rows)
 }
 
+// ColumnOptions returns the columns the service list can show.
+func (m ServicesModel) ColumnOptions() []appui.ColumnOption {
+	return serviceColumns.Options()
+}
+
+// ColumnChoices returns the columns chosen for the service list.
+func (m ServicesModel) ColumnChoices() []appui.ColumnChoice {
+	return m.columns
+}
+
+// SetColumnChoices sets the columns of the service list; none brings back
+// the default set.
+func (m *ServicesModel) SetColumnChoices(choices []appui.ColumnChoice) error {
+	if err := serviceColumns.Check(choices); err != nil {
+		return err
+	}
+	if len(choices) == 0 {
+		choices = defaultServiceColumns
+	}
+	m.columns = choices
+	m.table.SetColumns(serviceColumns.Columns(choices))
+	m.SetServices(m.services)
+	return nil
+}
+
 // SelectedService returns the service under the cursor, or nil.
 func (m ServicesModel) SelectedService() *swarm.Service {
 	row := m.table.SelectedRow()
@@ -98,12 +174,33 @@ func (m ServicesModel) SelectedService() *swarm.Service {
 	return nil
 }
 
+// MarkedCount returns the number of services marked.
+func (m ServicesModel) MarkedCount() int {
+	return m.table.MarkedCount()
+}
+
+// ClearMarks unmarks every service.
+func (m *ServicesModel) ClearMarks() {
+	m.table.ClearMarks()
+}
+
+// MarkedServices returns the marked services, in list order.
+func (m ServicesModel) MarkedServices() []swarm.Service {
+	var marked []swarm.Service
+	for _, row := range m.table.MarkedRows() {
+		if r, ok := row.(serviceRow); ok {
+			marked = append(marked, r.service)
+		}
+	}
+	return marked
+}
+
 // Update handles key events.
 func (m ServicesModel) Update(msg tea.Msg) (ServicesModel, tea.Cmd) {
 	if m.filter.Active() {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -141,6 +238,27 @@ func (m *ServicesModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
 
+// SortBy sorts the list by the column with the given title.
+func (m *ServicesModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
+
+// SortColumn returns the title of the column the list is sorted by.
+func (m ServicesModel) SortColumn() string {
+	return m.table.SortTitle()
+}
+
+// FilterText returns the filter applied to the list.
+func (m ServicesModel) FilterText() string {
+	return m.table.FilterText()
+}
+
+// SetFilterText filters the list as if text had been typed in the filter
+// input.
+func (m *ServicesModel) SetFilterText(text string) error {
+	return m.table.SetFilter(text)
+}
+
 func (m ServicesModel) widgetHeader() string {
 	return appui.RenderWidgetHeader(appui.WidgetHeaderOpts{
 		Icon:     "⚙",
@@ -148,6 +266,7 @@ func (m ServicesModel) widgetHeader() string {
 		Total:    m.table.TotalRowCount(),
 		Filtered: m.table.RowCount(),
 		Filter:   m.table.FilterText(),
+		Marked:   m.table.MarkedCount(),
 		Width:    m.table.Width(),
 		Accent:   appui.DryTheme.Primary,
 	})
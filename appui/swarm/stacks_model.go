@@ -14,22 +14,48 @@ type stackRow struct {
 	columns []string
 }
 
-func newStackRow(s docker.Stack) stackRow {
+func newStackRow(s docker.Stack, choices []appui.ColumnChoice) stackRow {
 	return stackRow{
-		stack: s,
-		columns: []string{
-			s.Name,
-			fmt.Sprintf("%d", s.Services),
-			fmt.Sprintf("%d", s.Networks),
-			fmt.Sprintf("%d", s.Configs),
-			fmt.Sprintf("%d", s.Secrets),
-		},
+		stack:   s,
+		columns: stackColumns.Values(choices, s),
 	}
 }
 
 func (r stackRow) Columns() []string { return r.columns }
 func (r stackRow) ID() string        { return r.stack.Name }
 
+// QueryFields returns the fields filter queries match the stack by.
+func (r stackRow) QueryFields() appui.QueryFields {
+	return appui.QueryFields{
+		Values: map[string]string{
+			"name":         r.stack.Name,
+			"orchestrator": r.stack.Orchestrator,
+		},
+	}
+}
+
+// stackQueryFields lists the fields filter queries can match in the stack
+// list.
+var stackQueryFields = []string{"name", "orchestrator"}
+
+// stackColumns lists the columns the stack list can show.
+var stackColumns = appui.ColumnCatalog[docker.Stack]{
+	{Key: "name", Column: appui.Column{Title: "NAME"},
+		Value: func(s docker.Stack) string { return s.Name }},
+	{Key: "services", Column: appui.Column{Title: "SERVICES", Width: 10, Fixed: true},
+		Value: func(s docker.Stack) string { return fmt.Sprintf("%d", s.Services) }},
+	{Key: "networks", Column: appui.Column{Title: "NETWORKS", Width: 10, Fixed: true},
+		Value: func(s docker.Stack) string { return fmt.Sprintf("%d", s.Networks) }},
+	{Key: "configs", Column: appui.Column{Title: "CONFIGS", Width: 10, Fixed: true},
+		Value: func(s docker.Stack) string { return fmt.Sprintf("%d", s.Configs) }},
+	{Key: "secrets", Column: appui.Column{Title: "SECRETS", Width: 10, Fixed: true},
+		Value: func(s docker.Stack) string { return fmt.Sprintf("%d", s.Secrets) }},
+	{Key: "orchestrator", Column: appui.Column{Title: "ORCHESTRATOR", Width: 14, Fixed: true},
+		Value: func(s docker.Stack) string { return s.Orchestrator }},
+}
+
+var defaultStackColumns = appui.DefaultColumnChoices("name", "services", "networks", "configs", "secrets")
+
 // StacksLoadedMsg carries the loaded stacks.
 type StacksLoadedMsg struct {
 	Stacks []docker.Stack
@@ -37,23 +63,21 @@ type StacksLoadedMsg struct {
 
 // StacksModel is the swarm stacks list view.
 type StacksModel struct {
-	table  appui.TableModel
-	filter appui.FilterInputModel
+	table   appui.TableModel
+	filter  appui.FilterInputModel
+	stacks  []docker.Stack
+	columns []appui.ColumnChoice
 }
 
 // NewStacksModel creates a stacks list model.
 func NewStacksModel() StacksModel {
-	columns := []appui.Column{
-		{Title: "NAME"},
-		{Title: "SERVICES", Width: 10, Fixed: true},
-		{Title: "NETWORKS", Width: 10, Fixed: true},
-		{Title: "CONFIGS", Width: 10, Fixed: true},
-		{Title: "SECRETS", Width: 10, Fixed: true},
-	}
-	return StacksModel{
-		table:  appui.NewTableModel(columns),
-		filter: appui.NewFilterInputModel(),
+	m := StacksModel{
+		table:   appui.NewTableModel(stackColumns.Columns(defaultStackColumns)),
+		filter:  appui.NewFilterInputModel(),
+		columns: defaultStackColumns,
 	}
+	m.table.SetQueryFields(stackQueryFields...)
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -71,13 +95,39 @@ func (m *StacksModel) SetSize(w, h int) {
 
 // SetStacks replaces the stack list.
 func (m *StacksModel) SetStacks(stacks []docker.Stack) {
+	m.stacks = stacks
 	rows := make([]appui.TableRow, len(stacks))
 	for i, s := range stacks {
-		rows[i] = newStackRow(s)
+		rows[i] = newStackRow(s, m.columns)
 	}
 	m.table.SetRows(rows)
 }
 
+// ColumnOptions returns the columns the stack list can show.
+func (m StacksModel) ColumnOptions() []appui.ColumnOption {
+	return stackColumns.Options()
+}
+
+// ColumnChoices returns the columns chosen for the stack list.
+func (m StacksModel) ColumnChoices() []appui.ColumnChoice {
+	return m.columns
+}
+
+// SetColumnChoices sets the columns of the stack list; none brings back
+// the default set.
+func (m *StacksModel) SetColumnChoices(choices []appui.ColumnChoice) error {
+	if err := stackColumns.Check(choices); err != nil {
+		return err
+	}
+	if len(choices) == 0 {
+		choices = defaultStackColumns
+	}
+	m.columns = choices
+	m.table.SetColumns(stackColumns.Columns(choices))
+	m.SetStacks(m.stacks)
+	return nil
+}
+
 // SelectedStack returns the stack under the cursor, or nil.
 func (m StacksModel) SelectedStack() *docker.Stack {
 	row := m.table.SelectedRow()
@@ -95,7 +145,7 @@ func (m StacksModel) Update(msg tea.Msg) (StacksModel, tea.Cmd) {
 	if m.filter.Active() {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -133,6 +183,27 @@ func (m *StacksModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
 
+// SortBy sorts the list by the column with the given title.
+func (m *StacksModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
+
+// SortColumn returns the title of the column the list is sorted by.
+func (m StacksModel) SortColumn() string {
+	return m.table.SortTitle()
+}
+
+// FilterText returns the filter applied to the list.
+func (m StacksModel) FilterText() string {
+	return m.table.FilterText()
+}
+
+// SetFilterText filters the list as if text had been typed in the filter
+// input.
+func (m *StacksModel) SetFilterText(text string) error {
+	return m.table.SetFilter(text)
+}
+
 func (m StacksModel) widgetHeader() string {
 	return appui.RenderWidgetHeader(appui.WidgetHeaderOpts{
 		Icon:     "📚",
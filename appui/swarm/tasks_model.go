@@ -37,6 +37,33 @@ func newTaskRow(api docker.SwarmAPI, t swarm.Task) taskRow {
 func (r taskRow) Columns() []string { return r.columns }
 func (r taskRow) ID() string        { return r.task.ID }
 
+// QueryFields returns the fields filter queries match the task by.
+func (r taskRow) QueryFields() appui.QueryFields {
+	t := r.task
+	var image string
+	if t.Spec.ContainerSpec != nil {
+		image = t.Spec.ContainerSpec.Image
+	}
+	return appui.QueryFields{
+		Values: map[string]string{
+			"id":      t.ID,
+			"service": t.ServiceID,
+			"node":    t.NodeID,
+			"image":   image,
+			"state":   string(t.Status.State),
+			"desired": string(t.DesiredState),
+		},
+		Labels:  t.Labels,
+		Created: t.CreatedAt,
+	}
+}
+
+// taskQueryFields lists the fields filter queries can match in task lists.
+var taskQueryFields = []string{
+	"id", "service", "node", "image", "state", "desired",
+	appui.QueryLabel, appui.QuerySince, appui.QueryBefore,
+}
+
 // TasksLoadedMsg carries the loaded tasks.
 type TasksLoadedMsg struct {
 	Tasks []swarm.Task
@@ -62,11 +89,13 @@ func NewTasksModel() TasksModel {
 		{Title: "CURRENT", Width: 10, Fixed: true},
 		{Title: "ERROR"},
 	}
-	return TasksModel{
+	m := TasksModel{
 		table:  appui.NewTableModel(columns),
 		filter: appui.NewFilterInputModel(),
 		title:  "Tasks",
 	}
+	m.table.SetQueryFields(taskQueryFields...)
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -114,7 +143,7 @@ func (m TasksModel) Update(msg tea.Msg) (TasksModel, tea.Cmd) {
 	if m.filter.Active() {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -141,6 +170,11 @@ func (m *TasksModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
 
+// SortBy sorts the list by the column with the given title.
+func (m *TasksModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
+
 // View renders the tasks list.
 func (m TasksModel) View() string {
 	header := appui.RenderWidgetHeader(appui.WidgetHeaderOpts{
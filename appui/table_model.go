@@ -33,17 +33,22 @@ type TableRow interface {
 // It wraps bubbles/table for rendering and keyboard navigation while keeping
 // sort, filter, and column-width logic locally.
 type TableModel struct {
-	inner      table.Model
-	columns    []Column
-	rows       []TableRow
-	filtered   []TableRow
-	sortField  int
-	sortAsc    bool
-	filterText string
-	filterFn   func(row TableRow, pattern string) bool
-	colWidths  []int
-	width      int
-	height     int
+	inner       table.Model
+	columns     []Column
+	rows        []TableRow
+	filtered    []TableRow
+	sortField   int
+	sortAsc     bool
+	filterText  string
+	query       Query
+	queryFields []string
+	keepSorted  bool            // re-sort on SetRows, set once a sort column is chosen by name
+	markable    bool            // v, V and * mark rows, see EnableMarks
+	marked      map[string]bool // IDs of the marked rows
+	anchor      string          // ID of the row last marked with v, where V ranges start
+	colWidths   []int
+	width       int
+	height      int
 }
 
 // NewTableModel creates a table with the given column definitions.
@@ -68,30 +73,54 @@ func NewTableModel(columns []Column) TableModel {
 	})
 
 	return TableModel{
-		inner:    t,
-		columns:  columns,
-		sortAsc:  true,
-		filterFn: defaultFilter,
+		inner:   t,
+		columns: columns,
+		sortAsc: true,
 	}
 }
 
-func defaultFilter(row TableRow, pattern string) bool {
-	lower := strings.ToLower(pattern)
-	for _, col := range row.Columns() {
-		if strings.Contains(strings.ToLower(col), lower) {
-			return true
-		}
-	}
-	return false
-}
-
-// SetRows replaces all rows and reapplies the filter.
+// SetRows replaces all rows and reapplies the filter. Marks of rows that
+// are gone are dropped.
 func (m *TableModel) SetRows(rows []TableRow) {
 	m.rows = rows
+	m.pruneMarks()
+	if m.keepSorted {
+		m.sortRows()
+		return
+	}
 	m.applyFilter()
 	m.syncInner()
 }
 
+// SetColumns replaces the column definitions, keeping the filter and the
+// cursor. Rows have to be set again afterwards, built for the new columns.
+// The sort indicator follows its column by title; when that column is
+// gone, rows stop being kept sorted.
+func (m *TableModel) SetColumns(columns []Column) {
+	sortTitle := ""
+	if m.sortField >= 0 && m.sortField < len(m.columns) {
+		sortTitle = m.columns[m.sortField].Title
+	}
+	m.columns = columns
+	m.sortField = -1
+	for i, c := range columns {
+		if sortTitle != "" && c.Title == sortTitle {
+			m.sortField = i
+			break
+		}
+	}
+	if m.sortField < 0 {
+		m.keepSorted = false
+	}
+	// Drop the rows first, bubbles' table renders them against the new
+	// columns as soon as those are set.
+	m.rows = nil
+	m.filtered = nil
+	m.inner.SetRows(nil)
+	m.calculateColumnWidths()
+	m.syncInnerColumns()
+}
+
 // SetSize updates the table dimensions. Table height is reduced
 // by 1 to leave space for the blank line after the table.
 func (m *TableModel) SetSize(w, h int) {
@@ -141,11 +170,29 @@ func (m TableModel) FilterText() string {
 	return m.filterText
 }
 
-// SetFilter sets the filter text and reapplies it.
-func (m *TableModel) SetFilter(pattern string) {
+// SetQueryFields sets the fields filter queries can match, see Query.
+func (m *TableModel) SetQueryFields(fields ...string) {
+	m.queryFields = fields
+}
+
+// QueryFields returns the fields filter queries can match.
+func (m TableModel) QueryFields() []string {
+	return m.queryFields
+}
+
+// SetFilter parses the filter text as a query and reapplies it. A text
+// that does not parse leaves the previous filter in place and the error
+// is returned.
+func (m *TableModel) SetFilter(pattern string) error {
+	q, err := ParseQuery(pattern, m.queryFields)
+	if err != nil {
+		return err
+	}
 	m.filterText = pattern
+	m.query = q
 	m.applyFilter()
 	m.syncInner()
+	return nil
 }
 
 // NextSort cycles to the next sort field and re-sorts the rows.
@@ -167,18 +214,171 @@ func (m *TableModel) SetSortField(col int) {
 	m.syncInnerColumns()
 }
 
+// SortBy sorts by the column with the given title, ignoring case, and
+// keeps rows set later sorted the same way. It returns false if no column
+// has that title.
+func (m *TableModel) SortBy(title string) bool {
+	for i, c := range m.columns {
+		if c.Title != "" && strings.EqualFold(c.Title, title) {
+			m.sortField = i
+			m.keepSorted = true
+			m.sortRows()
+			m.syncInnerColumns()
+			return true
+		}
+	}
+	return false
+}
+
 // SortField returns the current sort field index.
 func (m TableModel) SortField() int {
 	return m.sortField
 }
 
+// SortTitle returns the title of the sort column, empty when the rows are
+// not sorted by a titled column.
+func (m TableModel) SortTitle() string {
+	if m.sortField < 0 || m.sortField >= len(m.columns) {
+		return ""
+	}
+	return m.columns[m.sortField].Title
+}
+
 // Sort re-sorts the current rows using the active sort field.
 func (m *TableModel) Sort() {
 	m.sortRows()
 }
 
-// Update handles keyboard navigation via the inner bubbles table.
+// EnableMarks lets rows be marked from the keyboard: v marks or unmarks
+// the row under the cursor, V marks the rows from the last one marked with
+// v to the cursor and * marks every row the filter shows, or unmarks them
+// if they all are.
+func (m *TableModel) EnableMarks() {
+	m.markable = true
+}
+
+// ToggleMark marks the row under the cursor, or unmarks it if it is
+// marked, and moves the cursor to the next row.
+func (m *TableModel) ToggleMark() {
+	row := m.SelectedRow()
+	if row == nil {
+		return
+	}
+	if m.marked[row.ID()] {
+		delete(m.marked, row.ID())
+	} else {
+		m.mark(row)
+	}
+	m.anchor = row.ID()
+	m.inner.MoveDown(1)
+	m.syncInner()
+}
+
+// MarkRange marks the rows from the last one marked with ToggleMark to
+// the one under the cursor, or just the latter if the former is not shown.
+func (m *TableModel) MarkRange() {
+	cursor := m.inner.Cursor()
+	if cursor < 0 || cursor >= len(m.filtered) {
+		return
+	}
+	from := cursor
+	for i, r := range m.filtered {
+		if r.ID() == m.anchor {
+			from = i
+		}
+	}
+	for i := min(from, cursor); i <= max(from, cursor); i++ {
+		m.mark(m.filtered[i])
+	}
+	m.syncInner()
+}
+
+// ToggleMarkAll marks every row the filter shows, or unmarks them if they
+// all are marked already.
+func (m *TableModel) ToggleMarkAll() {
+	all := true
+	for _, r := range m.filtered {
+		all = all && m.marked[r.ID()]
+	}
+	for _, r := range m.filtered {
+		if all {
+			delete(m.marked, r.ID())
+		} else {
+			m.mark(r)
+		}
+	}
+	m.syncInner()
+}
+
+func (m *TableModel) mark(row TableRow) {
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	m.marked[row.ID()] = true
+}
+
+// ClearMarks unmarks every row.
+func (m *TableModel) ClearMarks() {
+	if len(m.marked) == 0 {
+		return
+	}
+	m.marked = nil
+	m.syncInner()
+}
+
+// IsMarked returns true if the row with the given ID is marked.
+func (m TableModel) IsMarked(id string) bool {
+	return m.marked[id]
+}
+
+// MarkedCount returns the number of marked rows.
+func (m TableModel) MarkedCount() int {
+	return len(m.marked)
+}
+
+// MarkedRows returns the marked rows, in table order, including those the
+// filter hides.
+func (m TableModel) MarkedRows() []TableRow {
+	var rows []TableRow
+	for _, r := range m.rows {
+		if m.marked[r.ID()] {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+func (m *TableModel) pruneMarks() {
+	if len(m.marked) == 0 {
+		return
+	}
+	present := make(map[string]bool, len(m.rows))
+	for _, r := range m.rows {
+		present[r.ID()] = true
+	}
+	for id := range m.marked {
+		if !present[id] {
+			delete(m.marked, id)
+		}
+	}
+}
+
+// Update handles keyboard navigation via the inner bubbles table, and the
+// keys marking rows once enabled.
 func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyPressMsg); ok && m.markable {
+		switch key.String() {
+		case "v":
+			m.ToggleMark()
+			return m, nil
+		case "V":
+			m.MarkRange()
+			return m, nil
+		case "*":
+			m.ToggleMarkAll()
+			return m, nil
+		}
+	}
 	var cmd tea.Cmd
 	m.inner, cmd = m.inner.Update(msg)
 	return m, cmd
@@ -261,8 +461,13 @@ func (m *TableModel) toBubblesRows() []table.Row {
 	for i, r := range m.filtered {
 		cols := r.Columns()
 		row := make(table.Row, len(m.columns))
+		marked := m.marked[r.ID()]
 		for j := range m.columns {
-			if j < len(cols) {
+			if j < len(cols) && marked {
+				// Marked rows take the accent color throughout, over
+				// any color of their own.
+				row[j] = ColorFg(ansi.Strip(cols[j]), DryTheme.Tertiary)
+			} else if j < len(cols) {
 				// Skip ColorFg wrapping for columns that already contain
 				// ANSI escape sequences (e.g. container status indicator)
 				// to avoid double-coloring.
@@ -312,13 +517,13 @@ func colValue(row TableRow, col int) string {
 }
 
 func (m *TableModel) applyFilter() {
-	if m.filterText == "" {
+	if m.query.IsZero() {
 		m.filtered = m.rows
 		return
 	}
 	m.filtered = nil
 	for _, row := range m.rows {
-		if m.filterFn(row, m.filterText) {
+		if m.query.Match(row) {
 			m.filtered = append(m.filtered, row)
 		}
 	}
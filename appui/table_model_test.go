@@ -149,6 +149,26 @@ func TestTableModel_Filter(t *testing.T) {
 	}
 }
 
+func TestTableModel_InvalidFilterKeepsPrevious(t *testing.T) {
+	table := NewTableModel([]Column{{Title: "Name"}, {Title: "Value"}})
+	table.SetSize(80, 25)
+	table.SetQueryFields("name")
+	table.SetRows([]TableRow{
+		testRow{id: "1", cols: []string{"alpha", "x"}},
+		testRow{id: "2", cols: []string{"beta", "y"}},
+	})
+
+	if err := table.SetFilter("alpha"); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.SetFilter("name="); err == nil {
+		t.Fatal("expected an error for a field term without value")
+	}
+	if table.RowCount() != 1 {
+		t.Fatalf("expected the previous filter to stay, got %d rows", table.RowCount())
+	}
+}
+
 func TestTableModel_NextSort(t *testing.T) {
 	cols := []Column{{Title: "A"}, {Title: "B"}, {Title: "C"}}
 	table := NewTableModel(cols)
@@ -409,3 +429,90 @@ func TestTableModel_ViewNotEmpty(t *testing.T) {
 		t.Fatal("View() should not be empty")
 	}
 }
+
+func TestTableModel_SortByKeepsRowsSorted(t *testing.T) {
+	cols := []Column{{Title: "NAME"}, {Title: "SIZE"}}
+	table := NewTableModel(cols)
+	table.SetSize(80, 25)
+
+	if table.SortBy("missing") {
+		t.Fatal("expected SortBy to fail for an unknown column")
+	}
+	if !table.SortBy("size") {
+		t.Fatal("expected SortBy to match the column title ignoring case")
+	}
+	if table.SortField() != 1 {
+		t.Fatalf("expected sort field 1, got %d", table.SortField())
+	}
+
+	table.SetRows([]TableRow{
+		testRow{id: "a", cols: []string{"a", "30"}},
+		testRow{id: "b", cols: []string{"b", "4"}},
+		testRow{id: "c", cols: []string{"c", "100"}},
+	})
+	var got []string
+	for _, row := range table.FilteredRows() {
+		got = append(got, row.ID())
+	}
+	if strings.Join(got, ",") != "b,a,c" {
+		t.Fatalf("expected rows sorted by size, got %v", got)
+	}
+}
+
+func TestTableModel_SetColumnsKeepsSortByTitle(t *testing.T) {
+	table := NewTableModel([]Column{{Title: "NAME"}, {Title: "SIZE", Width: 6, Fixed: true}})
+	table.SetSize(80, 25)
+	if !table.SortBy("SIZE") {
+		t.Fatal("expected SIZE to be sortable")
+	}
+
+	table.SetColumns([]Column{{Title: "SIZE", Width: 12, Fixed: true}, {Title: "ID"}, {Title: "NAME"}})
+	if table.SortField() != 0 {
+		t.Fatalf("expected sort to follow SIZE to column 0, got %d", table.SortField())
+	}
+	if table.colWidths[0] != 12+DefaultColumnSpacing {
+		t.Fatalf("expected fixed width 12 honoured, got %d", table.colWidths[0])
+	}
+
+	table.SetColumns([]Column{{Title: "ID"}, {Title: "NAME"}})
+	if table.SortField() != -1 {
+		t.Fatalf("expected no sort field once SIZE is gone, got %d", table.SortField())
+	}
+	table.SetRows([]TableRow{testRow{id: "1", cols: []string{"b", "x"}}})
+	if table.RowCount() != 1 {
+		t.Fatalf("expected rows to be set again, got %d", table.RowCount())
+	}
+}
+
+func TestTableModel_Marks(t *testing.T) {
+	table := NewTableModel([]Column{{Title: "Name"}, {Title: "Value"}})
+	table.SetSize(80, 25)
+	table.SetRows(makeRows(4))
+
+	table.ToggleMark()
+	table.ToggleMark()
+	if table.Cursor() != 2 {
+		t.Fatalf("expected marking to move the cursor down, got %d", table.Cursor())
+	}
+	table, _ = table.Update(tea.KeyPressMsg{Code: 'k'})
+	table.ToggleMark()
+	if got := table.MarkedRows(); len(got) != 1 || got[0].ID() != "a" {
+		t.Fatalf("expected row a marked, got %v", got)
+	}
+
+	// Marks of rows gone are dropped, those of rows hidden by the filter
+	// are kept.
+	table.ToggleMark()
+	if err := table.SetFilter("B"); err != nil {
+		t.Fatal(err)
+	}
+	table.SetRows(makeRows(2))
+	if table.MarkedCount() != 1 || !table.IsMarked("a") {
+		t.Fatalf("expected row a to stay marked, got %v", table.MarkedRows())
+	}
+
+	table.ClearMarks()
+	if table.MarkedCount() != 0 {
+		t.Fatalf("expected no marks, got %v", table.MarkedRows())
+	}
+}
@@ -1,8 +1,11 @@
 package appui
 
 import (
+	"time"
+
 	tea "charm.land/bubbletea/v2"
 	"github.com/moby/moby/api/types/volume"
+	"github.com/moncho/dry/docker/formatter"
 )
 
 // volumeRow wraps a Docker volume as a TableRow.
@@ -11,18 +14,53 @@ type volumeRow struct {
 	columns []string
 }
 
-func newVolumeRow(v volume.Volume) volumeRow {
+func newVolumeRow(v volume.Volume, values []string) volumeRow {
 	return volumeRow{
-		volume: v,
-		columns: []string{
-			v.Driver, v.Name, v.Mountpoint,
-		},
+		volume:  v,
+		columns: values,
 	}
 }
 
 func (r volumeRow) Columns() []string { return r.columns }
 func (r volumeRow) ID() string        { return r.volume.Name }
 
+// QueryFields returns the fields filter queries match the volume by.
+func (r volumeRow) QueryFields() QueryFields {
+	created, _ := time.Parse(time.RFC3339, r.volume.CreatedAt)
+	return QueryFields{
+		Values: map[string]string{
+			"name":       r.volume.Name,
+			"driver":     r.volume.Driver,
+			"scope":      r.volume.Scope,
+			"mountpoint": r.volume.Mountpoint,
+		},
+		Labels:  r.volume.Labels,
+		Created: created,
+	}
+}
+
+// volumeQueryFields lists the fields filter queries can match in the
+// volume list.
+var volumeQueryFields = []string{"name", "driver", "scope", "mountpoint", QueryLabel, QuerySince, QueryBefore}
+
+// volumeColumns lists the columns the volume list can show.
+var volumeColumns = ColumnCatalog[volume.Volume]{
+	{Key: "driver", Column: Column{Title: "DRIVER", Width: 16, Fixed: true},
+		Value: func(v volume.Volume) string { return v.Driver }},
+	{Key: "name", Column: Column{Title: "NAME"},
+		Value: func(v volume.Volume) string { return v.Name }},
+	{Key: "mountpoint", Column: Column{Title: "MOUNTPOINT"},
+		Value: func(v volume.Volume) string { return v.Mountpoint }},
+	{Key: "scope", Column: Column{Title: "SCOPE", Width: 8, Fixed: true},
+		Value: func(v volume.Volume) string { return v.Scope }},
+	{Key: "created", Column: Column{Title: "CREATED", Width: 26, Fixed: true},
+		Value: func(v volume.Volume) string { return v.CreatedAt }},
+	{Key: "labels", Column: Column{Title: "LABELS"},
+		Value: func(v volume.Volume) string { return formatter.FormatLabels(v.Labels) }},
+}
+
+var defaultVolumeColumns = DefaultColumnChoices("driver", "name", "mountpoint")
+
 // VolumesLoadedMsg carries the loaded volumes.
 type VolumesLoadedMsg struct {
 	Volumes []volume.Volume
@@ -30,21 +68,23 @@ type VolumesLoadedMsg struct {
 
 // VolumesModel is the volumes list view sub-model.
 type VolumesModel struct {
-	table  TableModel
-	filter FilterInputModel
+	table   TableModel
+	filter  FilterInputModel
+	volumes []volume.Volume
+	columns []ColumnChoice
+	format  *formatter.RowFormat
 }
 
 // NewVolumesModel creates a volumes list model.
 func NewVolumesModel() VolumesModel {
-	columns := []Column{
-		{Title: "DRIVER", Width: 16, Fixed: true},
-		{Title: "NAME"},
-		{Title: "MOUNTPOINT"},
-	}
-	return VolumesModel{
-		table:  NewTableModel(columns),
-		filter: NewFilterInputModel(),
+	m := VolumesModel{
+		table:   NewTableModel(volumeColumns.Columns(defaultVolumeColumns)),
+		filter:  NewFilterInputModel(),
+		columns: defaultVolumeColumns,
 	}
+	m.table.SetQueryFields(volumeQueryFields...)
+	m.table.EnableMarks()
+	return m
 }
 
 // FilterActive returns true when the filter input is active.
@@ -62,13 +102,62 @@ func (m *VolumesModel) SetSize(w, h int) {
 
 // SetVolumes replaces the volume list.
 func (m *VolumesModel) SetVolumes(volumes []volume.Volume) {
+	m.volumes = volumes
 	rows := make([]TableRow, len(volumes))
 	for i, v := range volumes {
-		rows[i] = newVolumeRow(v)
+		rows[i] = newVolumeRow(v, m.rowValues(v))
 	}
 	m.table.SetRows(rows)
 }
 
+// ColumnOptions returns the columns the volume list can show.
+func (m VolumesModel) ColumnOptions() []ColumnOption {
+	return volumeColumns.Options()
+}
+
+// ColumnChoices returns the columns chosen for the volume list.
+func (m VolumesModel) ColumnChoices() []ColumnChoice {
+	return m.columns
+}
+
+// SetColumnChoices sets the columns of the volume list; none brings back
+// the default set.
+func (m *VolumesModel) SetColumnChoices(choices []ColumnChoice) error {
+	if err := volumeColumns.Check(choices); err != nil {
+		return err
+	}
+	if len(choices) == 0 {
+		choices = defaultVolumeColumns
+	}
+	m.columns = choices
+	m.format = nil
+	m.table.SetColumns(m.tableColumns())
+	m.SetVolumes(m.volumes)
+	return nil
+}
+
+// SetRowFormat renders the volume list with the given row format instead
+// of the chosen columns; nil goes back to the columns.
+func (m *VolumesModel) SetRowFormat(f *formatter.RowFormat) {
+	m.format = f
+	m.table.SetColumns(m.tableColumns())
+	m.SetVolumes(m.volumes)
+}
+
+func (m VolumesModel) tableColumns() []Column {
+	if m.format != nil {
+		return formatColumns(m.format)
+	}
+	return volumeColumns.Columns(m.columns)
+}
+
+func (m VolumesModel) rowValues(v volume.Volume) []string {
+	if m.format != nil {
+		return m.format.Row(formatter.NewVolumeFormatter(v))
+	}
+	return volumeColumns.Values(m.columns, v)
+}
+
 // SelectedVolume returns the volume under the cursor, or nil.
 func (m VolumesModel) SelectedVolume() *volume.Volume {
 	row := m.table.SelectedRow()
@@ -81,12 +170,33 @@ func (m VolumesModel) SelectedVolume() *volume.Volume {
 	return nil
 }
 
+// MarkedCount returns the number of volumes marked.
+func (m VolumesModel) MarkedCount() int {
+	return m.table.MarkedCount()
+}
+
+// ClearMarks unmarks every volume.
+func (m *VolumesModel) ClearMarks() {
+	m.table.ClearMarks()
+}
+
+// MarkedVolumes returns the marked volumes, in list order.
+func (m VolumesModel) MarkedVolumes() []volume.Volume {
+	var marked []volume.Volume
+	for _, row := range m.table.MarkedRows() {
+		if r, ok := row.(volumeRow); ok {
+			marked = append(marked, r.volume)
+		}
+	}
+	return marked
+}
+
 // Update handles volume-list-specific key events.
 func (m VolumesModel) Update(msg tea.Msg) (VolumesModel, tea.Cmd) {
 	if m.filter.Active() {
 		var cmd tea.Cmd
 		m.filter, cmd = m.filter.Update(msg)
-		m.table.SetFilter(m.filter.Value())
+		m.filter.SetError(m.table.SetFilter(m.filter.Value()))
 		return m, cmd
 	}
 
@@ -124,6 +234,27 @@ func (m *VolumesModel) RefreshTableStyles() {
 	m.table.RefreshStyles()
 }
 
+// SortBy sorts the list by the column with the given title.
+func (m *VolumesModel) SortBy(column string) bool {
+	return m.table.SortBy(column)
+}
+
+// SortColumn returns the title of the column the list is sorted by.
+func (m VolumesModel) SortColumn() string {
+	return m.table.SortTitle()
+}
+
+// FilterText returns the filter applied to the list.
+func (m VolumesModel) FilterText() string {
+	return m.table.FilterText()
+}
+
+// SetFilterText filters the list as if text had been typed in the filter
+// input.
+func (m *VolumesModel) SetFilterText(text string) error {
+	return m.table.SetFilter(text)
+}
+
 func (m VolumesModel) widgetHeader() string {
 	return RenderWidgetHeader(WidgetHeaderOpts{
 		Icon:     "💾",
@@ -131,6 +262,7 @@ func (m VolumesModel) widgetHeader() string {
 		Total:    m.table.TotalRowCount(),
 		Filtered: m.table.RowCount(),
 		Filter:   m.table.FilterText(),
+		Marked:   m.table.MarkedCount(),
 		Width:    m.table.Width(),
 		Accent:   DryTheme.Warning,
 	})
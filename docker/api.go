@@ -16,9 +16,12 @@ import (
 
 // Container holds a detailed view of a container.
 // Detail holds the full inspect response (State, Config, etc.).
+// Host names the daemon the container was listed from when containers
+// from several daemons are shown together, and is empty otherwise.
 type Container struct {
 	container.Summary
 	Detail container.InspectResponse
+	Host   string
 }
 
 // ComposeAPI defines methods to query Docker Compose project/service information
@@ -43,6 +46,7 @@ type ComposeActionsAPI interface {
 // SystemAPI is the subset of the Docker API for daemon-level information
 // and maintenance.
 type SystemAPI interface {
+	Close() error
 	DiskUsage() (client.DiskUsageResult, error)
 	DockerEnv() Env
 	Events(ctx context.Context) (<-chan events.Message, error)
@@ -54,6 +58,21 @@ type SystemAPI interface {
 	Version() (*client.ServerVersionResult, error)
 }
 
+// LogOptions select the logs of a container to read.
+type LogOptions struct {
+	// Since and Until bound the logs in time, as RFC 3339 or Unix
+	// timestamps, or as durations back from now such as 5m.
+	Since string
+	Until string
+	// Tail is the number of lines read from the end of the logs; 0 reads
+	// them all.
+	Tail int
+	// Follow keeps streaming the logs as the container writes them.
+	Follow bool
+	// Timestamps prefixes every line with the time it was written.
+	Timestamps bool
+}
+
 // ContainerAPI is a subset of the Docker API to manage containers
 type ContainerAPI interface {
 	ContainerByID(id string) *Container
@@ -61,12 +80,27 @@ type ContainerAPI interface {
 	Inspect(id string) (container.InspectResponse, error)
 	IsContainerRunning(id string) bool
 	Kill(id string) error
-	Logs(id string, since string, withTimeStamp bool) (io.ReadCloser, error)
+	Logs(id string, opts LogOptions) (io.ReadCloser, error)
+	PauseContainer(id string) error
 	RemoveAllStoppedContainers() (int, error)
+	RenameContainer(id, name string) error
 	RestartContainer(id string) error
 	Rm(id string) error
 	StartContainer(id string) error
 	StopContainer(id string) error
+	UnpauseContainer(id string) error
+	UpdateContainerResources(id string, opts ResourceOptions) error
+}
+
+// ContainerFilesAPI is the subset of the Docker API to browse the filesystem
+// of containers and copy files from and to it
+type ContainerFilesAPI interface {
+	ContainerChanges(id string) ([]container.FilesystemChange, error)
+	ContainerFileTree(ctx context.Context, id string) (*FileTree, error)
+	CopyFromContainer(ctx context.Context, id, src, dest string, progress func(int64)) error
+	CopyToContainer(ctx context.Context, id, src, dest string, progress func(int64)) error
+	ExportContainer(ctx context.Context, id, path string, progress func(int64)) error
+	ReadContainerFile(id, path string, limit int64) ([]byte, error)
 }
 
 // ContainerRuntime is the subset of the Docker API to query container runtime information
@@ -79,14 +113,18 @@ type ContainerRuntime interface {
 
 // ImageAPI is a subset of the Docker API to manage images
 type ImageAPI interface {
+	CommitContainer(id string, opts CommitOptions) (string, error)
 	History(id string) ([]image.HistoryResponseItem, error)
 	ImageByID(id string) (image.Summary, error)
+	ImageLoad(ctx context.Context, path string, progress func(int64)) ([]string, error)
 	Images() ([]image.Summary, error)
+	ImageSave(ctx context.Context, refs []string, path string, progress func(int64)) error
 	InspectImage(name string) (image.InspectResponse, error)
 	RemoveDanglingImages() (int, error)
 	RemoveUnusedImages() (int, error)
 	Rmi(id string, force bool) ([]image.DeleteResponse, error)
-	RunImage(image image.Summary, command string) error
+	RunImage(image image.Summary, opts RunOptions) (string, error)
+	RunImageAttached(image image.Summary, opts RunOptions) (*AttachSession, error)
 }
 
 // NetworkAPI is a subset of the Docker API to manage networks
@@ -26,6 +26,39 @@ func (daemon *DockerDaemon) AttachInteractive(ctx context.Context, id string, st
 		return fmt.Errorf("container %s is not running", shortContainerID(id))
 	}
 
+	session, err := daemon.attach(ctx, id, inspect.Config != nil && inspect.Config.Tty, detachKeys)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	return session.Stream(stdin, stdout, stderr)
+}
+
+// AttachSession is an attach session opened on a container, streamed to
+// the terminal with Stream.
+type AttachSession struct {
+	ID     string
+	attach client.HijackedResponse
+	tty    bool
+}
+
+// Stream copies stdin to the container and its output to stdout and
+// stderr, blocking until the session ends (detach, process exit, or error).
+func (s *AttachSession) Stream(stdin io.Reader, stdout, stderr io.Writer) error {
+	if s.tty {
+		return streamInteractive(s.attach, stdin, stdout, true)
+	}
+	// Non-TTY: demultiplex stdout/stderr via stdcopy.
+	return streamInteractiveStdcopy(s.attach, stdin, stdout, stderr)
+}
+
+// Close ends the session.
+func (s *AttachSession) Close() {
+	s.attach.Close()
+}
+
+// attach opens an attach session on the container with the given id.
+func (daemon *DockerDaemon) attach(ctx context.Context, id string, tty bool, detachKeys string) (*AttachSession, error) {
 	if detachKeys == "" {
 		detachKeys = defaultDetachKeys
 	}
@@ -39,17 +72,9 @@ func (daemon *DockerDaemon) AttachInteractive(ctx context.Context, id string, st
 		Logs:       false,
 	})
 	if err != nil {
-		return fmt.Errorf("attach %s: %w", shortContainerID(id), err)
+		return nil, fmt.Errorf("attach %s: %w", shortContainerID(id), err)
 	}
-	defer attach.Close()
-
-	tty := inspect.Config != nil && inspect.Config.Tty
-	if tty {
-		return streamInteractive(attach.HijackedResponse, stdin, stdout, true)
-	}
-
-	// Non-TTY: demultiplex stdout/stderr via stdcopy.
-	return streamInteractiveStdcopy(attach.HijackedResponse, stdin, stdout, stderr)
+	return &AttachSession{ID: id, attach: attach.HijackedResponse, tty: tty}, nil
 }
 
 // streamInteractive handles bidirectional stream copy for TTY sessions
@@ -26,6 +26,22 @@ const (
 	STATS
 	// STOP stop command
 	STOP
+	// HEALTH health check command
+	HEALTH
+	// PAUSE pause command
+	PAUSE
+	// UNPAUSE unpause command
+	UNPAUSE
+	// RENAME rename command
+	RENAME
+	// UPDATE update resources command
+	UPDATE
+	// FILES browse files command
+	FILES
+	// COMMIT commit to image command
+	COMMIT
+	// EXPORT export filesystem command
+	EXPORT
 )
 
 // ContainerCommands is the list of container commands
@@ -34,11 +50,19 @@ var ContainerCommands = []CommandDescription{
 	{ATTACH, "Attach"},
 	{EXEC, "Exec command"},
 	{INSPECT, "Inspect container"},
+	{FILES, "Browse files"},
+	{COMMIT, "Commit to image"},
+	{EXPORT, "Export filesystem"},
 	{KILL, "Kill container"},
 	{RM, "Remove container"},
+	{RENAME, "Rename container"},
 	{RESTART, "Restart"},
+	{PAUSE, "Pause"},
+	{UNPAUSE, "Unpause"},
 	{HISTORY, "Show image history"},
 	{STATS, "Stats + Top"},
+	{UPDATE, "Update resources"},
+	{HEALTH, "Health checks"},
 	{STOP, "Stop"},
 }
 
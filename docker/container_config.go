@@ -1,12 +1,44 @@
 package docker
 
 import (
+	"fmt"
+	"net/netip"
+	"strconv"
 	"strings"
 
+	"github.com/docker/cli/opts"
+	"github.com/docker/go-units"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/network"
 )
 
+// RunOptions are the options of "docker run" a container is created with.
+// List options take one entry per element, as given to the repeated flags
+// of docker run.
+type RunOptions struct {
+	Name       string
+	Command    string
+	Entrypoint string
+	Env        []string // KEY=value, or KEY to take the value of dry's environment
+	EnvFiles   []string
+	// Ports are published as [ip:][hostPort:]containerPort[/proto]; without
+	// any, the ports exposed by the image are published on the same host
+	// ports.
+	Ports       []string
+	Volumes     []string // [source:]target[:options], source being a volume or a host path
+	Network     string
+	Restart     string // no, always, unless-stopped or on-failure[:max-retries]
+	Labels      []string
+	Memory      string // such as 512m
+	CPUs        string // such as 1.5
+	User        string
+	Workdir     string
+	Detach      bool
+	Interactive bool
+	TTY         bool
+	AutoRemove  bool
+}
+
 type containerConfigBuilder struct {
 	config     container.Config
 	hostConfig container.HostConfig
@@ -24,6 +56,14 @@ func (cc *containerConfigBuilder) build() (container.Config, container.HostConfi
 	return cc.config, cc.hostConfig, cc.err
 }
 
+// fail keeps the first error found building the configuration.
+func (cc *containerConfigBuilder) fail(err error) *containerConfigBuilder {
+	if cc.err == nil {
+		cc.err = err
+	}
+	return cc
+}
+
 func (cc *containerConfigBuilder) image(image string) *containerConfigBuilder {
 	cc.config.Image = image
 	return cc
@@ -39,6 +79,16 @@ func (cc *containerConfigBuilder) command(command string) *containerConfigBuilde
 	return cc
 }
 
+// entrypoint sets the executable run by the container. As with
+// docker run --entrypoint, the whole value names it: arguments go in the
+// command.
+func (cc *containerConfigBuilder) entrypoint(entrypoint string) *containerConfigBuilder {
+	if entrypoint != "" {
+		cc.config.Entrypoint = []string{entrypoint}
+	}
+	return cc
+}
+
 func (cc *containerConfigBuilder) ports(portSet map[string]struct{}) *containerConfigBuilder {
 	if len(portSet) > 0 {
 		exposedPorts := make(network.PortSet)
@@ -62,3 +112,236 @@ func (cc *containerConfigBuilder) ports(portSet map[string]struct{}) *containerC
 	}
 	return cc
 }
+
+// publish publishes container ports on the host, as docker run -p does.
+func (cc *containerConfigBuilder) publish(specs []string) *containerConfigBuilder {
+	if len(specs) == 0 {
+		return cc
+	}
+	if cc.config.ExposedPorts == nil {
+		cc.config.ExposedPorts = make(network.PortSet)
+	}
+	if cc.hostConfig.PortBindings == nil {
+		cc.hostConfig.PortBindings = make(network.PortMap)
+	}
+	for _, spec := range specs {
+		if err := cc.publishPort(spec); err != nil {
+			return cc.fail(err)
+		}
+	}
+	return cc
+}
+
+func (cc *containerConfigBuilder) publishPort(spec string) error {
+	var ip, hostPort, containerPort string
+	parts := strings.Split(spec, ":")
+	switch len(parts) {
+	case 1:
+		containerPort = parts[0]
+	case 2:
+		hostPort, containerPort = parts[0], parts[1]
+	case 3:
+		ip, hostPort, containerPort = parts[0], parts[1], parts[2]
+	default:
+		// An IPv6 address is written in brackets.
+		i := strings.LastIndex(spec, "]:")
+		if !strings.HasPrefix(spec, "[") || i < 0 {
+			return fmt.Errorf("invalid port %q", spec)
+		}
+		ip = spec[1:i]
+		hostPort, containerPort, _ = strings.Cut(spec[i+2:], ":")
+	}
+	var hostIP netip.Addr
+	if ip != "" {
+		addr, err := netip.ParseAddr(strings.Trim(ip, "[]"))
+		if err != nil {
+			return fmt.Errorf("invalid address in port %q", spec)
+		}
+		hostIP = addr
+	}
+	ports, err := network.ParsePortRange(containerPort)
+	if err != nil {
+		return fmt.Errorf("invalid port %q: %w", spec, err)
+	}
+	// A range of host ports maps one to one on the container ports.
+	hostStart, hostEnd := 0, 0
+	if hostPort != "" {
+		start, end, isRange := strings.Cut(hostPort, "-")
+		if hostStart, err = strconv.Atoi(start); err != nil {
+			return fmt.Errorf("invalid host port in %q", spec)
+		}
+		hostEnd = hostStart
+		if isRange {
+			if hostEnd, err = strconv.Atoi(end); err != nil || hostEnd < hostStart {
+				return fmt.Errorf("invalid host port in %q", spec)
+			}
+		}
+	}
+	i := 0
+	for p := range ports.All() {
+		binding := network.PortBinding{HostIP: hostIP}
+		if hostPort != "" {
+			if hostStart+i > hostEnd {
+				return fmt.Errorf("invalid port %q: more container ports than host ports", spec)
+			}
+			binding.HostPort = strconv.Itoa(hostStart + i)
+		}
+		cc.config.ExposedPorts[p] = struct{}{}
+		cc.hostConfig.PortBindings[p] = append(cc.hostConfig.PortBindings[p], binding)
+		i++
+	}
+	return nil
+}
+
+// env sets the environment, read from the given files first, as docker run
+// --env-file and -e do.
+func (cc *containerConfigBuilder) env(vars, files []string) *containerConfigBuilder {
+	if len(vars) == 0 && len(files) == 0 {
+		return cc
+	}
+	env, err := opts.ReadKVEnvStrings(files, vars)
+	if err != nil {
+		return cc.fail(fmt.Errorf("env: %w", err))
+	}
+	for _, v := range env {
+		if _, err := opts.ValidateEnv(v); err != nil {
+			return cc.fail(err)
+		}
+	}
+	cc.config.Env = env
+	return cc
+}
+
+// volumes mounts volumes and host paths, as docker run -v does. A target
+// alone creates an anonymous volume.
+func (cc *containerConfigBuilder) volumes(specs []string) *containerConfigBuilder {
+	for _, spec := range specs {
+		parts := strings.Split(spec, ":")
+		switch {
+		case len(parts) == 1 && strings.HasPrefix(spec, "/"):
+			if cc.config.Volumes == nil {
+				cc.config.Volumes = make(map[string]struct{})
+			}
+			cc.config.Volumes[spec] = struct{}{}
+		case (len(parts) == 2 || len(parts) == 3) && parts[0] != "" && strings.HasPrefix(parts[1], "/"):
+			cc.hostConfig.Binds = append(cc.hostConfig.Binds, spec)
+		default:
+			return cc.fail(fmt.Errorf("invalid volume %q", spec))
+		}
+	}
+	return cc
+}
+
+func (cc *containerConfigBuilder) network(name string) *containerConfigBuilder {
+	if name != "" {
+		cc.hostConfig.NetworkMode = container.NetworkMode(name)
+	}
+	return cc
+}
+
+func (cc *containerConfigBuilder) restart(policy string) *containerConfigBuilder {
+	p, err := parseRestartPolicy(policy)
+	if err != nil {
+		return cc.fail(err)
+	}
+	cc.hostConfig.RestartPolicy = p
+	return cc
+}
+
+// parseRestartPolicy parses a restart policy as given to docker run
+// --restart.
+func parseRestartPolicy(policy string) (container.RestartPolicy, error) {
+	p, err := opts.ParseRestartPolicy(policy)
+	if err != nil {
+		return p, err
+	}
+	switch p.Name {
+	case "", container.RestartPolicyDisabled, container.RestartPolicyAlways,
+		container.RestartPolicyUnlessStopped, container.RestartPolicyOnFailure:
+	default:
+		return p, fmt.Errorf("invalid restart policy %q", policy)
+	}
+	if p.MaximumRetryCount != 0 && p.Name != container.RestartPolicyOnFailure {
+		return p, fmt.Errorf("invalid restart policy %q: only on-failure takes a retry count", policy)
+	}
+	return p, nil
+}
+
+func (cc *containerConfigBuilder) labels(labels []string) *containerConfigBuilder {
+	for _, l := range labels {
+		if _, err := opts.ValidateLabel(l); err != nil {
+			return cc.fail(err)
+		}
+	}
+	if len(labels) > 0 {
+		cc.config.Labels = opts.ConvertKVStringsToMap(labels)
+	}
+	return cc
+}
+
+// resources limits the memory, such as "512m", and the CPUs, such as
+// "1.5", of the container.
+func (cc *containerConfigBuilder) resources(memory, cpus string) *containerConfigBuilder {
+	if memory != "" {
+		m, err := units.RAMInBytes(memory)
+		if err != nil || m <= 0 {
+			return cc.fail(fmt.Errorf("invalid memory %q", memory))
+		}
+		cc.hostConfig.Memory = m
+	}
+	if cpus != "" {
+		n, err := opts.ParseCPUs(cpus)
+		if err != nil || n <= 0 {
+			return cc.fail(fmt.Errorf("invalid cpus %q", cpus))
+		}
+		cc.hostConfig.NanoCPUs = n
+	}
+	return cc
+}
+
+func (cc *containerConfigBuilder) user(user string) *containerConfigBuilder {
+	cc.config.User = user
+	return cc
+}
+
+func (cc *containerConfigBuilder) workdir(dir string) *containerConfigBuilder {
+	cc.config.WorkingDir = dir
+	return cc
+}
+
+// streams sets how the container standard streams are attached: an
+// attached container sends its output, an interactive one keeps its input
+// open.
+func (cc *containerConfigBuilder) streams(detach, interactive, tty bool) *containerConfigBuilder {
+	cc.config.Tty = tty
+	cc.config.OpenStdin = interactive
+	if !detach {
+		cc.config.AttachStdout = true
+		cc.config.AttachStderr = true
+		cc.config.AttachStdin = interactive
+		cc.config.StdinOnce = interactive
+	}
+	return cc
+}
+
+func (cc *containerConfigBuilder) autoRemove(remove bool) *containerConfigBuilder {
+	cc.hostConfig.AutoRemove = remove
+	return cc
+}
+
+// runOptions sets everything the given options ask for.
+func (cc *containerConfigBuilder) runOptions(o RunOptions) *containerConfigBuilder {
+	return cc.command(o.Command).
+		entrypoint(o.Entrypoint).
+		env(o.Env, o.EnvFiles).
+		publish(o.Ports).
+		volumes(o.Volumes).
+		network(o.Network).
+		restart(o.Restart).
+		labels(o.Labels).
+		resources(o.Memory, o.CPUs).
+		user(o.User).
+		workdir(o.Workdir).
+		streams(o.Detach, o.Interactive, o.TTY).
+		autoRemove(o.AutoRemove)
+}
@@ -1,6 +1,9 @@
 package docker
 
 import (
+	"net/netip"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -111,3 +114,83 @@ func Test_containerConfigBuilder_build(t *testing.T) {
 		})
 	}
 }
+
+func Test_containerConfigBuilder_runOptions(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "env")
+	if err := os.WriteFile(envFile, []byte("# comment\nFROM_FILE=1\nOVERRIDDEN=file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cc, hc, err := newCCB().image("nginx").runOptions(RunOptions{
+		Command:    "nginx -g daemon off;",
+		Entrypoint: "/opt/app scripts/entrypoint.sh",
+		Env:        []string{"OVERRIDDEN=flag"},
+		EnvFiles:   []string{envFile},
+		Ports:      []string{"8080:80", "127.0.0.1:8443:443/tcp", "9000-9001:9000-9001/udp"},
+		Volumes:    []string{"data:/var/lib/data", "/srv/conf:/etc/nginx:ro", "/cache"},
+		Network:    "backend",
+		Restart:    "on-failure:3",
+		Labels:     []string{"team=web", "tier"},
+		Memory:     "512m",
+		CPUs:       "1.5",
+		User:       "nobody",
+		Workdir:    "/srv",
+		Detach:     true,
+		TTY:        true,
+		AutoRemove: true,
+	}).build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cc.Cmd, []string{"nginx", "-g", "daemon", "off;"}) ||
+		!reflect.DeepEqual(cc.Entrypoint, []string{"/opt/app scripts/entrypoint.sh"}) {
+		t.Errorf("unexpected command %v %v", cc.Entrypoint, cc.Cmd)
+	}
+	if !reflect.DeepEqual(cc.Env, []string{"FROM_FILE=1", "OVERRIDDEN=file", "OVERRIDDEN=flag"}) {
+		t.Errorf("unexpected env %v", cc.Env)
+	}
+	want := network.PortMap{
+		network.MustParsePort("80/tcp"):   {{HostPort: "8080"}},
+		network.MustParsePort("443/tcp"):  {{HostIP: netip.MustParseAddr("127.0.0.1"), HostPort: "8443"}},
+		network.MustParsePort("9000/udp"): {{HostPort: "9000"}},
+		network.MustParsePort("9001/udp"): {{HostPort: "9001"}},
+	}
+	if !reflect.DeepEqual(hc.PortBindings, want) || len(cc.ExposedPorts) != 4 {
+		t.Errorf("unexpected ports %v %v", hc.PortBindings, cc.ExposedPorts)
+	}
+	if !reflect.DeepEqual(hc.Binds, []string{"data:/var/lib/data", "/srv/conf:/etc/nginx:ro"}) || len(cc.Volumes) != 1 {
+		t.Errorf("unexpected volumes %v %v", hc.Binds, cc.Volumes)
+	}
+	if hc.NetworkMode != "backend" || hc.RestartPolicy.Name != container.RestartPolicyOnFailure ||
+		hc.RestartPolicy.MaximumRetryCount != 3 || !hc.AutoRemove {
+		t.Errorf("unexpected host config %+v", hc)
+	}
+	if cc.Labels["team"] != "web" || len(cc.Labels) != 2 {
+		t.Errorf("unexpected labels %v", cc.Labels)
+	}
+	if hc.Memory != 512*1024*1024 || hc.NanoCPUs != 1500000000 {
+		t.Errorf("unexpected resources %d %d", hc.Memory, hc.NanoCPUs)
+	}
+	if cc.User != "nobody" || cc.WorkingDir != "/srv" || !cc.Tty || cc.OpenStdin || cc.AttachStdout {
+		t.Errorf("unexpected config %+v", cc)
+	}
+}
+
+func Test_containerConfigBuilder_runOptionsErrors(t *testing.T) {
+	tests := []RunOptions{
+		{Ports: []string{"80:http"}},
+		{Ports: []string{"localhost:80:80"}},
+		{Ports: []string{"8000:80-81"}},
+		{Volumes: []string{"data"}},
+		{Restart: "sometimes"},
+		{Restart: "always:3"},
+		{Memory: "a lot"},
+		{CPUs: "-1"},
+		{Env: []string{"=value"}},
+		{EnvFiles: []string{"/does/not/exist"}},
+	}
+	for _, o := range tests {
+		if _, _, err := newCCB().runOptions(o).build(); err == nil {
+			t.Errorf("expected %+v to fail", o)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// FileEntry is a file of a container filesystem.
+type FileEntry struct {
+	Path       string // absolute path in the container
+	Size       int64
+	Mode       os.FileMode
+	ModTime    time.Time
+	LinkTarget string
+}
+
+// Name returns the last element of the path of the file.
+func (e FileEntry) Name() string {
+	return path.Base(e.Path)
+}
+
+// IsDir returns true if the file is a directory.
+func (e FileEntry) IsDir() bool {
+	return e.Mode.IsDir()
+}
+
+// FileTree is a container filesystem, as the files of each directory.
+type FileTree struct {
+	entries map[string]FileEntry
+	dirs    map[string][]string
+}
+
+// NewFileTree creates a file tree of the given files. Directories that are
+// not given but hold files that are are added.
+func NewFileTree(files ...FileEntry) *FileTree {
+	t := &FileTree{
+		entries: map[string]FileEntry{"/": {Path: "/", Mode: os.ModeDir | 0o755}},
+		dirs:    make(map[string][]string),
+	}
+	for _, e := range files {
+		if e.Path = path.Clean("/" + e.Path); e.Path != "/" {
+			t.add(e)
+		}
+	}
+	t.sort()
+	return t
+}
+
+// ReadFileTree reads a file tree from the headers of a tar archive, as the
+// one a container is exported as.
+func ReadFileTree(r io.Reader) (*FileTree, error) {
+	var files []FileEntry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		e := FileEntry{Path: hdr.Name, Size: hdr.Size, Mode: hdr.FileInfo().Mode(), ModTime: hdr.ModTime}
+		if hdr.Typeflag == tar.TypeSymlink || hdr.Typeflag == tar.TypeLink {
+			e.LinkTarget = hdr.Linkname
+		}
+		files = append(files, e)
+	}
+	return NewFileTree(files...), nil
+}
+
+// sort sorts the files of every directory, directories first.
+func (t *FileTree) sort() {
+	for dir := range t.dirs {
+		sort.Slice(t.dirs[dir], func(i, j int) bool {
+			a, b := t.entries[t.dirs[dir][i]], t.entries[t.dirs[dir][j]]
+			if a.IsDir() != b.IsDir() {
+				return a.IsDir()
+			}
+			return a.Path < b.Path
+		})
+	}
+}
+
+func (t *FileTree) add(e FileEntry) {
+	if _, ok := t.entries[e.Path]; !ok {
+		parent := path.Dir(e.Path)
+		if _, ok := t.entries[parent]; !ok {
+			t.add(FileEntry{Path: parent, Mode: os.ModeDir | 0o755})
+		}
+		t.dirs[parent] = append(t.dirs[parent], e.Path)
+	}
+	t.entries[e.Path] = e
+}
+
+// Entry returns the file with the given path.
+func (t *FileTree) Entry(p string) (FileEntry, bool) {
+	e, ok := t.entries[p]
+	return e, ok
+}
+
+// Dir returns the files of the directory with the given path, directories
+// first, by name.
+func (t *FileTree) Dir(p string) []FileEntry {
+	files := make([]FileEntry, len(t.dirs[p]))
+	for i, child := range t.dirs[p] {
+		files[i] = t.entries[child]
+	}
+	return files
+}
+
+// ContainerChanges returns the files added, changed or deleted in the
+// filesystem of the container with the given id since it was created.
+func (daemon *DockerDaemon) ContainerChanges(id string) ([]container.FilesystemChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	res, err := daemon.client.ContainerDiff(ctx, id, client.ContainerDiffOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return res.Changes, nil
+}
+
+// ContainerFileTree returns the filesystem of the container with the given
+// id, running or not. Volumes mounted in the container are not in it. The
+// whole filesystem is read, until done or ctx is cancelled.
+func (daemon *DockerDaemon) ContainerFileTree(ctx context.Context, id string) (*FileTree, error) {
+	r, err := daemon.client.ContainerExport(ctx, id, client.ContainerExportOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ReadFileTree(r)
+}
+
+// ReadContainerFile returns up to limit bytes of the file with the given
+// path in the container with the given id.
+func (daemon *DockerDaemon) ReadContainerFile(id, p string, limit int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	res, err := daemon.client.CopyFromContainer(ctx, id, client.CopyFromContainerOptions{SourcePath: p})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Content.Close()
+	if !res.Stat.Mode.IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", p)
+	}
+	tr := tar.NewReader(res.Content)
+	if _, err := tr.Next(); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(io.LimitReader(tr, limit))
+}
+
+// CopyFromContainer copies the file or directory with the given path in the
+// container with the given id into the host directory dest, reporting the
+// bytes received, until done or the context is cancelled.
+func (daemon *DockerDaemon) CopyFromContainer(ctx context.Context, id, src, dest string, progress func(int64)) error {
+	res, err := daemon.client.CopyFromContainer(ctx, id, client.CopyFromContainerOptions{SourcePath: src})
+	if err != nil {
+		return err
+	}
+	defer res.Content.Close()
+	return extractArchive(&progressReader{r: res.Content, progress: progress}, dest)
+}
+
+// CopyToContainer copies the host file or directory src into the directory
+// with the given path in the container with the given id, reporting the
+// bytes sent, until done or the context is cancelled.
+func (daemon *DockerDaemon) CopyToContainer(ctx context.Context, id, src, dest string, progress func(int64)) error {
+	if _, err := os.Lstat(src); err != nil {
+		return err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeArchive(&progressWriter{w: pw, progress: progress}, src))
+	}()
+	defer pr.Close()
+	_, err := daemon.client.CopyToContainer(ctx, id, client.CopyToContainerOptions{
+		DestinationPath: dest,
+		Content:         pr,
+	})
+	return err
+}
+
+// writeArchive writes the file or directory src as a tar archive whose
+// root is named after src.
+func writeArchive(w io.Writer, src string) error {
+	tw := tar.NewWriter(w)
+	base := filepath.Dir(src)
+	err := filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractArchive extracts a tar archive into the directory dest, refusing
+// entries that would be written outside of it.
+func extractArchive(r io.Reader, dest string) error {
+	root, err := filepath.EvalSymlinks(dest)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(root, filepath.FromSlash(path.Clean("/"+hdr.Name)))
+		if !within(root, target) {
+			return fmt.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+		// An entry of the archive must not be written through a link of an
+		// earlier one pointing out of dest.
+		parent, err := resolveExisting(filepath.Dir(target))
+		if err != nil {
+			return err
+		}
+		if !within(root, parent) {
+			return fmt.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+		if hdr.Typeflag != tar.TypeSymlink {
+			// Nor through a link of an earlier entry in its place.
+			resolved, err := resolveExisting(target)
+			if err != nil {
+				return err
+			}
+			if !within(root, resolved) {
+				return fmt.Errorf("invalid path in archive: %s", hdr.Name)
+			}
+		}
+		mode := hdr.FileInfo().Mode()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode.Perm()|0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			// A dangling link is not resolved above, and opening it would
+			// create the file it points to.
+			if fi, err := os.Lstat(target); err == nil && !fi.Mode().IsRegular() {
+				if fi.IsDir() {
+					return fmt.Errorf("invalid file in archive: %s is a directory", hdr.Name)
+				}
+				if err := os.Remove(target); err != nil {
+					return err
+				}
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			_ = os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		}
+		// Devices, fifos and hard links are not copied.
+	}
+}
+
+// resolveExisting resolves the links of the longest part of the path p
+// that exists.
+func resolveExisting(p string) (string, error) {
+	for {
+		resolved, err := filepath.EvalSymlinks(p)
+		if err == nil || !errors.Is(err, os.ErrNotExist) || filepath.Dir(p) == p {
+			return resolved, err
+		}
+		p = filepath.Dir(p)
+	}
+}
+
+// within returns true if p is root or a path under it.
+func within(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,141 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tarOf(t *testing.T, headers ...*tar.Header) *bytes.Buffer {
+	t.Helper()
+	var b bytes.Buffer
+	tw := tar.NewWriter(&b)
+	for _, h := range headers {
+		if h.Typeflag == tar.TypeReg {
+			h.Size = int64(len(h.Name))
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if h.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(h.Name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &b
+}
+
+func TestReadFileTree(t *testing.T) {
+	tree, err := ReadFileTree(tarOf(t,
+		&tar.Header{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0o755},
+		&tar.Header{Name: "etc/hostname", Typeflag: tar.TypeReg, Mode: 0o644},
+		&tar.Header{Name: "bin/sh", Typeflag: tar.TypeSymlink, Linkname: "busybox", Mode: 0o777},
+		&tar.Header{Name: "a.txt", Typeflag: tar.TypeReg, Mode: 0o644},
+		&tar.Header{Name: "etc/ssl/", Typeflag: tar.TypeDir, Mode: 0o755},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range tree.Dir("/") {
+		names = append(names, e.Name())
+	}
+	if len(names) != 3 || names[0] != "bin" || names[1] != "etc" || names[2] != "a.txt" {
+		t.Fatalf("expected directories first, got %v", names)
+	}
+	if bin, ok := tree.Entry("/bin"); !ok || !bin.IsDir() {
+		t.Fatalf("expected the parent of a file to be a directory, got %+v", bin)
+	}
+	if etc := tree.Dir("/etc"); len(etc) != 2 || etc[0].Path != "/etc/ssl" || etc[1].Size != 12 {
+		t.Fatalf("unexpected /etc %+v", etc)
+	}
+	if sh, _ := tree.Entry("/bin/sh"); sh.LinkTarget != "busybox" || sh.Mode&os.ModeSymlink == 0 {
+		t.Fatalf("unexpected link %+v", sh)
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "conf")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "app.conf"), []byte("port=80\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("sub/app.conf", filepath.Join(src, "current")); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := writeArchive(&b, src); err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if err := extractArchive(&b, dest); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dest, "conf", "current"))
+	if err != nil || string(content) != "port=80\n" {
+		t.Fatalf("unexpected copy %q, %v", content, err)
+	}
+	if info, err := os.Stat(filepath.Join(dest, "conf", "sub", "app.conf")); err != nil || info.Mode().Perm() != 0o640 {
+		t.Fatalf("expected the file mode to be kept, got %v, %v", info, err)
+	}
+}
+
+func TestExtractArchiveStaysInDest(t *testing.T) {
+	outside := t.TempDir()
+	dest := t.TempDir()
+	err := extractArchive(tarOf(t,
+		&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: outside, Mode: 0o777},
+		&tar.Header{Name: "link/sub/evil", Typeflag: tar.TypeReg, Mode: 0o644},
+	), dest)
+	if err == nil {
+		t.Fatal("expected writing through a link out of dest to fail")
+	}
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Fatalf("expected nothing written out of dest, got %v", entries)
+	}
+
+	victim := filepath.Join(outside, "bashrc")
+	err = extractArchive(tarOf(t,
+		&tar.Header{Name: "a", Typeflag: tar.TypeSymlink, Linkname: victim, Mode: 0o777},
+		&tar.Header{Name: "a", Typeflag: tar.TypeReg, Mode: 0o644},
+	), dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(victim); !os.IsNotExist(err) {
+		t.Fatalf("expected no file written through a dangling link, got %v", err)
+	}
+	if fi, err := os.Lstat(filepath.Join(dest, "a")); err != nil || !fi.Mode().IsRegular() {
+		t.Fatalf("expected the link to be replaced by the file, got %v", err)
+	}
+
+	if err := os.WriteFile(victim, []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err = extractArchive(tarOf(t,
+		&tar.Header{Name: "b", Typeflag: tar.TypeSymlink, Linkname: victim, Mode: 0o777},
+		&tar.Header{Name: "b", Typeflag: tar.TypeReg, Mode: 0o644},
+	), dest)
+	if err == nil {
+		t.Fatal("expected writing through a link out of dest to fail")
+	}
+	if b, _ := os.ReadFile(victim); string(b) != "keep" {
+		t.Fatalf("expected the file out of dest to be kept, got %q", b)
+	}
+
+	if err := extractArchive(tarOf(t, &tar.Header{Name: "../../evil", Typeflag: tar.TypeReg, Mode: 0o644}), dest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "evil")); err != nil {
+		t.Fatalf("expected a path going up to be kept in dest: %v", err)
+	}
+}
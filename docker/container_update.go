@@ -0,0 +1,141 @@
+package docker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/container"
+)
+
+// ResourceOptions are the options of "docker update" the resources of a
+// container are changed with. An empty option leaves the resource as it is,
+// while none (or 0, or -1) removes the limits of memory, swap, CPU quota
+// and PIDs.
+type ResourceOptions struct {
+	Memory     string // such as 512m
+	MemorySwap string // memory plus swap, such as 1g
+	CPUShares  string // relative weight, such as 512
+	CPUQuota   string // microseconds of CPU time per period
+	CpusetCPUs string // such as 0-2 or 0,1
+	PidsLimit  string
+	Restart    string // no, always, unless-stopped or on-failure[:max-retries]
+}
+
+// unlimited is the value the update API takes to remove a limit.
+const unlimited = -1
+
+// isNone returns true if the option asks for no limit.
+func isNone(option string) bool {
+	return option == "0" || option == "-1" || strings.EqualFold(option, "none")
+}
+
+// ResourceOptionsOf returns the resource options a container has been given,
+// as the options that would set them.
+func ResourceOptionsOf(hc *container.HostConfig) ResourceOptions {
+	if hc == nil {
+		return ResourceOptions{}
+	}
+	o := ResourceOptions{
+		Memory:     formatRAM(hc.Memory),
+		CpusetCPUs: hc.CpusetCpus,
+		Restart:    string(hc.RestartPolicy.Name),
+	}
+	switch {
+	case hc.MemorySwap < 0:
+		o.MemorySwap = "-1"
+	case hc.MemorySwap > 0:
+		o.MemorySwap = formatRAM(hc.MemorySwap)
+	}
+	if hc.CPUShares != 0 {
+		o.CPUShares = strconv.FormatInt(hc.CPUShares, 10)
+	}
+	if hc.CPUQuota != 0 {
+		o.CPUQuota = strconv.FormatInt(hc.CPUQuota, 10)
+	}
+	if hc.PidsLimit != nil && *hc.PidsLimit > 0 {
+		o.PidsLimit = strconv.FormatInt(*hc.PidsLimit, 10)
+	}
+	if hc.RestartPolicy.Name == container.RestartPolicyOnFailure && hc.RestartPolicy.MaximumRetryCount > 0 {
+		o.Restart += ":" + strconv.Itoa(hc.RestartPolicy.MaximumRetryCount)
+	}
+	return o
+}
+
+// formatRAM writes an amount of memory in the largest unit it is a whole
+// number of, so that parsing it back gives the same amount.
+func formatRAM(bytes int64) string {
+	if bytes <= 0 {
+		return ""
+	}
+	for _, u := range []struct {
+		suffix string
+		size   int64
+	}{{"g", units.GiB}, {"m", units.MiB}, {"k", units.KiB}} {
+		if bytes%u.size == 0 {
+			return fmt.Sprintf("%d%s", bytes/u.size, u.suffix)
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+// updateConfig returns the update the given options ask for.
+func (o ResourceOptions) updateConfig() (container.Resources, container.RestartPolicy, error) {
+	var r container.Resources
+	var err error
+	switch {
+	case o.Memory == "":
+	case isNone(o.Memory):
+		// Swap is not limited either by a container without a memory
+		// limit.
+		r.Memory, r.MemorySwap = unlimited, unlimited
+	default:
+		if r.Memory, err = units.RAMInBytes(o.Memory); err != nil || r.Memory <= 0 {
+			return r, container.RestartPolicy{}, fmt.Errorf("invalid memory %q", o.Memory)
+		}
+	}
+	switch {
+	case o.MemorySwap == "":
+	case isNone(o.MemorySwap):
+		r.MemorySwap = unlimited
+	default:
+		if r.MemorySwap, err = units.RAMInBytes(o.MemorySwap); err != nil || r.MemorySwap <= 0 {
+			return r, container.RestartPolicy{}, fmt.Errorf("invalid memory swap %q", o.MemorySwap)
+		}
+	}
+	if o.CPUShares != "" {
+		if r.CPUShares, err = strconv.ParseInt(o.CPUShares, 10, 64); err != nil || r.CPUShares <= 0 {
+			return r, container.RestartPolicy{}, fmt.Errorf("invalid cpu shares %q", o.CPUShares)
+		}
+	}
+	switch {
+	case o.CPUQuota == "":
+	case isNone(o.CPUQuota):
+		r.CPUQuota = unlimited
+	default:
+		if r.CPUQuota, err = strconv.ParseInt(o.CPUQuota, 10, 64); err != nil || r.CPUQuota <= 0 {
+			return r, container.RestartPolicy{}, fmt.Errorf("invalid cpu quota %q", o.CPUQuota)
+		}
+	}
+	r.CpusetCpus = o.CpusetCPUs
+	switch {
+	case o.PidsLimit == "":
+	case isNone(o.PidsLimit):
+		limit := int64(unlimited)
+		r.PidsLimit = &limit
+	default:
+		limit, err := strconv.ParseInt(o.PidsLimit, 10, 64)
+		if err != nil || limit <= 0 {
+			return r, container.RestartPolicy{}, fmt.Errorf("invalid pids limit %q", o.PidsLimit)
+		}
+		r.PidsLimit = &limit
+	}
+	var restart container.RestartPolicy
+	if o.Restart != "" {
+		if restart, err = parseRestartPolicy(o.Restart); err != nil {
+			return r, restart, err
+		}
+	}
+	return r, restart, nil
+}
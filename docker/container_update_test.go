@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+func TestResourceOptions_RoundTrip(t *testing.T) {
+	pids := int64(200)
+	hc := &container.HostConfig{
+		Resources: container.Resources{
+			Memory:     1536 * 1024 * 1024,
+			MemorySwap: -1,
+			CPUShares:  512,
+			CPUQuota:   50000,
+			CpusetCpus: "0-1",
+			PidsLimit:  &pids,
+		},
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 5},
+	}
+	o := ResourceOptionsOf(hc)
+	want := ResourceOptions{
+		Memory: "1536m", MemorySwap: "-1", CPUShares: "512", CPUQuota: "50000",
+		CpusetCPUs: "0-1", PidsLimit: "200", Restart: "on-failure:5",
+	}
+	if o != want {
+		t.Fatalf("ResourceOptionsOf() = %+v, want %+v", o, want)
+	}
+
+	r, restart, err := o.updateConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Memory != hc.Memory || r.MemorySwap != -1 || r.CPUShares != 512 || r.CPUQuota != 50000 ||
+		r.CpusetCpus != "0-1" || r.PidsLimit == nil || *r.PidsLimit != 200 {
+		t.Errorf("unexpected resources %+v", r)
+	}
+	if restart != hc.RestartPolicy {
+		t.Errorf("unexpected restart policy %+v", restart)
+	}
+
+	if o := ResourceOptionsOf(&container.HostConfig{}); o != (ResourceOptions{}) {
+		t.Errorf("expected no options for an unlimited container, got %+v", o)
+	}
+	r, restart, err = ResourceOptions{}.updateConfig()
+	if err != nil || r.PidsLimit != nil || restart.Name != "" {
+		t.Errorf("expected empty options to change nothing, got %+v %+v %v", r, restart, err)
+	}
+}
+
+func TestResourceOptions_NoneRemovesLimits(t *testing.T) {
+	r, _, err := ResourceOptions{Memory: "none", CPUQuota: "0", PidsLimit: "None"}.updateConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Memory != -1 || r.MemorySwap != -1 || r.CPUQuota != -1 || r.PidsLimit == nil || *r.PidsLimit != -1 {
+		t.Errorf("expected the limits to be removed, got %+v", r)
+	}
+	r, _, err = ResourceOptions{Memory: "0", MemorySwap: "2g"}.updateConfig()
+	if err != nil || r.Memory != -1 || r.MemorySwap != 2*1024*1024*1024 {
+		t.Errorf("expected the swap given to be kept, got %+v %v", r, err)
+	}
+}
+
+func TestResourceOptions_Errors(t *testing.T) {
+	tests := []ResourceOptions{
+		{Memory: "a lot"},
+		{Memory: "-5m"},
+		{MemorySwap: "-2"},
+		{CPUShares: "-5"},
+		{CPUQuota: "-2"},
+		{PidsLimit: "many"},
+		{Restart: "sometimes"},
+	}
+	for _, o := range tests {
+		if _, _, err := o.updateConfig(); err == nil {
+			t.Errorf("expected %+v to fail", o)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Context is a named Docker endpoint dry can connect to.
+type Context struct {
+	Name        string
+	Description string
+	Env         Env
+}
+
+// contextMeta is the part of a Docker CLI context meta.json file dry
+// understands.
+type contextMeta struct {
+	Name     string `json:"Name"`
+	Metadata struct {
+		Description string `json:"Description"`
+	} `json:"Metadata"`
+	Endpoints map[string]struct {
+		Host          string `json:"Host"`
+		SkipTLSVerify bool   `json:"SkipTLSVerify"`
+	} `json:"Endpoints"`
+}
+
+// dockerConfigDir returns the Docker CLI configuration directory,
+// $DOCKER_CONFIG or ~/.docker.
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	return defaultDockerPath
+}
+
+// DockerCLIContexts returns the contexts created with `docker context
+// create`, sorted by name. Contexts are stored under
+// <docker config dir>/contexts/meta/<digest>/meta.json, with their TLS
+// material, if any, under contexts/tls/<digest>/docker. Contexts without a
+// Docker endpoint are skipped; the implicit "default" context is not
+// stored and so never returned.
+func DockerCLIContexts() ([]Context, error) {
+	contextsDir := filepath.Join(dockerConfigDir(), "contexts")
+	entries, err := os.ReadDir(filepath.Join(contextsDir, "meta"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read Docker contexts: %w", err)
+	}
+
+	var contexts []Context
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(contextsDir, "meta", entry.Name(), "meta.json"))
+		if err != nil {
+			continue
+		}
+		var meta contextMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil, fmt.Errorf("read Docker context %s: %w", entry.Name(), err)
+		}
+		endpoint, ok := meta.Endpoints["docker"]
+		if !ok || meta.Name == "" || endpoint.Host == "" {
+			continue
+		}
+		env := Env{DockerHost: endpoint.Host}
+		tlsDir := filepath.Join(contextsDir, "tls", entry.Name(), "docker")
+		if _, err := os.Stat(filepath.Join(tlsDir, "ca.pem")); err == nil {
+			env.DockerCertPath = tlsDir
+			env.DockerTLSVerify = !endpoint.SkipTLSVerify
+		}
+		contexts = append(contexts, Context{
+			Name:        meta.Name,
+			Description: meta.Metadata.Description,
+			Env:         env,
+		})
+	}
+	sort.Slice(contexts, func(i, j int) bool {
+		return contexts[i].Name < contexts[j].Name
+	})
+	return contexts, nil
+}
@@ -0,0 +1,60 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeContext(t *testing.T, dir, digest, meta string) {
+	t.Helper()
+	metaDir := filepath.Join(dir, "contexts", "meta", digest)
+	if err := os.MkdirAll(metaDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDockerCLIContexts(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	writeContext(t, dir, "bbb", `{"Name":"remote","Metadata":{"Description":"prod box"},"Endpoints":{"docker":{"Host":"tcp://10.0.0.1:2376","SkipTLSVerify":false}}}`)
+	writeContext(t, dir, "aaa", `{"Name":"builder","Metadata":{},"Endpoints":{"docker":{"Host":"ssh://me@builder"}}}`)
+	writeContext(t, dir, "ccc", `{"Name":"k8s-only","Metadata":{},"Endpoints":{"kubernetes":{"Host":"https://k8s"}}}`)
+	tlsDir := filepath.Join(dir, "contexts", "tls", "bbb", "docker")
+	if err := os.MkdirAll(tlsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tlsDir, "ca.pem"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	contexts, err := DockerCLIContexts()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(contexts) != 2 {
+		t.Fatalf("expected 2 contexts, got %d: %+v", len(contexts), contexts)
+	}
+	if contexts[0].Name != "builder" || contexts[0].Env.DockerHost != "ssh://me@builder" || contexts[0].Env.DockerCertPath != "" {
+		t.Fatalf("unexpected first context: %+v", contexts[0])
+	}
+	remote := contexts[1]
+	if remote.Name != "remote" || remote.Description != "prod box" {
+		t.Fatalf("unexpected second context: %+v", remote)
+	}
+	if remote.Env.DockerCertPath != tlsDir || !remote.Env.DockerTLSVerify {
+		t.Fatalf("expected TLS material from %s, got %+v", tlsDir, remote.Env)
+	}
+}
+
+func TestDockerCLIContexts_NoContextsDir(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	contexts, err := DockerCLIContexts()
+	if err != nil || len(contexts) != 0 {
+		t.Fatalf("expected no contexts and no error, got %v, %v", contexts, err)
+	}
+}
@@ -45,6 +45,7 @@ type DockerDaemon struct {
 	storeLock sync.RWMutex
 	resolver  Resolver
 	eventLog  *EventLog
+	closed    atomic.Bool
 }
 
 // Containers returns the containers known by the daemon
@@ -62,6 +63,16 @@ func (daemon *DockerDaemon) ContainerByID(cid string) *Container {
 	return daemon.store().Get(cid)
 }
 
+// Close releases the connection with the Docker daemon. The daemon stops
+// refreshing its container store on the events of its streams; it must not
+// be used after Close returns.
+func (daemon *DockerDaemon) Close() error {
+	if daemon.closed.Swap(true) {
+		return nil
+	}
+	return daemon.client.Close()
+}
+
 // DiskUsage returns reported Docker disk usage
 func (daemon *DockerDaemon) DiskUsage() (client.DiskUsageResult, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
@@ -74,7 +85,8 @@ func (daemon *DockerDaemon) DockerEnv() Env {
 	return daemon.dockerEnv
 }
 
-// Events returns a channel to receive Docker events.
+// Events returns a channel to receive Docker events. Container events
+// refresh the container store of the daemon.
 // The caller owns cancellation via the provided context.
 // The returned channel is closed when the context is cancelled or the
 // Docker daemon disconnects (error on the event stream).
@@ -103,6 +115,7 @@ func (daemon *DockerDaemon) Events(ctx context.Context) (<-chan events.Message,
 						event,
 						streamEvents(eventC),
 						logEvents(daemon.eventLog),
+						refreshContainers(daemon),
 						callbackNotifier)
 				}
 			case <-res.Err:
@@ -130,6 +143,7 @@ func (daemon *DockerDaemon) Events(ctx context.Context) (<-chan events.Message,
 						event,
 						streamEvents(eventC),
 						logEvents(daemon.eventLog),
+						refreshContainers(daemon),
 						callbackNotifier)
 				case <-swarmEvents.Err:
 					innerCancel()
@@ -210,21 +224,21 @@ func (daemon *DockerDaemon) Kill(id string) error {
 }
 
 // Logs shows the logs of the container with the given id
-func (daemon *DockerDaemon) Logs(id string, since string, withTimeStamps bool) (io.ReadCloser, error) {
+func (daemon *DockerDaemon) Logs(id string, opts LogOptions) (io.ReadCloser, error) {
+	if opts.Tail < 0 {
+		return nil, fmt.Errorf("invalid log tail value %d", opts.Tail)
+	}
 	options := client.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
-		Timestamps: withTimeStamps,
-		Follow:     true,
+		Timestamps: opts.Timestamps,
+		Follow:     opts.Follow,
 		Details:    false,
-		Since:      since,
+		Since:      opts.Since,
+		Until:      opts.Until,
 	}
-	if strings.HasPrefix(since, "tail:") {
-		options.Since = ""
-		options.Tail = strings.TrimPrefix(since, "tail:")
-		if _, err := strconv.Atoi(options.Tail); err != nil {
-			return nil, fmt.Errorf("invalid log tail value %q: %w", options.Tail, err)
-		}
+	if opts.Tail > 0 {
+		options.Tail = strconv.Itoa(opts.Tail)
 	}
 	return daemon.client.ContainerLogs(context.Background(), id, options)
 }
@@ -288,6 +302,28 @@ func (daemon *DockerDaemon) Prune() (*PruneReport, error) {
 	}, nil
 }
 
+// PauseContainer pauses the processes of the container with the given id
+func (daemon *DockerDaemon) PauseContainer(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	if _, err := daemon.client.ContainerPause(ctx, id, client.ContainerPauseOptions{}); err != nil {
+		return err
+	}
+
+	return daemon.refreshAndWait()
+}
+
+// RenameContainer renames the container with the given id
+func (daemon *DockerDaemon) RenameContainer(id, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	if _, err := daemon.client.ContainerRename(ctx, id, client.ContainerRenameOptions{NewName: name}); err != nil {
+		return err
+	}
+
+	return daemon.refreshAndWait()
+}
+
 // RestartContainer restarts the container with the given id
 func (daemon *DockerDaemon) RestartContainer(id string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
@@ -479,6 +515,38 @@ func (daemon *DockerDaemon) StopContainer(id string) error {
 	return daemon.refreshAndWait()
 }
 
+// UnpauseContainer resumes the processes of the paused container with the
+// given id
+func (daemon *DockerDaemon) UnpauseContainer(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	if _, err := daemon.client.ContainerUnpause(ctx, id, client.ContainerUnpauseOptions{}); err != nil {
+		return err
+	}
+
+	return daemon.refreshAndWait()
+}
+
+// UpdateContainerResources changes the resources of the container with the
+// given id, as docker update does
+func (daemon *DockerDaemon) UpdateContainerResources(id string, opts ResourceOptions) error {
+	resources, restart, err := opts.updateConfig()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	options := client.ContainerUpdateOptions{Resources: &resources}
+	if restart.Name != "" {
+		options.RestartPolicy = &restart
+	}
+	if _, err := daemon.client.ContainerUpdate(ctx, id, options); err != nil {
+		return err
+	}
+
+	return daemon.refreshAndWait()
+}
+
 // Top returns Top information for the given container
 func (daemon *DockerDaemon) Top(ctx context.Context, id string) (container.TopResponse, error) {
 	res, err := daemon.client.ContainerTop(ctx, id, client.ContainerTopOptions{})
@@ -569,11 +637,6 @@ func (daemon *DockerDaemon) init() error {
 	} else {
 		return fmt.Errorf("get Docker info: %w", err)
 	}
-	GlobalRegistry.Register(
-		ContainerSource,
-		func(ctx context.Context, message events.Message) {
-			_ = daemon.refreshAndWait()
-		})
 	return nil
 }
 
@@ -638,6 +701,27 @@ func (daemon *DockerDaemon) ComposeServices(project string) []ComposeService {
 	return AggregateComposeServices(daemon.Containers(nil, SortByContainerID), project)
 }
 
+// IsContainerUnhealthy returns true if the health check of the given
+// container is failing
+func IsContainerUnhealthy(c *Container) bool {
+	if c == nil {
+		return false
+	}
+	if c.Health != nil {
+		return c.Health.Status == container.Unhealthy
+	}
+	return c.Detail.State != nil && c.Detail.State.Health != nil &&
+		c.Detail.State.Health.Status == container.Unhealthy
+}
+
+// IsContainerPaused returns true if the given container is paused
+func IsContainerPaused(c *Container) bool {
+	if c == nil {
+		return false
+	}
+	return c.State == container.StatePaused || strings.Contains(c.Status, "(Paused)")
+}
+
 // IsContainerRunning returns true if the given container is running
 func IsContainerRunning(container *Container) bool {
 	if container != nil {
@@ -28,6 +28,17 @@ func logEvents(log *EventLog) EventCallback {
 	}
 }
 
+// refreshContainers refreshes the container store of the daemon on
+// container events, until the daemon is closed.
+func refreshContainers(daemon *DockerDaemon) EventCallback {
+	return func(ctx context.Context, event events.Message) {
+		if SourceType(event.Type) != ContainerSource || daemon.closed.Load() {
+			return
+		}
+		daemon.Refresh(nil)
+	}
+}
+
 func handleEvent(
 	ctx context.Context,
 	event events.Message,
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
 )
 
 func TestHandleEvent_RunsCallbacksSynchronously(t *testing.T) {
@@ -205,3 +206,37 @@ func TestCleanup_ClosesChannelAfterContextCancel(t *testing.T) {
 	}
 	// If we reach here without panic, the test passes.
 }
+
+type containerListCounter struct {
+	client.APIClient
+	lists chan struct{}
+}
+
+func (c containerListCounter) ContainerList(context.Context, client.ContainerListOptions) (client.ContainerListResult, error) {
+	c.lists <- struct{}{}
+	return client.ContainerListResult{}, nil
+}
+
+func (c containerListCounter) Close() error { return nil }
+
+func TestRefreshContainers_RefreshesItsDaemonUntilClosed(t *testing.T) {
+	lists := make(chan struct{}, 4)
+	daemon := &DockerDaemon{client: containerListCounter{lists: lists}}
+
+	refresh := refreshContainers(daemon)
+	refresh(context.Background(), events.Message{Type: events.ImageEventType})
+	refresh(context.Background(), events.Message{Type: events.ContainerEventType})
+	select {
+	case <-lists:
+	case <-time.After(time.Second):
+		t.Fatal("expected a container event to refresh the containers of the daemon")
+	}
+
+	_ = daemon.Close()
+	refresh(context.Background(), events.Message{Type: events.ContainerEventType})
+	select {
+	case <-lists:
+		t.Fatal("expected the image event and the closed daemon not to refresh")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
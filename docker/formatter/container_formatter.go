@@ -25,8 +25,31 @@ const (
 	portsHeader      = "PORTS"
 	sizeHeader       = "SIZE"
 	labelsHeader     = "LABELS"
+	mountsHeader     = "MOUNTS"
+	networksHeader   = "NETWORKS"
+	healthHeader     = "HEALTH"
+	hostHeader       = "HOST"
 )
 
+// ContainerFields maps the fields a container row format can use to their
+// column headers.
+var ContainerFields = map[string]string{
+	"ID":         idHeader,
+	"Names":      namesHeader,
+	"Image":      imageHeader,
+	"Command":    commandHeader,
+	"CreatedAt":  createdAtHeader,
+	"RunningFor": runningForHeader,
+	"Status":     statusHeader,
+	"Ports":      portsHeader,
+	"Size":       sizeHeader,
+	"Labels":     labelsHeader,
+	"Mounts":     mountsHeader,
+	"Networks":   networksHeader,
+	"Health":     healthHeader,
+	"Host":       hostHeader,
+}
+
 // ContainerFormatter knows how to pretty-print the information of a container
 type ContainerFormatter struct {
 	trunc  bool
@@ -48,6 +71,13 @@ func (c *ContainerFormatter) ID() string {
 	return c.c.ID
 }
 
+// Host returns the name of the Docker host the container runs on, when
+// containers from several hosts are listed.
+func (c *ContainerFormatter) Host() string {
+	c.addHeader(hostHeader)
+	return c.c.Host
+}
+
 // Names prettifies the container name(s)
 func (c *ContainerFormatter) Names() string {
 	c.addHeader(namesHeader)
@@ -140,6 +170,52 @@ func (c *ContainerFormatter) Labels() string {
 	return FormatLabels(c.c.Labels)
 }
 
+// Mounts prettifies the container mounts: volume names, or the source
+// of other mounts.
+func (c *ContainerFormatter) Mounts() string {
+	c.addHeader(mountsHeader)
+	mounts := make([]string, 0, len(c.c.Mounts))
+	for _, m := range c.c.Mounts {
+		name := m.Name
+		if name == "" {
+			name = m.Source
+		}
+		if c.trunc && len(name) > 15 {
+			name = name[:14] + "…"
+		}
+		mounts = append(mounts, name)
+	}
+	return strings.Join(mounts, ",")
+}
+
+// Networks prettifies the names of the networks the container is
+// attached to.
+func (c *ContainerFormatter) Networks() string {
+	c.addHeader(networksHeader)
+	if c.c.NetworkSettings == nil {
+		return ""
+	}
+	networks := make([]string, 0, len(c.c.NetworkSettings.Networks))
+	for name := range c.c.NetworkSettings.Networks {
+		networks = append(networks, name)
+	}
+	sort.Strings(networks)
+	return strings.Join(networks, ",")
+}
+
+// Health prettifies the container health status, empty for containers
+// without a health check.
+func (c *ContainerFormatter) Health() string {
+	c.addHeader(healthHeader)
+	if c.c.Health != nil && c.c.Health.Status != container.NoHealthcheck {
+		return string(c.c.Health.Status)
+	}
+	if c.c.Detail.State != nil && c.c.Detail.State.Health != nil {
+		return string(c.c.Detail.State.Health.Status)
+	}
+	return ""
+}
+
 func (c *ContainerFormatter) addHeader(header string) {
 	if c.header == nil {
 		c.header = []string{}
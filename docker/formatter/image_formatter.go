@@ -17,6 +17,17 @@ const (
 	size          = "SIZE"
 )
 
+// ImageFields maps the fields an image row format can use to their column
+// headers.
+var ImageFields = map[string]string{
+	"ID":           imageIDHeader,
+	"Repository":   repository,
+	"Tag":          tag,
+	"Digest":       digest,
+	"CreatedSince": "CREATED",
+	"Size":         size,
+}
+
 // ImageFormatter knows how to pretty-print the information of an image
 type ImageFormatter struct {
 	trunc  bool
@@ -20,6 +20,19 @@ const (
 	gateway            = "GATEWAYS"
 )
 
+// NetworkFields maps the fields a network row format can use to their
+// column headers.
+var NetworkFields = map[string]string{
+	"ID":         networkIDHeader,
+	"Name":       name,
+	"Driver":     driver,
+	"Containers": numberOfContainers,
+	"Services":   numberOfServices,
+	"Scope":      scope,
+	"Subnet":     subnet,
+	"Gateway":    gateway,
+}
+
 // NetworkFormatter knows how to pretty-print the information of an network
 type NetworkFormatter struct {
 	trunc   bool
@@ -0,0 +1,95 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/docker/cli/templates"
+)
+
+// RowFormat is a format template such as "table {{.Names}}\t{{.Status}}",
+// as given to docker ps --format, split on its tabs into the cells of a
+// table row.
+type RowFormat struct {
+	format  Format
+	cells   []*template.Template
+	headers []string
+}
+
+// ParseRowFormat parses a row format. Fields maps the fields the template
+// can use to their column headers; using any other field is an error.
+func ParseRowFormat(format Format, fields map[string]string) (*RowFormat, error) {
+	text := string(format)
+	if format.IsTable() {
+		text = text[len(TableFormatKey):]
+	}
+	text = strings.NewReplacer(`\t`, "\t", `\n`, " ", "\n", " ").Replace(strings.Trim(text, " "))
+	if text == "" {
+		return nil, errors.New("empty format")
+	}
+	r := &RowFormat{format: format}
+	for _, cell := range strings.Split(text, "\t") {
+		tmpl, err := templates.Parse(cell)
+		if err != nil {
+			return nil, fmt.Errorf("parse template: %w", err)
+		}
+		header, err := cellHeader(tmpl, fields)
+		if err != nil {
+			return nil, err
+		}
+		r.cells = append(r.cells, tmpl)
+		r.headers = append(r.headers, header)
+	}
+	return r, nil
+}
+
+// cellHeader renders the header of a cell by executing it against the
+// field headers, which also catches unknown fields before any row is
+// rendered.
+func cellHeader(tmpl *template.Template, fields map[string]string) (string, error) {
+	h, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := h.Funcs(templates.HeaderFunctions).Option("missingkey=error").Execute(&b, fields); err != nil {
+		valid := slices.Sorted(maps.Keys(fields))
+		return "", fmt.Errorf("template execution: %w (valid fields: %s)", err, strings.Join(valid, ", "))
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// String returns the format as it was given.
+func (r *RowFormat) String() string {
+	return string(r.format)
+}
+
+// IsTable returns true if the format asked for a table, with headers.
+func (r *RowFormat) IsTable() bool {
+	return r.format.IsTable()
+}
+
+// Headers returns the header of each cell.
+func (r *RowFormat) Headers() []string {
+	return r.headers
+}
+
+// Row renders the cells of a row for data, the formatter of the item the
+// row shows. A cell that cannot be rendered shows why instead.
+func (r *RowFormat) Row(data any) []string {
+	row := make([]string, len(r.cells))
+	var b strings.Builder
+	for i, cell := range r.cells {
+		b.Reset()
+		if err := cell.Execute(&b, data); err != nil {
+			row[i] = fmt.Sprintf("<%s>", err)
+			continue
+		}
+		row[i] = b.String()
+	}
+	return row
+}
@@ -0,0 +1,48 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moncho/dry/docker"
+)
+
+func TestParseRowFormat(t *testing.T) {
+	f, err := ParseRowFormat(`table {{.Names}}\t{{.Status}} ({{upper .Health}})`, ContainerFields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !f.IsTable() {
+		t.Error("expected a table format")
+	}
+	if got := f.Headers(); len(got) != 2 || got[0] != "NAMES" || got[1] != "STATUS (HEALTH)" {
+		t.Fatalf("unexpected headers %q", got)
+	}
+	c := &docker.Container{Summary: container.Summary{
+		Names:  []string{"/web"},
+		Status: "Up 2 hours",
+		Health: &container.HealthSummary{Status: container.Healthy},
+	}}
+	row := f.Row(NewContainerFormatter(c, true))
+	if len(row) != 2 || row[0] != "web" || row[1] != "Up 2 hours (HEALTHY)" {
+		t.Fatalf("unexpected row %q", row)
+	}
+}
+
+func TestParseRowFormat_Errors(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table ", "empty format"},
+		{"{{.Names", "parse template"},
+		{"{{.Names}}\t{{.Bogus}}", "valid fields: Command, CreatedAt"},
+	}
+	for _, tt := range tests {
+		_, err := ParseRowFormat(Format(tt.format), ContainerFields)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: expected error containing %q, got %v", tt.format, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package formatter
+
+import (
+	"github.com/moby/moby/api/types/volume"
+)
+
+const (
+	volumeNameHeader = "VOLUME NAME"
+	mountpointHeader = "MOUNTPOINT"
+)
+
+// VolumeFields maps the fields a volume row format can use to their column
+// headers.
+var VolumeFields = map[string]string{
+	"Driver":     driver,
+	"Name":       volumeNameHeader,
+	"Mountpoint": mountpointHeader,
+	"Scope":      scope,
+	"CreatedAt":  createdAtHeader,
+	"Labels":     labelsHeader,
+}
+
+// VolumeFormatter knows how to pretty-print the information of a volume
+type VolumeFormatter struct {
+	volume volume.Volume
+}
+
+// NewVolumeFormatter creates a volume formatter
+func NewVolumeFormatter(volume volume.Volume) *VolumeFormatter {
+	return &VolumeFormatter{volume: volume}
+}
+
+// Driver returns the volume driver
+func (formatter *VolumeFormatter) Driver() string {
+	return formatter.volume.Driver
+}
+
+// Name returns the volume name
+func (formatter *VolumeFormatter) Name() string {
+	return formatter.volume.Name
+}
+
+// Mountpoint returns where the volume is mounted on the host
+func (formatter *VolumeFormatter) Mountpoint() string {
+	return formatter.volume.Mountpoint
+}
+
+// Scope returns the volume scope
+func (formatter *VolumeFormatter) Scope() string {
+	return formatter.volume.Scope
+}
+
+// CreatedAt returns when the volume was created
+func (formatter *VolumeFormatter) CreatedAt() string {
+	return formatter.volume.CreatedAt
+}
+
+// Labels prettifies the volume labels
+func (formatter *VolumeFormatter) Labels() string {
+	return FormatLabels(formatter.volume.Labels)
+}
@@ -0,0 +1,198 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/moby/moby/client"
+)
+
+// CommitOptions are the options of "docker commit" an image is created from
+// a container with.
+type CommitOptions struct {
+	Reference string   // repository[:tag] of the image, untagged if empty
+	Comment   string   // commit message
+	Author    string   // such as "Jane Doe <jane@example.com>"
+	Changes   []string // Dockerfile instructions applied, such as CMD or ENV
+	NoPause   bool     // do not pause the container while it is committed
+}
+
+// CommitContainer creates an image from the container with the given id,
+// returning the ID of the image.
+func (daemon *DockerDaemon) CommitContainer(id string, opts CommitOptions) (string, error) {
+	if opts.Reference != "" {
+		named, err := reference.ParseNormalizedNamed(opts.Reference)
+		if err != nil {
+			return "", fmt.Errorf("invalid image reference %q: %w", opts.Reference, err)
+		}
+		if _, ok := named.(reference.Digested); ok {
+			return "", fmt.Errorf("invalid image reference %q: images are tagged, not digested", opts.Reference)
+		}
+		opts.Reference = reference.FamiliarString(reference.TagNameOnly(named))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	res, err := daemon.client.ContainerCommit(ctx, id, client.ContainerCommitOptions{
+		Reference: opts.Reference,
+		Comment:   opts.Comment,
+		Author:    opts.Author,
+		Changes:   opts.Changes,
+		NoPause:   opts.NoPause,
+	})
+	if err != nil {
+		return "", err
+	}
+	return res.ID, nil
+}
+
+// ExportContainer writes the filesystem of the container with the given id
+// to a new tar archive at path, reporting the bytes written, until done or
+// ctx is cancelled.
+func (daemon *DockerDaemon) ExportContainer(ctx context.Context, id, path string, progress func(int64)) error {
+	if err := checkNotExists(path); err != nil {
+		return err
+	}
+	r, err := daemon.client.ContainerExport(ctx, id, client.ContainerExportOptions{})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return writeTarFile(path, r, progress)
+}
+
+// ImageSave writes the images with the given references to a new tar
+// archive at path, as docker save does, reporting the bytes written, until
+// done or ctx is cancelled.
+func (daemon *DockerDaemon) ImageSave(ctx context.Context, refs []string, path string, progress func(int64)) error {
+	if err := checkNotExists(path); err != nil {
+		return err
+	}
+	r, err := daemon.client.ImageSave(ctx, refs)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return writeTarFile(path, r, progress)
+}
+
+// ImageLoad loads the images of the tar archive at path, as docker load
+// does, reporting the bytes read, until done or ctx is cancelled. It
+// returns what the daemon loaded.
+func (daemon *DockerDaemon) ImageLoad(ctx context.Context, path string, progress func(int64)) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	res, err := daemon.client.ImageLoad(ctx, &progressReader{r: f, progress: progress})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	return readLoadResponse(res)
+}
+
+// loadMessage is a message of the stream an image load answers with.
+type loadMessage struct {
+	Stream string `json:"stream"`
+	Error  string `json:"error"`
+}
+
+// readLoadResponse returns the images an image load reports as loaded, or
+// the error it reports.
+func readLoadResponse(r io.Reader) ([]string, error) {
+	var loaded []string
+	dec := json.NewDecoder(r)
+	for {
+		var msg loadMessage
+		if err := dec.Decode(&msg); err == io.EOF {
+			return loaded, nil
+		} else if err != nil {
+			return loaded, err
+		}
+		if msg.Error != "" {
+			return loaded, errors.New(msg.Error)
+		}
+		for _, prefix := range []string{"Loaded image: ", "Loaded image ID: "} {
+			if name, ok := strings.CutPrefix(strings.TrimSpace(msg.Stream), prefix); ok {
+				loaded = append(loaded, name)
+			}
+		}
+	}
+}
+
+// checkNotExists returns an error if there is a file at path, archives
+// never replacing one.
+func checkNotExists(path string) error {
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// writeTarFile writes the archive r to a new file at path. The archive is
+// written to a temporary file moved to path once complete, so that path is
+// left as it was if writing fails.
+func writeTarFile(path string, r io.Reader, progress func(int64)) error {
+	if err := checkNotExists(path); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(&progressWriter{w: f, progress: progress}, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		if err = checkNotExists(path); err == nil {
+			err = os.Rename(f.Name(), path)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// progressWriter reports the bytes written so far after every write.
+type progressWriter struct {
+	w        io.Writer
+	n        int64
+	progress func(int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.n += int64(n)
+	if p.progress != nil {
+		p.progress(p.n)
+	}
+	return n, err
+}
+
+// progressReader reports the bytes read so far after every read.
+type progressReader struct {
+	r        io.Reader
+	n        int64
+	progress func(int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.n += int64(n)
+	if p.progress != nil && n > 0 {
+		p.progress(p.n)
+	}
+	return n, err
+}
@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadLoadResponse(t *testing.T) {
+	stream := `{"stream":"Loaded image: nginx:latest\n"}
+{"stream":"Loaded image ID: sha256:0123456789ab\n"}
+`
+	loaded, err := readLoadResponse(strings.NewReader(stream))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[0] != "nginx:latest" || loaded[1] != "sha256:0123456789ab" {
+		t.Fatalf("unexpected images loaded %v", loaded)
+	}
+
+	_, err = readLoadResponse(strings.NewReader(`{"error":"unexpected EOF"}`))
+	if err == nil || err.Error() != "unexpected EOF" {
+		t.Fatalf("expected the error of the stream, got %v", err)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestWriteTarFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "images.tar")
+	var reported int64
+	if err := writeTarFile(path, strings.NewReader("archive"), func(n int64) { reported = n }); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "archive" {
+		t.Fatalf("unexpected file %q, %v", b, err)
+	}
+	if reported != int64(len("archive")) {
+		t.Fatalf("expected %d bytes reported, got %d", len("archive"), reported)
+	}
+
+	if err := writeTarFile(path, strings.NewReader("other"), nil); err == nil {
+		t.Fatal("expected an existing file not to be replaced")
+	}
+	if b, _ := os.ReadFile(path); string(b) != "archive" {
+		t.Fatalf("expected the existing file to be kept, got %q", b)
+	}
+
+	failed := filepath.Join(filepath.Dir(path), "failed.tar")
+	r := io.MultiReader(strings.NewReader("partial"), failingReader{})
+	if err := writeTarFile(failed, r, nil); err == nil {
+		t.Fatal("expected the read error")
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 || entries[0].Name() != "images.tar" {
+		t.Fatalf("expected nothing left of the failed archive, got %v", entries)
+	}
+}
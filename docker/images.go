@@ -46,45 +46,83 @@ func (daemon *DockerDaemon) Images() ([]image.Summary, error) {
 	return res.Items, nil
 }
 
-// RunImage creates a container based on the given image and runs the given command
-// Kind of like running "docker run $image $command" from the command line.
-func (daemon *DockerDaemon) RunImage(image image.Summary, command string) error {
+// RunImage creates a container based on the given image and starts it,
+// returning its ID. Kind of like running "docker run $options $image
+// $command" from the command line, except that the container is not
+// attached to.
+func (daemon *DockerDaemon) RunImage(image image.Summary, opts RunOptions) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
 	defer cancel()
 
+	id, imageName, err := daemon.createImageContainer(ctx, image, opts)
+	if err != nil {
+		return "", err
+	}
+	if _, err := daemon.client.ContainerStart(ctx, id, client.ContainerStartOptions{}); err != nil {
+		return "", fmt.Errorf("run image: start container for image %s: %w", imageName, err)
+	}
+	return id, nil
+}
+
+// RunImageAttached is RunImage for containers that are not detached: as
+// docker run does, the container is attached to before it starts, so that
+// none of its output is lost. The session returned is streamed by the
+// caller.
+func (daemon *DockerDaemon) RunImageAttached(image image.Summary, opts RunOptions) (*AttachSession, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+
+	id, imageName, err := daemon.createImageContainer(ctx, image, opts)
+	if err != nil {
+		return nil, err
+	}
+	// The session outlives this call, its context must too.
+	session, err := daemon.attach(context.Background(), id, opts.TTY, "")
+	if err != nil {
+		return nil, fmt.Errorf("run image: %w", err)
+	}
+	if _, err := daemon.client.ContainerStart(ctx, id, client.ContainerStartOptions{}); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("run image: start container for image %s: %w", imageName, err)
+	}
+	return session, nil
+}
+
+// createImageContainer creates a container of the given image with the
+// options of docker run, returning its ID and the image name used.
+func (daemon *DockerDaemon) createImageContainer(ctx context.Context, image image.Summary, opts RunOptions) (string, string, error) {
 	var imageName string
 	if len(image.RepoTags) > 0 {
 		imageName = image.RepoTags[0]
 	} else if len(image.RepoDigests) > 0 {
 		imageName = image.RepoDigests[0]
 	} else {
-		return errors.New("run image: image has no tag or digest")
-	}
-
-	imageDetails, err := daemon.InspectImage(imageName)
-	if err != nil {
-		return fmt.Errorf("run image: inspect image %s: %w", imageName, err)
+		return "", "", errors.New("run image: image has no tag or digest")
 	}
 
+	// Without ports to publish, those exposed by the image are.
 	var exposedPorts map[string]struct{}
-	if imageDetails.Config != nil && imageDetails.Config.ExposedPorts != nil {
-		exposedPorts = imageDetails.Config.ExposedPorts
+	if len(opts.Ports) == 0 {
+		imageDetails, err := daemon.InspectImage(imageName)
+		if err != nil {
+			return "", "", fmt.Errorf("run image: inspect image %s: %w", imageName, err)
+		}
+		if imageDetails.Config != nil && imageDetails.Config.ExposedPorts != nil {
+			exposedPorts = imageDetails.Config.ExposedPorts
+		}
 	}
-	cc, hc, err := newCCB().image(imageName).command(command).ports(exposedPorts).build()
+	cc, hc, err := newCCB().image(imageName).ports(exposedPorts).runOptions(opts).build()
 	if err != nil {
-		return fmt.Errorf("run image: %w", err)
+		return "", "", fmt.Errorf("run image: %w", err)
 	}
 
 	cCreated, err := daemon.client.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Name:       opts.Name,
 		Config:     &cc,
 		HostConfig: &hc,
 	})
 	if err != nil {
-		return fmt.Errorf("run image: create container for image %s: %w", imageName, err)
-	}
-
-	if _, err := daemon.client.ContainerStart(ctx, cCreated.ID, client.ContainerStartOptions{}); err != nil {
-		return fmt.Errorf("run image: start container for image %s: %w", imageName, err)
+		return "", "", fmt.Errorf("run image: create container for image %s: %w", imageName, err)
 	}
-	return nil
+	return cCreated.ID, imageName, nil
 }
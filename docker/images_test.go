@@ -9,9 +9,9 @@ import (
 
 func TestImageRun(t *testing.T) {
 	daemon := DockerDaemon{client: mock.ImageAPIClientMock{}}
-	err := daemon.RunImage(image.Summary{
+	_, err := daemon.RunImage(image.Summary{
 		RepoTags: []string{"nope:latest"},
-	}, "command")
+	}, RunOptions{Command: "command"})
 	if err != nil {
 		t.Errorf("Running an image resulted in error %s", err.Error())
 	}
@@ -0,0 +1,212 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+// ContainerHost is a named daemon taking part in a MultiHostContainers.
+type ContainerHost struct {
+	Name string
+	API  ContainerAPI
+}
+
+// MultiHostContainers is a ContainerAPI spanning several daemons. Listings
+// merge the containers of every daemon, each one tagged with the name of
+// the host it came from, and operations on a container are routed to the
+// daemon that owns it.
+type MultiHostContainers struct {
+	hosts []ContainerHost
+}
+
+// NewMultiHostContainers creates a ContainerAPI over the given hosts.
+func NewMultiHostContainers(hosts ...ContainerHost) *MultiHostContainers {
+	return &MultiHostContainers{hosts: hosts}
+}
+
+// Hosts returns the names of the hosts, in the order they were given.
+func (m *MultiHostContainers) Hosts() []string {
+	names := make([]string, len(m.hosts))
+	for i, h := range m.hosts {
+		names[i] = h.Name
+	}
+	return names
+}
+
+// HostOf returns the name of the host owning the container with the given
+// id.
+func (m *MultiHostContainers) HostOf(id string) (string, bool) {
+	h, err := m.owner(id)
+	if err != nil {
+		return "", false
+	}
+	return h.Name, true
+}
+
+func (m *MultiHostContainers) owner(id string) (ContainerHost, error) {
+	for _, h := range m.hosts {
+		if h.API.ContainerByID(id) != nil {
+			return h, nil
+		}
+	}
+	return ContainerHost{}, fmt.Errorf("container %s not found on any host", id)
+}
+
+// tagged returns a copy of the given container tagged with the host name,
+// the stores of each daemon keep their own containers untouched.
+func tagged(c *Container, host string) *Container {
+	cc := *c
+	cc.Host = host
+	return &cc
+}
+
+// ContainerByID returns the container with the given id, tagged with its host.
+func (m *MultiHostContainers) ContainerByID(id string) *Container {
+	for _, h := range m.hosts {
+		if c := h.API.ContainerByID(id); c != nil {
+			return tagged(c, h.Name)
+		}
+	}
+	return nil
+}
+
+// Containers returns the containers of every host matching the given
+// filters, sorted as a single list.
+func (m *MultiHostContainers) Containers(filter []ContainerFilter, mode SortMode) []*Container {
+	var containers []*Container
+	for _, h := range m.hosts {
+		for _, c := range h.API.Containers(filter, NoSort) {
+			containers = append(containers, tagged(c, h.Name))
+		}
+	}
+	SortContainers(containers, mode)
+	return containers
+}
+
+// Inspect inspects the container with the given id on its host.
+func (m *MultiHostContainers) Inspect(id string) (container.InspectResponse, error) {
+	h, err := m.owner(id)
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+	return h.API.Inspect(id)
+}
+
+// IsContainerRunning returns true if the container with the given id is
+// running on its host.
+func (m *MultiHostContainers) IsContainerRunning(id string) bool {
+	h, err := m.owner(id)
+	if err != nil {
+		return false
+	}
+	return h.API.IsContainerRunning(id)
+}
+
+// Kill kills the container with the given id on its host.
+func (m *MultiHostContainers) Kill(id string) error {
+	h, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return h.API.Kill(id)
+}
+
+// Logs returns the logs of the container with the given id from its host.
+func (m *MultiHostContainers) Logs(id string, opts LogOptions) (io.ReadCloser, error) {
+	h, err := m.owner(id)
+	if err != nil {
+		return nil, err
+	}
+	return h.API.Logs(id, opts)
+}
+
+// RemoveAllStoppedContainers removes the stopped containers of every host.
+// Hosts failing to do so do not stop the others from being cleaned up.
+func (m *MultiHostContainers) RemoveAllStoppedContainers() (int, error) {
+	var count int
+	var errs []error
+	for _, h := range m.hosts {
+		removed, err := h.API.RemoveAllStoppedContainers()
+		count += removed
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.Name, err))
+		}
+	}
+	return count, errors.Join(errs...)
+}
+
+// PauseContainer pauses the container with the given id on its host.
+func (m *MultiHostContainers) PauseContainer(id string) error {
+	h, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return h.API.PauseContainer(id)
+}
+
+// RenameContainer renames the container with the given id on its host.
+func (m *MultiHostContainers) RenameContainer(id, name string) error {
+	h, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return h.API.RenameContainer(id, name)
+}
+
+// RestartContainer restarts the container with the given id on its host.
+func (m *MultiHostContainers) RestartContainer(id string) error {
+	h, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return h.API.RestartContainer(id)
+}
+
+// Rm removes the container with the given id from its host.
+func (m *MultiHostContainers) Rm(id string) error {
+	h, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return h.API.Rm(id)
+}
+
+// StartContainer starts the container with the given id on its host.
+func (m *MultiHostContainers) StartContainer(id string) error {
+	h, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return h.API.StartContainer(id)
+}
+
+// StopContainer stops the container with the given id on its host.
+func (m *MultiHostContainers) StopContainer(id string) error {
+	h, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return h.API.StopContainer(id)
+}
+
+// UnpauseContainer unpauses the container with the given id on its host.
+func (m *MultiHostContainers) UnpauseContainer(id string) error {
+	h, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return h.API.UnpauseContainer(id)
+}
+
+// UpdateContainerResources changes the resources of the container with the
+// given id on its host.
+func (m *MultiHostContainers) UpdateContainerResources(id string, opts ResourceOptions) error {
+	h, err := m.owner(id)
+	if err != nil {
+		return err
+	}
+	return h.API.UpdateContainerResources(id, opts)
+}
@@ -0,0 +1,205 @@
+package docker
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+// hostContainersMock is a ContainerAPI over a fixed set of containers that
+// records the operations it is asked to run.
+type hostContainersMock struct {
+	containers []*Container
+	removeErr  error
+	ops        []string
+}
+
+func (h *hostContainersMock) ContainerByID(id string) *Container {
+	for _, c := range h.containers {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (h *hostContainersMock) Containers(filters []ContainerFilter, mode SortMode) []*Container {
+	result := append([]*Container(nil), h.containers...)
+	for _, filter := range filters {
+		result = filter.Apply(result)
+	}
+	SortContainers(result, mode)
+	return result
+}
+
+func (h *hostContainersMock) Inspect(id string) (container.InspectResponse, error) {
+	h.ops = append(h.ops, "inspect "+id)
+	return container.InspectResponse{}, nil
+}
+
+func (h *hostContainersMock) IsContainerRunning(id string) bool {
+	return IsContainerRunning(h.ContainerByID(id))
+}
+
+func (h *hostContainersMock) Kill(id string) error {
+	h.ops = append(h.ops, "kill "+id)
+	return nil
+}
+
+func (h *hostContainersMock) Logs(id string, _ LogOptions) (io.ReadCloser, error) {
+	h.ops = append(h.ops, "logs "+id)
+	return nil, nil
+}
+
+func (h *hostContainersMock) PauseContainer(id string) error {
+	h.ops = append(h.ops, "pause "+id)
+	return nil
+}
+
+func (h *hostContainersMock) RemoveAllStoppedContainers() (int, error) {
+	h.ops = append(h.ops, "rm-all-stopped")
+	if h.removeErr != nil {
+		return 0, h.removeErr
+	}
+	return 1, nil
+}
+
+func (h *hostContainersMock) RenameContainer(id, name string) error {
+	h.ops = append(h.ops, "rename "+id+" "+name)
+	return nil
+}
+
+func (h *hostContainersMock) RestartContainer(id string) error {
+	h.ops = append(h.ops, "restart "+id)
+	return nil
+}
+
+func (h *hostContainersMock) Rm(id string) error {
+	h.ops = append(h.ops, "rm "+id)
+	return nil
+}
+
+func (h *hostContainersMock) StartContainer(id string) error {
+	h.ops = append(h.ops, "start "+id)
+	return nil
+}
+
+func (h *hostContainersMock) StopContainer(id string) error {
+	h.ops = append(h.ops, "stop "+id)
+	return nil
+}
+
+func (h *hostContainersMock) UnpauseContainer(id string) error {
+	h.ops = append(h.ops, "unpause "+id)
+	return nil
+}
+
+func (h *hostContainersMock) UpdateContainerResources(id string, _ ResourceOptions) error {
+	h.ops = append(h.ops, "update "+id)
+	return nil
+}
+
+func hostContainer(id, name, status string) *Container {
+	return &Container{Summary: container.Summary{
+		ID:     id,
+		Names:  []string{"/" + name},
+		Status: status,
+	}}
+}
+
+func TestMultiHostContainers_ContainersAreMergedAndTagged(t *testing.T) {
+	local := &hostContainersMock{containers: []*Container{
+		hostContainer("c3", "web", "Up 2 hours"),
+		hostContainer("c1", "db", "Exited (0) 1 hour ago"),
+	}}
+	remote := &hostContainersMock{containers: []*Container{
+		hostContainer("c2", "cache", "Up 2 hours"),
+	}}
+	hosts := NewMultiHostContainers(
+		ContainerHost{Name: "default", API: local},
+		ContainerHost{Name: "staging", API: remote},
+	)
+
+	containers := hosts.Containers(nil, SortByContainerID)
+	if len(containers) != 3 {
+		t.Fatalf("expected 3 containers, got %d", len(containers))
+	}
+	want := []struct{ id, host string }{
+		{"c1", "default"}, {"c2", "staging"}, {"c3", "default"},
+	}
+	for i, w := range want {
+		if containers[i].ID != w.id || containers[i].Host != w.host {
+			t.Errorf("container %d: got %s@%s, want %s@%s",
+				i, containers[i].ID, containers[i].Host, w.id, w.host)
+		}
+	}
+	if local.containers[0].Host != "" {
+		t.Error("tagging must not modify the containers of the host stores")
+	}
+
+	running := hosts.Containers([]ContainerFilter{ContainerFilters.Running()}, NoSort)
+	if len(running) != 2 {
+		t.Errorf("expected 2 running containers, got %d", len(running))
+	}
+	if c := hosts.ContainerByID("c2"); c == nil || c.Host != "staging" {
+		t.Errorf("ContainerByID(c2) = %v, want container from staging", c)
+	}
+	if got := hosts.Hosts(); len(got) != 2 || got[0] != "default" || got[1] != "staging" {
+		t.Errorf("Hosts() = %v", got)
+	}
+}
+
+func TestMultiHostContainers_OperationsGoToTheOwningHost(t *testing.T) {
+	local := &hostContainersMock{containers: []*Container{hostContainer("c1", "web", "Up 2 hours")}}
+	remote := &hostContainersMock{containers: []*Container{hostContainer("c2", "db", "Up 2 hours")}}
+	hosts := NewMultiHostContainers(
+		ContainerHost{Name: "default", API: local},
+		ContainerHost{Name: "staging", API: remote},
+	)
+
+	_ = hosts.StopContainer("c2")
+	_ = hosts.Kill("c1")
+	_ = hosts.RestartContainer("c2")
+	_ = hosts.Rm("c1")
+	_ = hosts.PauseContainer("c2")
+	_ = hosts.UnpauseContainer("c2")
+	_ = hosts.RenameContainer("c1", "api")
+
+	if got := remote.ops; len(got) != 4 || got[0] != "stop c2" || got[1] != "restart c2" ||
+		got[2] != "pause c2" || got[3] != "unpause c2" {
+		t.Errorf("staging ops = %v", got)
+	}
+	if got := local.ops; len(got) != 3 || got[0] != "kill c1" || got[1] != "rm c1" || got[2] != "rename c1 api" {
+		t.Errorf("default ops = %v", got)
+	}
+	if host, ok := hosts.HostOf("c2"); !ok || host != "staging" {
+		t.Errorf("HostOf(c2) = %q, %v", host, ok)
+	}
+	if err := hosts.StopContainer("unknown"); err == nil {
+		t.Error("expected an error for a container no host knows")
+	}
+}
+
+func TestMultiHostContainers_RemoveAllStoppedContainersOnEveryHost(t *testing.T) {
+	local := &hostContainersMock{}
+	remote := &hostContainersMock{removeErr: errors.New("boom")}
+	other := &hostContainersMock{}
+	hosts := NewMultiHostContainers(
+		ContainerHost{Name: "default", API: local},
+		ContainerHost{Name: "staging", API: remote},
+		ContainerHost{Name: "prod", API: other},
+	)
+
+	count, err := hosts.RemoveAllStoppedContainers()
+	if count != 2 {
+		t.Errorf("expected 2 removed containers, got %d", count)
+	}
+	if err == nil || err.Error() != "staging: boom" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(other.ops) != 1 {
+		t.Error("a failing host must not stop the others from being cleaned up")
+	}
+}
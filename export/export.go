@@ -0,0 +1,229 @@
+// Package export takes snapshots of dry's views and prints them in a
+// format meant for scripts rather than for the terminal UI.
+package export
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/docker/formatter"
+)
+
+// API is the subset of the Docker API snapshots are taken from.
+type API interface {
+	docker.ContainerAPI
+	docker.ImageAPI
+	docker.NetworkAPI
+	docker.VolumesAPI
+	docker.SwarmAPI
+	docker.ComposeAPI
+}
+
+// Views lists the views a snapshot can be taken of.
+var Views = []string{"containers", "images", "networks", "volumes", "services", "stacks", "compose"}
+
+// Options tweaks what a snapshot holds.
+type Options struct {
+	// All includes stopped containers, as F2 does in the containers view.
+	All bool
+	// NoTrunc shows full IDs, images and commands.
+	NoTrunc bool
+	// RowFormat, when set, renders the rows instead of the view's columns.
+	RowFormat *formatter.RowFormat
+}
+
+// rowFormatFields maps the views whose rows can be rendered by a row
+// format to the fields the format can use.
+var rowFormatFields = map[string]map[string]string{
+	"containers": formatter.ContainerFields,
+	"images":     formatter.ImageFields,
+	"networks":   formatter.NetworkFields,
+	"volumes":    formatter.VolumeFields,
+}
+
+// IsRowFormat returns true if format is a row format template, such as
+// "table {{.Names}}\t{{.Status}}", rather than the name of an output format.
+func IsRowFormat(format string) bool {
+	return strings.Contains(format, "{{")
+}
+
+// ParseRowFormat parses a row format template for the given view.
+func ParseRowFormat(view, format string) (*formatter.RowFormat, error) {
+	fields, ok := rowFormatFields[view]
+	if !ok {
+		views := slices.Sorted(maps.Keys(rowFormatFields))
+		return nil, fmt.Errorf("view %q takes no row format, views that do: %s", view, strings.Join(views, ", "))
+	}
+	return formatter.ParseRowFormat(formatter.Format(format), fields)
+}
+
+// Snapshot is the content of a view: the titles of its columns and one
+// row of values per item, in the order the view lists them.
+type Snapshot struct {
+	View    string
+	Columns []string
+	Rows    [][]string
+}
+
+// Validate returns an error if the view or the format is not one a
+// snapshot can be taken of or written in. A row format is checked by
+// ParseRowFormat.
+func Validate(view, format string) error {
+	if !slices.Contains(Views, view) {
+		return fmt.Errorf("unknown view %q, valid views: %s", view, strings.Join(Views, ", "))
+	}
+	if !slices.Contains(Formats, format) && !IsRowFormat(format) {
+		return fmt.Errorf("unknown format %q, valid formats: %s", format, strings.Join(Formats, ", "))
+	}
+	return nil
+}
+
+// Take returns a snapshot of the given view.
+func Take(api API, view string, opts Options) (Snapshot, error) {
+	s := Snapshot{View: view}
+	trunc := !opts.NoTrunc
+	rf := opts.RowFormat
+	if rf != nil {
+		if _, ok := rowFormatFields[view]; !ok {
+			return s, fmt.Errorf("view %q takes no row format", view)
+		}
+	}
+	switch view {
+	case "containers":
+		var filters []docker.ContainerFilter
+		if !opts.All {
+			filters = append(filters, docker.ContainerFilters.Running())
+		}
+		s.Columns = []string{"CONTAINER", "IMAGE", "COMMAND", "STATUS", "PORTS", "NAMES"}
+		for _, c := range api.Containers(filters, docker.SortByContainerID) {
+			f := formatter.NewContainerFormatter(c, trunc)
+			s.Rows = append(s.Rows, row(rf, f, func() []string {
+				return []string{f.ID(), f.Image(), f.Command(), f.Status(), f.Ports(), f.Names()}
+			}))
+		}
+	case "images":
+		images, err := api.Images()
+		if err != nil {
+			return s, err
+		}
+		s.Columns = []string{"REPOSITORY", "TAG", "ID", "CREATED", "SIZE"}
+		for _, img := range images {
+			f := formatter.NewImageFormatter(img, trunc)
+			s.Rows = append(s.Rows, row(rf, f, func() []string {
+				return []string{f.Repository(), f.Tag(), f.ID(), f.CreatedSince(), f.Size()}
+			}))
+		}
+	case "networks":
+		networks, err := api.Networks()
+		if err != nil {
+			return s, err
+		}
+		s.Columns = []string{"ID", "NAME", "DRIVER", "CONTAINERS", "SCOPE", "SUBNET"}
+		for _, n := range networks {
+			f := formatter.NewNetworkFormatter(n, trunc)
+			s.Rows = append(s.Rows, row(rf, f, func() []string {
+				return []string{f.ID(), f.Name(), f.Driver(), f.Containers(), f.Scope(), f.Subnet()}
+			}))
+		}
+	case "volumes":
+		volumes, err := api.VolumeList(context.Background())
+		if err != nil {
+			return s, err
+		}
+		s.Columns = []string{"DRIVER", "NAME", "MOUNTPOINT"}
+		for _, v := range volumes {
+			f := formatter.NewVolumeFormatter(v)
+			s.Rows = append(s.Rows, row(rf, f, func() []string {
+				return []string{f.Driver(), f.Name(), f.Mountpoint()}
+			}))
+		}
+	case "services":
+		services, err := api.Services()
+		if err != nil {
+			return s, err
+		}
+		s.Columns = []string{"ID", "NAME", "REPLICAS", "IMAGE"}
+		for _, svc := range services {
+			id := svc.ID
+			if trunc {
+				id = docker.TruncateID(id)
+			}
+			s.Rows = append(s.Rows, []string{id, svc.Spec.Name, replicas(svc), serviceImage(svc)})
+		}
+	case "stacks":
+		stacks, err := api.Stacks()
+		if err != nil {
+			return s, err
+		}
+		s.Columns = []string{"NAME", "SERVICES", "NETWORKS", "CONFIGS", "SECRETS"}
+		for _, st := range stacks {
+			s.Rows = append(s.Rows, []string{
+				st.Name,
+				fmt.Sprintf("%d", st.Services),
+				fmt.Sprintf("%d", st.Networks),
+				fmt.Sprintf("%d", st.Configs),
+				fmt.Sprintf("%d", st.Secrets),
+			})
+		}
+	case "compose":
+		// The compose view interleaves each project with its services; a
+		// project row has a status where a service row has an image.
+		s.Columns = []string{"PROJECT", "SERVICE", "CONTAINERS", "RUNNING", "EXITED", "STATUS/IMAGE", "HEALTH", "PORTS"}
+		for _, p := range api.ComposeProjectsWithServices() {
+			s.Rows = append(s.Rows, []string{
+				p.Project.Name, "",
+				fmt.Sprintf("%d", p.Project.Containers),
+				fmt.Sprintf("%d", p.Project.Running),
+				fmt.Sprintf("%d", p.Project.Exited),
+				string(p.Project.Status), "", "",
+			})
+			for _, svc := range p.Services {
+				s.Rows = append(s.Rows, []string{
+					p.Project.Name, svc.Name,
+					fmt.Sprintf("%d", svc.Containers),
+					fmt.Sprintf("%d", svc.Running),
+					fmt.Sprintf("%d", svc.Exited),
+					svc.Image, svc.Health, svc.Ports,
+				})
+			}
+		}
+	default:
+		return s, fmt.Errorf("unknown view %q, valid views: %s", view, strings.Join(Views, ", "))
+	}
+	if rf != nil {
+		s.Columns = rf.Headers()
+	}
+	return s, nil
+}
+
+// row returns the values of an item: the row format rendered for the
+// item's formatter when there is one, the view's columns otherwise.
+func row(rf *formatter.RowFormat, f any, columns func() []string) []string {
+	if rf != nil {
+		return rf.Row(f)
+	}
+	return columns()
+}
+
+func replicas(s swarm.Service) string {
+	switch {
+	case s.Spec.Mode.Replicated != nil && s.Spec.Mode.Replicated.Replicas != nil:
+		return fmt.Sprintf("%d", *s.Spec.Mode.Replicated.Replicas)
+	case s.Spec.Mode.Replicated != nil:
+		return "0"
+	default:
+		return "global"
+	}
+}
+
+func serviceImage(s swarm.Service) string {
+	if s.Spec.TaskTemplate.ContainerSpec != nil {
+		return s.Spec.TaskTemplate.ContainerSpec.Image
+	}
+	return ""
+}
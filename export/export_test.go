@@ -0,0 +1,145 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/moncho/dry/mocks"
+)
+
+func TestTake_Containers(t *testing.T) {
+	daemon := &mocks.DockerDaemonMock{}
+
+	running, err := Take(daemon, "containers", Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(running.Rows) != 10 {
+		t.Errorf("expected the 10 running containers, got %d", len(running.Rows))
+	}
+	all, _ := Take(daemon, "containers", Options{All: true})
+	if len(all.Rows) != 20 {
+		t.Errorf("expected 20 containers with All, got %d", len(all.Rows))
+	}
+	if len(all.Columns) != len(all.Rows[0]) {
+		t.Errorf("expected a value per column, got %d columns and %d values", len(all.Columns), len(all.Rows[0]))
+	}
+}
+
+func TestTake_ComposeInterleavesProjectsAndServices(t *testing.T) {
+	s, err := Take(&mocks.DockerDaemonMock{}, "compose", Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := s.Rows[0]; got[0] != "webapp" || got[1] != "" || got[5] != "running" {
+		t.Errorf("expected the webapp project row first, got %v", got)
+	}
+	if got := s.Rows[1]; got[0] != "webapp" || got[1] != "api" || got[5] != "api:latest" {
+		t.Errorf("expected the api service row after its project, got %v", got)
+	}
+}
+
+func TestTake_UnknownView(t *testing.T) {
+	if _, err := Take(&mocks.DockerDaemonMock{}, "pods", Options{}); err == nil {
+		t.Error("expected an error for an unknown view")
+	}
+	if err := Validate("containers", "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if err := Validate("images", "yaml"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	s := Snapshot{
+		View:    "compose",
+		Columns: []string{"PROJECT", "STATUS/IMAGE", "PORTS"},
+		Rows: [][]string{
+			{"webapp", "running", ""},
+			{"webapp", "api:latest", "0.0.0.0:8080->8080/tcp, 443/tcp"},
+		},
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"json", `[
+  {
+    "project": "webapp",
+    "status_image": "running",
+    "ports": ""
+  },
+  {
+    "project": "webapp",
+    "status_image": "api:latest",
+    "ports": "0.0.0.0:8080->8080/tcp, 443/tcp"
+  }
+]
+`},
+		{"yaml", `- project: webapp
+  status_image: running
+  ports: ""
+- project: webapp
+  status_image: api:latest
+  ports: 0.0.0.0:8080->8080/tcp, 443/tcp
+`},
+		{"csv", `PROJECT,STATUS/IMAGE,PORTS
+webapp,running,
+webapp,api:latest,"0.0.0.0:8080->8080/tcp, 443/tcp"
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, s, tt.format); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, s, "table"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "PROJECT") {
+		t.Errorf("expected a header and two rows, got %q", buf.String())
+	}
+}
+
+func TestTake_RowFormat(t *testing.T) {
+	rf, err := ParseRowFormat("containers", `{{.ID}}\t{{.Names}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s, err := Take(&mocks.DockerDaemonMock{}, "containers", Options{RowFormat: rf})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.Columns) != 2 || s.Columns[0] != "CONTAINER ID" || s.Columns[1] != "NAMES" {
+		t.Errorf("unexpected columns %q", s.Columns)
+	}
+	if got := s.Rows[0]; len(got) != 2 || got[1] != "Name" {
+		t.Errorf("unexpected row %q", got)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, s, `{{.ID}}\t{{.Names}}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first, _, _ := strings.Cut(buf.String(), "\n"); first != s.Rows[0][0]+"\tName" {
+		t.Errorf("expected bare lines without a header, got %q", first)
+	}
+
+	if _, err := ParseRowFormat("stacks", "{{.Name}}"); err == nil {
+		t.Error("expected an error for a view that takes no row format")
+	}
+	if err := Validate("images", "table {{.Repository}}"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
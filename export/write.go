@@ -0,0 +1,153 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/moncho/dry/docker/formatter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats lists the output formats a snapshot can be written in.
+var Formats = []string{"table", "json", "yaml", "csv"}
+
+// Write writes the snapshot to w in the given format. Structured formats
+// get one object per row, keyed by the lower-cased column titles. Given a
+// row format template, the rows are written as a table when the template
+// starts with "table" and as bare lines, like docker ps --format, when not.
+func Write(w io.Writer, s Snapshot, format string) error {
+	if IsRowFormat(format) {
+		if formatter.Format(format).IsTable() {
+			return writeTable(w, s)
+		}
+		return writeLines(w, s)
+	}
+	switch format {
+	case "table":
+		return writeTable(w, s)
+	case "json":
+		return writeJSON(w, s)
+	case "yaml":
+		return writeYAML(w, s)
+	case "csv":
+		return writeCSV(w, s)
+	}
+	return fmt.Errorf("unknown format %q, valid formats: %s", format, strings.Join(Formats, ", "))
+}
+
+// keys returns the keys the columns are given in structured formats:
+// "STATUS/IMAGE" becomes "status_image".
+func (s Snapshot) keys() []string {
+	keys := make([]string, len(s.Columns))
+	for i, c := range s.Columns {
+		keys[i] = strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+				return r
+			case r >= 'A' && r <= 'Z':
+				return r + 'a' - 'A'
+			}
+			return '_'
+		}, c)
+	}
+	return keys
+}
+
+func writeTable(w io.Writer, s Snapshot) error {
+	t := tabwriter.NewWriter(w, 20, 1, 3, ' ', 0)
+	fmt.Fprintln(t, strings.Join(s.Columns, "\t"))
+	for _, row := range s.Rows {
+		fmt.Fprintln(t, strings.Join(row, "\t"))
+	}
+	return t.Flush()
+}
+
+func writeLines(w io.Writer, s Snapshot) error {
+	for _, row := range s.Rows {
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, s Snapshot) error {
+	c := csv.NewWriter(w)
+	if err := c.Write(s.Columns); err != nil {
+		return err
+	}
+	if err := c.WriteAll(s.Rows); err != nil {
+		return err
+	}
+	return c.Error()
+}
+
+// record is a row with its keys kept in column order when marshalled.
+type record struct {
+	keys   []string
+	values []string
+}
+
+func (r record) MarshalJSON() ([]byte, error) {
+	// Ports and commands are full of <, > and &, keep them readable.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	buf.WriteByte('{')
+	for i, k := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := enc.Encode(k); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(':')
+		if err := enc.Encode(r.values[i]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (r record) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i, k := range r.keys {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: k},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: r.values[i], Tag: "!!str"},
+		)
+	}
+	return node, nil
+}
+
+func (s Snapshot) records() []record {
+	keys := s.keys()
+	records := make([]record, len(s.Rows))
+	for i, row := range s.Rows {
+		records[i] = record{keys: keys, values: row}
+	}
+	return records
+}
+
+func writeJSON(w io.Writer, s Snapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s.records())
+}
+
+func writeYAML(w io.Writer, s Snapshot) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(s.records()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,108 @@
+package logline
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Term is a condition on a field of a record, such as level>=warn or
+// trace_id=abc.
+type Term struct {
+	Key   string
+	Op    string
+	Value string
+}
+
+// termOps lists the operators of terms, longest first.
+var termOps = []string{">=", "<=", "!=", "=", ">", "<", "~"}
+
+// ParseTerm parses a term: a key, one of the operators =, !=, ~ (contains),
+// >, >=, < and <=, and a value. It returns false for any other text.
+func ParseTerm(s string) (Term, bool) {
+	i := strings.IndexAny(s, "=!~<>")
+	if i <= 0 || !isKey(s[:i]) {
+		return Term{}, false
+	}
+	for _, op := range termOps {
+		if value, ok := strings.CutPrefix(s[i:], op); ok && value != "" {
+			return Term{Key: s[:i], Op: op, Value: value}, true
+		}
+	}
+	return Term{}, false
+}
+
+// Match returns true if the record has the field of the term with a value
+// satisfying it. Levels compare by severity, numbers as numbers and
+// anything else as text.
+func (t Term) Match(r Record) bool {
+	value, ok := r.Value(t.Key)
+	if !ok {
+		return t.Op == "!="
+	}
+	var cmp int
+	if contains(levelKeys, strings.ToLower(t.Key)) {
+		want, known := ParseLevel(t.Value)
+		if !known {
+			return false
+		}
+		cmp = int(r.Level) - int(want)
+	} else if a, b, numbers := parseNumbers(value, t.Value); numbers {
+		cmp = compareFloats(a, b)
+	} else {
+		cmp = strings.Compare(value, t.Value)
+	}
+	switch t.Op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "~":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(t.Value))
+	}
+	return false
+}
+
+func parseNumbers(a, b string) (float64, float64, bool) {
+	x, err := strconv.ParseFloat(a, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	y, err := strconv.ParseFloat(b, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// SplitTerms separates the terms of a filter from the rest of its words,
+// returned joined by single spaces.
+func SplitTerms(filter string) ([]Term, string) {
+	var terms []Term
+	var rest []string
+	for _, word := range strings.Fields(filter) {
+		if t, ok := ParseTerm(word); ok {
+			terms = append(terms, t)
+			continue
+		}
+		rest = append(rest, word)
+	}
+	return terms, strings.Join(rest, " ")
+}
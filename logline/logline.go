@@ -0,0 +1,280 @@
+// Package logline parses structured log lines, written as JSON objects or
+// as logfmt key=value pairs, and filters them by their fields.
+package logline
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Level is the severity of a log record.
+type Level int
+
+// Levels, from the least to the most severe.
+const (
+	LevelUnknown Level = iota
+	LevelTrace
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+var levelNames = []string{"", "TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+func (l Level) String() string {
+	if l < LevelUnknown || l > LevelFatal {
+		return ""
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parses the level names and numbers used by common logging
+// libraries: warn, WARNING, err, E, 40 (as in bunyan and pino)...
+func ParseLevel(s string) (Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace", "trc", "t", "10":
+		return LevelTrace, true
+	case "debug", "dbg", "d", "20":
+		return LevelDebug, true
+	case "info", "information", "inf", "i", "notice", "30":
+		return LevelInfo, true
+	case "warn", "warning", "wrn", "w", "40":
+		return LevelWarn, true
+	case "error", "err", "e", "50":
+		return LevelError, true
+	case "fatal", "ftl", "f", "critical", "crit", "panic", "emergency", "alert", "60":
+		return LevelFatal, true
+	}
+	return LevelUnknown, false
+}
+
+// Field is a key and its value, as found in a log line.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Record is a structured log line.
+type Record struct {
+	Time    string
+	Level   Level
+	Message string
+	// Fields are the other fields of the line, in the order they were
+	// written.
+	Fields []Field
+}
+
+// Keys holding the time, the level and the message of a record.
+var (
+	timeKeys    = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+	levelKeys   = []string{"level", "lvl", "severity", "loglevel", "@level"}
+	messageKeys = []string{"msg", "message", "@message"}
+)
+
+// Parse parses a log line written as a JSON object or as logfmt, optionally
+// preceded by the timestamp Docker adds to log lines. It returns false for
+// any other line.
+func Parse(line string) (Record, bool) {
+	line = strings.TrimSpace(line)
+	stamp := ""
+	if first, rest, ok := strings.Cut(line, " "); ok {
+		if _, err := time.Parse(time.RFC3339Nano, first); err == nil {
+			stamp, line = first, strings.TrimSpace(rest)
+		}
+	}
+	var fields []Field
+	var ok bool
+	if strings.HasPrefix(line, "{") {
+		fields, ok = parseJSON(line)
+	} else {
+		fields, ok = parseLogfmt(line)
+	}
+	if !ok {
+		return Record{}, false
+	}
+	r := Record{Time: stamp}
+	for _, f := range fields {
+		key := strings.ToLower(f.Key)
+		switch {
+		case r.Time == "" && contains(timeKeys, key):
+			r.Time = f.Value
+		case r.Level == LevelUnknown && contains(levelKeys, key):
+			level, known := ParseLevel(f.Value)
+			if !known {
+				r.Fields = append(r.Fields, f)
+			}
+			r.Level = level
+		case r.Message == "" && contains(messageKeys, key):
+			r.Message = f.Value
+		default:
+			r.Fields = append(r.Fields, f)
+		}
+	}
+	return r, true
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJSON parses a JSON object, keeping the order of its fields. Values
+// that are not strings are kept as compact JSON.
+func parseJSON(line string) ([]Field, bool) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, false
+	}
+	var fields []Field
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, _ := t.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, false
+		}
+		fields = append(fields, Field{Key: key, Value: jsonValue(raw)})
+	}
+	if t, err := dec.Token(); err != nil || t != json.Delim('}') {
+		return nil, false
+	}
+	return fields, true
+}
+
+func jsonValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var b bytes.Buffer
+	if err := json.Compact(&b, raw); err != nil {
+		return string(raw)
+	}
+	return b.String()
+}
+
+// parseLogfmt parses key=value pairs, values being quoted when they hold
+// spaces. Plain text with an = in it is not logfmt: every word has to be a
+// pair and one of them has to be a level or a message.
+func parseLogfmt(line string) ([]Field, bool) {
+	var fields []Field
+	known := false
+	for line != "" {
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || !isKey(line[:eq]) {
+			return nil, false
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := closingQuote(line)
+			if end < 0 {
+				return nil, false
+			}
+			v, err := strconv.Unquote(line[:end+1])
+			if err != nil {
+				return nil, false
+			}
+			value, line = v, line[end+1:]
+			if line != "" && line[0] != ' ' {
+				return nil, false
+			}
+		} else {
+			value, line, _ = strings.Cut(line, " ")
+		}
+		fields = append(fields, Field{Key: key, Value: value})
+		lower := strings.ToLower(key)
+		known = known || contains(levelKeys, lower) || contains(messageKeys, lower)
+		line = strings.TrimLeft(line, " ")
+	}
+	return fields, known && len(fields) > 1
+}
+
+// closingQuote returns the index of the quote closing the string s starts
+// with, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func isKey(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-.@/", r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// String renders the record as "time level msg key=val ...".
+func (r Record) String() string {
+	var b strings.Builder
+	if r.Time != "" {
+		b.WriteString(r.Time)
+		b.WriteByte(' ')
+	}
+	if r.Level != LevelUnknown {
+		b.WriteString(padLevel(r.Level.String()))
+		b.WriteByte(' ')
+	}
+	b.WriteString(r.Message)
+	for _, f := range r.Fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(quote(f.Value))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+func padLevel(s string) string {
+	return s + strings.Repeat(" ", len("ERROR")-min(len(s), len("ERROR")))
+}
+
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Value returns the value of the field with the given key, the time,
+// level and message being found under any of their usual keys.
+func (r Record) Value(key string) (string, bool) {
+	lower := strings.ToLower(key)
+	switch {
+	case contains(timeKeys, lower):
+		return r.Time, r.Time != ""
+	case contains(levelKeys, lower):
+		return strings.ToLower(r.Level.String()), r.Level != LevelUnknown
+	case contains(messageKeys, lower):
+		return r.Message, true
+	}
+	for _, f := range r.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
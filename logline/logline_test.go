@@ -0,0 +1,94 @@
+package logline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want Record
+		ok   bool
+	}{
+		{
+			`{"time":"10:00","level":"warn","msg":"slow query","ms":1200,"tags":["db"]}`,
+			Record{Time: "10:00", Level: LevelWarn, Message: "slow query",
+				Fields: []Field{{"ms", "1200"}, {"tags", `["db"]`}}},
+			true,
+		},
+		{
+			`{"level":50,"message":"boom","trace_id":"abc"}`,
+			Record{Level: LevelError, Message: "boom", Fields: []Field{{"trace_id", "abc"}}},
+			true,
+		},
+		{
+			`ts=10:00 lvl=info msg="user logged in" user=ana`,
+			Record{Time: "10:00", Level: LevelInfo, Message: "user logged in",
+				Fields: []Field{{"user", "ana"}}},
+			true,
+		},
+		{
+			`2026-10-17T10:00:00.123456789Z level=debug msg=tick`,
+			Record{Time: "2026-10-17T10:00:00.123456789Z", Level: LevelDebug, Message: "tick"},
+			true,
+		},
+		{`GET /health 200`, Record{}, false},
+		{`retrying with timeout=5s`, Record{}, false},
+		{`a=1 b=2`, Record{}, false},
+		{`{"msg": "truncated`, Record{}, false},
+		{`level=info msg="unterminated`, Record{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.line)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRecord_String(t *testing.T) {
+	r := Record{Time: "10:00", Level: LevelInfo, Message: "user logged in",
+		Fields: []Field{{"user", "ana"}, {"agent", "curl 8.0"}}}
+	want := `10:00 INFO  user logged in user=ana agent="curl 8.0"`
+	if got := r.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestTerm_Match(t *testing.T) {
+	r, _ := Parse(`{"level":"error","msg":"boom","trace_id":"abc123","status":503}`)
+	tests := []struct {
+		term string
+		want bool
+	}{
+		{"level>=warn", true},
+		{"lvl<warn", false},
+		{"level=ERROR", true},
+		{"trace_id=abc123", true},
+		{"trace_id~ABC", true},
+		{"trace_id!=abc123", false},
+		{"status>=500", true},
+		{"status<404", false},
+		{"user!=ana", true},
+		{"user=ana", false},
+		{"msg=boom", true},
+	}
+	for _, tt := range tests {
+		term, ok := ParseTerm(tt.term)
+		if !ok {
+			t.Fatalf("ParseTerm(%q) failed", tt.term)
+		}
+		if got := term.Match(r); got != tt.want {
+			t.Errorf("%s matched %v, want %v", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestSplitTerms(t *testing.T) {
+	terms, rest := SplitTerms("level>=warn  connection refused trace_id=abc =x")
+	want := []Term{{"level", ">=", "warn"}, {"trace_id", "=", "abc"}}
+	if !reflect.DeepEqual(terms, want) || rest != "connection refused =x" {
+		t.Errorf("SplitTerms = %v, %q", terms, rest)
+	}
+}
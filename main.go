@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"os/signal"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -15,6 +20,9 @@ import (
 	"github.com/moncho/dry/app"
 	"github.com/moncho/dry/appui"
 	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/docker/formatter"
+	dryexport "github.com/moncho/dry/export"
+	"github.com/moncho/dry/metrics"
 	"github.com/moncho/dry/version"
 	log "github.com/sirupsen/logrus"
 )
@@ -27,16 +35,46 @@ type options struct {
 	Profile   bool   `short:"p" long:"profile" description:"Enable profiling"`
 	Version   bool   `short:"v" long:"version" description:"Dry version"`
 	Splash    int    `short:"w" long:"splash" description:"Show loading screen for N seconds (max 10)" default:"0"`
-	Theme     string `short:"T" long:"theme" description:"Color theme (dark, light)" default:"dark"`
+	Theme     string `short:"T" long:"theme" description:"Color theme (dark, light), dark if not set"`
 	Workspace bool   `long:"workspace" description:"Enable experimental Phase 1 workspace layout"`
+	Config    string `long:"config" description:"Configuration file, ~/.config/dry/config.toml if not set"`
+	// turn off what the configuration file turns on
+	NoWorkspace bool `long:"no-workspace" description:"Disable the workspace layout, even if enabled in the configuration file"`
+	// keep monitor stats on disk
+	MetricsHistory   bool `long:"metrics-history" description:"Keep monitor stats on disk, ~/.local/share/dry/metrics unless metrics-dir is set in the configuration file"`
+	NoMetricsHistory bool `long:"no-metrics-history" description:"Do not keep monitor stats on disk, even if enabled in the configuration file"`
+	// serve container stats to Prometheus
+	MetricsListen string `long:"metrics-listen" description:"Serve container stats and Docker counts in the Prometheus format on the given address, such as :9323"`
+	Headless      bool   `long:"headless" description:"Only serve metrics, without the terminal UI; needs --metrics-listen"`
 	// Docker-related properties
 	DockerHost      string `short:"H" long:"docker_host" description:"Docker Host"`
 	DockerCertPath  string `short:"c" long:"docker_certpath" description:"Docker cert path"`
 	DockerTLSVerify string `short:"t" long:"docker_tls" description:"Docker TLS verify"`
 }
 
-func config(opts options) (app.Config, error) {
-	var cfg app.Config
+// exportOptions are the flags of the export command.
+type exportOptions struct {
+	View    string `long:"view" description:"View to print: containers, images, networks, volumes, services, stacks or compose" default:"containers"`
+	Format  string `long:"format" description:"Output format: table, json, yaml, csv or a Go template such as 'table {{.Names}}\t{{.Status}}'" default:"table"`
+	All     bool   `short:"a" long:"all" description:"Include stopped containers"`
+	NoTrunc bool   `long:"no-trunc" description:"Do not truncate IDs, images and commands"`
+}
+
+// config builds dry's configuration from the given flags and the settings
+// read from the configuration file; flags win over the file.
+func config(opts options, file app.FileConfig) (app.Config, error) {
+	cfg := app.Config{
+		MonitorRefreshRate: file.RefreshRate,
+		StartView:          file.View,
+		LogTail:            file.LogTail,
+		Compact:            file.Compact,
+		Sort:               file.Sort,
+		Keys:               file.Keys,
+		Hosts:              file.Hosts,
+		Columns:            file.Columns,
+		Formats:            file.Format,
+		Views:              file.Views,
+	}
 	if opts.DockerHost == "" {
 		if os.Getenv("DOCKER_HOST") == "" {
 			log.Printf(
@@ -69,10 +107,120 @@ func config(opts options) (app.Config, error) {
 		}
 		cfg.MonitorRefreshRate = refreshRate
 	}
-	cfg.WorkspaceMode = opts.Workspace
+	workspace, err := boolFlag("workspace", opts.Workspace, opts.NoWorkspace, file.Workspace)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.WorkspaceMode = workspace
+	metricsHistory, err := boolFlag("metrics-history", opts.MetricsHistory, opts.NoMetricsHistory, file.MetricsHistory)
+	if err != nil {
+		return cfg, err
+	}
+	if metricsHistory {
+		cfg.MetricsDir = file.MetricsDir
+		if cfg.MetricsDir == "" {
+			cfg.MetricsDir = metrics.DefaultDir()
+		}
+		// The configuration file checked the retention is valid.
+		cfg.MetricsRetention, _ = time.ParseDuration(file.MetricsRetention)
+	}
+	cfg.Alerts = file.Alerts
+	cfg.AlertBell = file.AlertBell
+	cfg.AlertNotify = file.AlertNotify
 	return cfg, nil
 }
 
+// boolFlag returns the value of a setting turned on with --name and off
+// with --no-name, the one of the configuration file if neither is given.
+func boolFlag(name string, on, off, file bool) (bool, error) {
+	switch {
+	case on && off:
+		return false, fmt.Errorf("--%s and --no-%s cannot be used together", name, name)
+	case on:
+		return true, nil
+	case off:
+		return false, nil
+	}
+	return file, nil
+}
+
+// export connects to Docker and prints one snapshot of the requested view.
+func export(cfg app.Config, opts exportOptions) error {
+	if err := dryexport.Validate(opts.View, opts.Format); err != nil {
+		return err
+	}
+	// A template given on the command line wins over the one configured
+	// for the view.
+	template := cfg.Formats[opts.View]
+	if dryexport.IsRowFormat(opts.Format) {
+		template = opts.Format
+	}
+	var rowFormat *formatter.RowFormat
+	if template != "" {
+		var err error
+		if rowFormat, err = dryexport.ParseRowFormat(opts.View, template); err != nil {
+			return err
+		}
+	}
+	daemon, err := docker.ConnectToDaemon(docker.Env{
+		DockerHost:      cfg.DockerHost,
+		DockerCertPath:  cfg.DockerCertPath,
+		DockerTLSVerify: cfg.DockerTLSVerify,
+	})
+	if err != nil {
+		return err
+	}
+	defer daemon.Close()
+	snapshot, err := dryexport.Take(daemon, opts.View, dryexport.Options{
+		All:       opts.All,
+		NoTrunc:   opts.NoTrunc,
+		RowFormat: rowFormat,
+	})
+	if err != nil {
+		return err
+	}
+	return dryexport.Write(os.Stdout, snapshot, opts.Format)
+}
+
+// serveMetrics serves the metrics of the Docker host dry connects to on
+// the given address, under /metrics, until the returned function is
+// called. Errors of the metrics server once started are sent to errs.
+//
+// The exporter has a connection of its own, so that it keeps reporting on
+// the host dry was started with when the terminal UI switches context.
+// Its events stream only refreshes the containers of that connection.
+func serveMetrics(cfg app.Config, addr string, errs chan<- error) (func(), error) {
+	daemon, err := docker.ConnectToDaemon(docker.Env{
+		DockerHost:      cfg.DockerHost,
+		DockerCertPath:  cfg.DockerCertPath,
+		DockerTLSVerify: cfg.DockerTLSVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		_ = daemon.Close()
+		return nil, err
+	}
+	exporter := metrics.NewExporter(daemon)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+	go exporter.Run(ctx)
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+	return func() {
+		cancel()
+		_ = srv.Close()
+		_ = daemon.Close()
+	}, nil
+}
+
 // getBool returns false if the given string looks like you mean
 // false, true otherwise.
 func getBool(key string) bool {
@@ -94,7 +242,13 @@ func main() {
 
 	// parse flags
 	var opts options
+	var exportOpts exportOptions
 	parser := flags.NewParser(&opts, flags.Default)
+	parser.SubcommandsOptional = true
+	_, _ = parser.AddCommand("export",
+		"Print a snapshot of a view and exit",
+		"Prints what the given view would show, in a format meant for scripts, and exits.",
+		&exportOpts)
 	_, err := parser.Parse()
 	if err != nil {
 		flagError := err.(*flags.Error)
@@ -123,14 +277,74 @@ func main() {
 		}()
 	}
 
-	cfg, err := config(opts)
+	// A missing default configuration file is fine, a missing file that
+	// was asked for explicitly is not.
+	configFile := opts.Config
+	if configFile == "" {
+		configFile = app.DefaultConfigFile()
+	} else if _, err := os.Stat(configFile); err != nil {
+		log.Printf("Could not load configuration: %s", err)
+		return
+	}
+	file, err := app.LoadConfigFile(configFile)
+	if err != nil {
+		log.Printf("Could not load configuration: %s", err)
+		return
+	}
+
+	cfg, err := config(opts, file)
 	if err != nil {
 		log.Println(err.Error())
 		return
 	}
+	cfg.ConfigFile = configFile
+
+	if parser.Active != nil && parser.Active.Name == "export" {
+		if err := export(cfg, exportOpts); err != nil {
+			log.Printf("Could not export %s: %s", exportOpts.View, err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	if !appui.SetThemeByName(opts.Theme) {
-		log.Printf("Unknown theme %q, valid options: dark, light", opts.Theme)
+	if opts.Headless && opts.MetricsListen == "" {
+		log.Print("--headless needs --metrics-listen")
+		return
+	}
+	if opts.MetricsListen != "" {
+		// Errors of the metrics server are only reported when headless,
+		// the terminal UI owning the screen otherwise.
+		errs := make(chan error, 1)
+		stop, err := serveMetrics(cfg, opts.MetricsListen, errs)
+		if err != nil {
+			log.Printf("Could not serve metrics: %s", err)
+			return
+		}
+		defer stop()
+		if opts.Headless {
+			log.Printf("Serving metrics on %s/metrics", opts.MetricsListen)
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			select {
+			case err := <-errs:
+				log.Printf("Could not serve metrics: %s", err)
+				stop()
+				os.Exit(1)
+			case <-signals:
+			}
+			return
+		}
+	}
+
+	theme := opts.Theme
+	if theme == "" {
+		theme = file.Theme
+	}
+	if theme == "" {
+		theme = "dark"
+	}
+	if !appui.SetThemeByName(theme) {
+		log.Printf("Unknown theme %q, valid options: dark, light", theme)
 		return
 	}
 	appui.InitStyles()
@@ -0,0 +1,351 @@
+package metrics
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moncho/dry/docker"
+)
+
+// ExporterDaemon is what the exporter needs from the Docker daemon.
+type ExporterDaemon interface {
+	Containers(filters []docker.ContainerFilter, mode docker.SortMode) []*docker.Container
+	StatsChannel(container *docker.Container) (*docker.StatsChannel, error)
+	Images() ([]image.Summary, error)
+	VolumeList(ctx context.Context) ([]volume.Volume, error)
+	Events(ctx context.Context) (<-chan events.Message, error)
+	Refresh(notify func(error))
+}
+
+// exporterRefresh is how often the exporter looks for containers started
+// or stopped.
+const exporterRefresh = 10 * time.Second
+
+// The events stream is reconnected after a delay doubling on each attempt
+// that fails, from exporterReconnect up to exporterReconnectMax.
+const (
+	exporterReconnect    = 2 * time.Second
+	exporterReconnectMax = time.Minute
+)
+
+// Exporter serves, in the Prometheus text format, the stats of the running
+// containers, as the monitor sees them, the number of containers by state,
+// of images and of volumes, and the number of Docker events by type and
+// action since it started.
+type Exporter struct {
+	daemon ExporterDaemon
+	// reconnect is the first delay before reconnecting the events
+	// stream, exporterReconnect if zero.
+	reconnect time.Duration
+
+	mu      sync.Mutex
+	stats   map[string]*docker.Stats
+	streams map[string]*statsStream
+	events  map[eventKey]uint64
+}
+
+// statsStream is the stats stream of a container.
+type statsStream struct {
+	cancel context.CancelFunc
+}
+
+type eventKey struct {
+	typ    string
+	action string
+}
+
+// NewExporter creates an exporter of the stats of the given daemon.
+func NewExporter(daemon ExporterDaemon) *Exporter {
+	return &Exporter{
+		daemon:  daemon,
+		stats:   make(map[string]*docker.Stats),
+		streams: make(map[string]*statsStream),
+		events:  make(map[eventKey]uint64),
+	}
+}
+
+// Run streams the stats of the running containers and counts Docker events
+// until the context is done. The events stream is reconnected whenever it
+// fails or ends, the stats being refreshed meanwhile.
+func (e *Exporter) Run(ctx context.Context) {
+	e.syncContainers(ctx)
+	ticker := time.NewTicker(exporterRefresh)
+	defer ticker.Stop()
+	defer e.stopAll()
+
+	var events <-chan events.Message
+	eventsCancel := func() {}
+	defer func() { eventsCancel() }()
+	first := cmp.Or(e.reconnect, exporterReconnect)
+	delay := first
+	retry := time.NewTimer(0)
+	defer retry.Stop()
+	for {
+		select {
+		case <-retry.C:
+			eventsCtx, cancel := context.WithCancel(ctx)
+			ch, err := e.daemon.Events(eventsCtx)
+			if err != nil {
+				cancel()
+				retry.Reset(delay)
+				delay = min(2*delay, exporterReconnectMax)
+				continue
+			}
+			events, eventsCancel = ch, cancel
+		case ev, ok := <-events:
+			if !ok {
+				eventsCancel()
+				events = nil
+				retry.Reset(first)
+				delay = first
+				continue
+			}
+			e.countEvent(ev)
+		case <-ticker.C:
+			e.syncContainers(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (e *Exporter) countEvent(ev events.Message) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events[eventKey{typ: string(ev.Type), action: string(ev.Action)}]++
+}
+
+// syncContainers refreshes the containers of the daemon, streaming the
+// stats of the ones started and stopping those of the ones gone.
+func (e *Exporter) syncContainers(ctx context.Context) {
+	done := make(chan struct{})
+	e.daemon.Refresh(func(error) { close(done) })
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return
+	}
+	running := e.daemon.Containers([]docker.ContainerFilter{docker.ContainerFilters.Running()}, docker.NoSort)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	seen := make(map[string]bool, len(running))
+	for _, c := range running {
+		seen[c.ID] = true
+		if _, ok := e.streams[c.ID]; ok {
+			continue
+		}
+		sc, err := e.daemon.StatsChannel(c)
+		if err != nil {
+			continue
+		}
+		statsCtx, cancel := context.WithCancel(ctx)
+		stream := &statsStream{cancel: cancel}
+		e.streams[c.ID] = stream
+		go e.readStats(c.ID, stream, sc.Start(statsCtx))
+	}
+	for id, stream := range e.streams {
+		if !seen[id] {
+			stream.cancel()
+			delete(e.streams, id)
+			delete(e.stats, id)
+		}
+	}
+}
+
+// readStats keeps the last stats read from the stream of a container,
+// until the stream ends or is replaced.
+func (e *Exporter) readStats(id string, stream *statsStream, ch <-chan *docker.Stats) {
+	for s := range ch {
+		if s.Error != nil || !e.setStats(id, stream, s) {
+			break
+		}
+	}
+	stream.cancel()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// A container whose stream ended is streamed again once listed as
+	// running.
+	if e.streams[id] == stream {
+		delete(e.streams, id)
+		delete(e.stats, id)
+	}
+}
+
+// setStats keeps the stats read from a stream, returning false if the
+// stream is no longer the one of the container.
+func (e *Exporter) setStats(id string, stream *statsStream, s *docker.Stats) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.streams[id] != stream {
+		return false
+	}
+	e.stats[id] = s
+	return true
+}
+
+func (e *Exporter) stopAll() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id, stream := range e.streams {
+		stream.cancel()
+		delete(e.streams, id)
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.Write(w)
+}
+
+// Write writes the metrics in the Prometheus text format.
+func (e *Exporter) Write(w io.Writer) {
+	p := promWriter{w: w}
+	e.writeContainerStats(&p)
+	e.writeDaemonCounts(&p)
+	e.writeEvents(&p)
+}
+
+// containerMetric is a metric of the stats of a container.
+type containerMetric struct {
+	name  string
+	typ   string
+	help  string
+	value func(*docker.Stats) float64
+}
+
+var containerMetrics = []containerMetric{
+	{"dry_container_cpu_percent", "gauge", "CPU used by the container, in percent of one CPU.",
+		func(s *docker.Stats) float64 { return s.CPUPercentage }},
+	{"dry_container_memory_usage_bytes", "gauge", "Memory used by the container.",
+		func(s *docker.Stats) float64 { return s.Memory }},
+	{"dry_container_memory_limit_bytes", "gauge", "Memory limit of the container.",
+		func(s *docker.Stats) float64 { return s.MemoryLimit }},
+	{"dry_container_memory_percent", "gauge", "Memory used by the container, in percent of its limit.",
+		func(s *docker.Stats) float64 { return s.MemoryPercentage }},
+	{"dry_container_network_receive_bytes_total", "counter", "Bytes received by the container.",
+		func(s *docker.Stats) float64 { return s.NetworkRx }},
+	{"dry_container_network_transmit_bytes_total", "counter", "Bytes sent by the container.",
+		func(s *docker.Stats) float64 { return s.NetworkTx }},
+	{"dry_container_block_read_bytes_total", "counter", "Bytes read from block devices by the container.",
+		func(s *docker.Stats) float64 { return s.BlockRead }},
+	{"dry_container_block_write_bytes_total", "counter", "Bytes written to block devices by the container.",
+		func(s *docker.Stats) float64 { return s.BlockWrite }},
+	{"dry_container_pids", "gauge", "Processes running in the container.",
+		func(s *docker.Stats) float64 { return float64(s.PidsCurrent) }},
+}
+
+func (e *Exporter) writeContainerStats(p *promWriter) {
+	e.mu.Lock()
+	stats := make([]*docker.Stats, 0, len(e.stats))
+	for _, s := range e.stats {
+		stats = append(stats, s)
+	}
+	e.mu.Unlock()
+	slices.SortFunc(stats, func(a, b *docker.Stats) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, m := range containerMetrics {
+		p.header(m.name, m.typ, m.help)
+		for _, s := range stats {
+			p.sample(m.name, m.value(s), "id", s.CID, "name", strings.TrimPrefix(s.Name, "/"))
+		}
+	}
+}
+
+func (e *Exporter) writeDaemonCounts(p *promWriter) {
+	states := make(map[string]int)
+	for _, c := range e.daemon.Containers(nil, docker.NoSort) {
+		state := string(c.State)
+		if state == "" {
+			state = "unknown"
+		}
+		states[state]++
+	}
+	p.header("dry_containers", "gauge", "Containers by state.")
+	for _, state := range sortedKeys(states) {
+		p.sample("dry_containers", float64(states[state]), "state", state)
+	}
+	if images, err := e.daemon.Images(); err == nil {
+		p.header("dry_images", "gauge", "Images.")
+		p.sample("dry_images", float64(len(images)))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if volumes, err := e.daemon.VolumeList(ctx); err == nil {
+		p.header("dry_volumes", "gauge", "Volumes.")
+		p.sample("dry_volumes", float64(len(volumes)))
+	}
+}
+
+func (e *Exporter) writeEvents(p *promWriter) {
+	e.mu.Lock()
+	keys := make([]eventKey, 0, len(e.events))
+	for k := range e.events {
+		keys = append(keys, k)
+	}
+	counts := make(map[eventKey]uint64, len(e.events))
+	for k, n := range e.events {
+		counts[k] = n
+	}
+	e.mu.Unlock()
+	slices.SortFunc(keys, func(a, b eventKey) int {
+		if c := strings.Compare(a.typ, b.typ); c != 0 {
+			return c
+		}
+		return strings.Compare(a.action, b.action)
+	})
+	p.header("dry_events_total", "counter", "Docker events seen since dry started, by type and action.")
+	for _, k := range keys {
+		p.sample("dry_events_total", float64(counts[k]), "type", k.typ, "action", k.action)
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// promWriter writes metrics in the Prometheus text format.
+type promWriter struct {
+	w io.Writer
+}
+
+func (p *promWriter) header(name, typ, help string) {
+	fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes a sample of a metric with the given label names and
+// values, in pairs.
+func (p *promWriter) sample(name string, value float64, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, "%s=\"%s\"", labels[i], labelEscaper.Replace(labels[i+1]))
+		}
+		b.WriteByte('}')
+	}
+	fmt.Fprintf(p.w, "%s %g\n", b.String(), value)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
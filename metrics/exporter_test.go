@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/events"
+	"github.com/moncho/dry/docker"
+	"github.com/moncho/dry/mocks"
+)
+
+func TestExporter_WritesPrometheusText(t *testing.T) {
+	e := NewExporter(&mocks.DockerDaemonMock{})
+	stream := &statsStream{cancel: func() {}}
+	e.streams["abc123"] = stream
+	e.setStats("abc123", stream, &docker.Stats{
+		CID: "abc123", Name: "/web", CPUPercentage: 12.5, Memory: 1024,
+		NetworkRx: 2048, PidsCurrent: 7,
+	})
+	e.countEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionStart})
+	e.countEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionStart})
+	e.countEvent(events.Message{Type: events.ImageEventType, Action: events.ActionPull})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE dry_container_cpu_percent gauge\n",
+		`dry_container_cpu_percent{id="abc123",name="web"} 12.5` + "\n",
+		"# TYPE dry_container_network_receive_bytes_total counter\n",
+		`dry_container_network_receive_bytes_total{id="abc123",name="web"} 2048` + "\n",
+		`dry_container_pids{id="abc123",name="web"} 7` + "\n",
+		`dry_containers{state="unknown"} `,
+		"dry_images 5\n",
+		"dry_volumes 0\n",
+		`dry_events_total{type="container",action="start"} 2` + "\n",
+		`dry_events_total{type="image",action="pull"} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in:\n%s", want, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+}
+
+// reconnectingDaemon fails to stream events once, then streams the
+// events of each of its channels in turn.
+type reconnectingDaemon struct {
+	mocks.DockerDaemonMock
+	calls   int
+	streams []chan events.Message
+}
+
+func (d *reconnectingDaemon) Containers([]docker.ContainerFilter, docker.SortMode) []*docker.Container {
+	return nil
+}
+
+func (d *reconnectingDaemon) Events(context.Context) (<-chan events.Message, error) {
+	d.calls++
+	if d.calls == 1 || len(d.streams) == 0 {
+		return nil, errors.New("connection refused")
+	}
+	ch := d.streams[0]
+	d.streams = d.streams[1:]
+	return ch, nil
+}
+
+func TestExporter_ReconnectsEvents(t *testing.T) {
+	first, second := make(chan events.Message, 1), make(chan events.Message, 1)
+	first <- events.Message{Type: events.ContainerEventType, Action: events.ActionStart}
+	close(first)
+	second <- events.Message{Type: events.ContainerEventType, Action: events.ActionStart}
+	daemon := &reconnectingDaemon{streams: []chan events.Message{first, second}}
+	e := NewExporter(daemon)
+	e.reconnect = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+	deadline := time.After(5 * time.Second)
+	for e.eventCount(eventKey{typ: "container", action: "start"}) < 2 {
+		select {
+		case <-done:
+			t.Fatal("expected Run to keep going while the events stream reconnects")
+		case <-deadline:
+			t.Fatal("expected the events of the reconnected stream to be counted")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+}
+
+func TestExporter_DropsStatsOfReplacedStreams(t *testing.T) {
+	e := NewExporter(&mocks.DockerDaemonMock{})
+	_, cancel := context.WithCancel(context.Background())
+	old := &statsStream{cancel: cancel}
+	e.streams["abc"] = &statsStream{cancel: func() {}}
+	ch := make(chan *docker.Stats, 1)
+	ch <- &docker.Stats{CID: "abc"}
+	close(ch)
+	e.readStats("abc", old, ch)
+	if _, ok := e.stats["abc"]; ok {
+		t.Fatal("expected the stats of a replaced stream to be dropped")
+	}
+	if _, ok := e.streams["abc"]; !ok {
+		t.Fatal("expected the stream replacing it to be kept")
+	}
+}
+
+func TestPromWriter_EscapesLabelValues(t *testing.T) {
+	var b strings.Builder
+	p := promWriter{w: &b}
+	p.sample("m", 1, "name", "a\"b\\c\nd")
+	if want := `m{name="a\"b\\c\nd"} 1` + "\n"; b.String() != want {
+		t.Fatalf("expected %q, got %q", want, b.String())
+	}
+}
+
+func (e *Exporter) eventCount(k eventKey) uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.events[k]
+}
@@ -0,0 +1,364 @@
+// Package metrics keeps the stats of containers on disk, so that they
+// can be charted over hours or days and across restarts of dry.
+package metrics
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// Sample is the stats of a container at a point in time. Network and
+// block IO are the totals since the container started, as reported by
+// Docker.
+type Sample struct {
+	At         time.Time
+	CPU        float64 // percentage of CPU used
+	Memory     float64 // percentage of the memory limit used
+	MemoryUsed float64 // bytes
+	NetworkRx  float64 // bytes
+	NetworkTx  float64 // bytes
+	BlockRead  float64 // bytes
+	BlockWrite float64 // bytes
+	Pids       float64
+}
+
+// Rates returns the network and block IO per second from prev to s, zero
+// for the totals that went down, as they do when a container restarts.
+func (s Sample) Rates(prev Sample) (rx, tx, read, write float64) {
+	secs := s.At.Sub(prev.At).Seconds()
+	if secs <= 0 {
+		return 0, 0, 0, 0
+	}
+	rate := func(cur, prev float64) float64 {
+		return max(0, cur-prev) / secs
+	}
+	return rate(s.NetworkRx, prev.NetworkRx), rate(s.NetworkTx, prev.NetworkTx),
+		rate(s.BlockRead, prev.BlockRead), rate(s.BlockWrite, prev.BlockWrite)
+}
+
+// DefaultRetention is how long samples are kept when no retention is given.
+const DefaultRetention = 24 * time.Hour
+
+// historySlots is how many samples a container file holds: samples are
+// spread over the retention period, one every retention/historySlots, and
+// never more often than minInterval.
+const (
+	historySlots = 8640
+	minInterval  = 10 * time.Second
+)
+
+// Layout of a container file: a header with a magic string, the number of
+// slots and the number of samples ever written, followed by the slots,
+// each one a sample written as the nanoseconds of its time and its values.
+// The magic string changes with the layout: DRYMETR1 files held samples of
+// seven values.
+const (
+	fileMagic  = "DRYMETR2"
+	headerSize = len(fileMagic) + 8 + 8
+	sampleSize = 8 + 8*8
+	fileSuffix = ".metrics"
+)
+
+// DefaultDir returns the directory samples are kept in when none is
+// given: $XDG_DATA_HOME/dry/metrics, or ~/.local/share/dry/metrics when
+// XDG_DATA_HOME is not set.
+func DefaultDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "dry", "metrics")
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "share", "dry", "metrics")
+}
+
+// History keeps the samples of each container in a file of its own, used
+// as a ring buffer: once full, the oldest sample is overwritten by the
+// newest. Samples recorded more often than its interval are averaged. It
+// is safe for concurrent use.
+type History struct {
+	dir       string
+	retention time.Duration
+	interval  time.Duration
+	slots     uint64 // slots of the files created
+
+	mu      sync.Mutex
+	pending map[string]*pendingSample
+	loaded  map[string][]Sample // samples read from disk, and recorded since
+}
+
+// pendingSample accumulates the samples of a container until they are
+// written as one.
+type pendingSample struct {
+	since  time.Time
+	n      int
+	cpu    float64
+	memory float64
+	last   Sample
+}
+
+// NewHistory opens the history kept under the given directory, creating
+// it if needed, keeping samples for the given retention period. Files of
+// containers without a sample in that period are removed.
+func NewHistory(dir string, retention time.Duration) (*History, error) {
+	if dir == "" {
+		return nil, errors.New("no metrics directory")
+	}
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	h := &History{
+		dir:       dir,
+		retention: retention,
+		interval:  max(minInterval, retention/historySlots),
+		slots:     historySlots,
+		pending:   make(map[string]*pendingSample),
+		loaded:    make(map[string][]Sample),
+	}
+	h.prune(time.Now())
+	return h, nil
+}
+
+// Retention returns how long samples are kept.
+func (h *History) Retention() time.Duration {
+	return h.retention
+}
+
+// Record adds a sample of the given container. Samples are averaged and
+// written once per interval.
+func (h *History) Record(id string, s Sample) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	p := h.pending[id]
+	if p == nil {
+		p = &pendingSample{since: s.At}
+		h.pending[id] = p
+	}
+	p.n++
+	p.cpu += s.CPU
+	p.memory += s.Memory
+	p.last = s
+	if s.At.Sub(p.since) < h.interval {
+		return nil
+	}
+	delete(h.pending, id)
+	avg := p.last
+	avg.CPU = p.cpu / float64(p.n)
+	avg.Memory = p.memory / float64(p.n)
+	if samples, ok := h.loaded[id]; ok {
+		h.loaded[id] = h.trim(append(samples, avg), avg.At)
+	}
+	return h.write(id, avg)
+}
+
+// Samples returns the samples of the given container taken since the
+// given time, oldest first.
+func (h *History) Samples(id string, since time.Time) ([]Sample, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	samples, ok := h.loaded[id]
+	if !ok {
+		var err error
+		if samples, err = h.read(id); err != nil {
+			return nil, err
+		}
+		samples = h.trim(samples, time.Now())
+		h.loaded[id] = samples
+	}
+	start := len(samples)
+	for start > 0 && !samples[start-1].At.Before(since) {
+		start--
+	}
+	return append([]Sample(nil), samples[start:]...), nil
+}
+
+// Forget drops the samples of the given container kept in memory, its
+// file staying on disk.
+func (h *History) Forget(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.loaded, id)
+	delete(h.pending, id)
+}
+
+// trim drops the samples older than the retention period.
+func (h *History) trim(samples []Sample, now time.Time) []Sample {
+	cutoff := now.Add(-h.retention)
+	start := 0
+	for start < len(samples) && samples[start].At.Before(cutoff) {
+		start++
+	}
+	return samples[start:]
+}
+
+func (h *History) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("invalid container id %q", id)
+	}
+	return filepath.Join(h.dir, id+fileSuffix), nil
+}
+
+// write puts a sample in the next slot of the file of a container.
+func (h *History) write(id string, s Sample) error {
+	path, err := h.path(id)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	slots, written, err := readHeader(f)
+	if errors.Is(err, io.EOF) {
+		slots, written, err = h.slots, 0, nil
+	}
+	if errors.Is(err, errFileLayout) {
+		// Samples of another layout cannot be kept, the file starts over.
+		if err = f.Truncate(0); err == nil {
+			slots, written = h.slots, 0
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if _, err := f.WriteAt(encodeSample(s), int64(headerSize)+int64(written%slots)*sampleSize); err != nil {
+		return err
+	}
+	return writeHeader(f, slots, written+1)
+}
+
+// read returns the samples in the file of a container, oldest first.
+func (h *History) read(id string) ([]Sample, error) {
+	path, err := h.path(id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	slots, written, err := readHeader(f)
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	n := min(written, slots)
+	buf := make([]byte, n*sampleSize)
+	if _, err := f.ReadAt(buf, int64(headerSize)); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	samples := make([]Sample, 0, n)
+	first := uint64(0)
+	if written > slots {
+		first = written % slots
+	}
+	for i := range n {
+		slot := (first + i) % slots
+		samples = append(samples, decodeSample(buf[slot*sampleSize:(slot+1)*sampleSize]))
+	}
+	return samples, nil
+}
+
+// prune removes the files not written to in the retention period.
+func (h *History) prune(now time.Time) {
+	entries, err := os.ReadDir(h.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileSuffix) {
+			continue
+		}
+		if info, err := e.Info(); err == nil && now.Sub(info.ModTime()) > h.retention {
+			_ = os.Remove(filepath.Join(h.dir, e.Name()))
+		}
+	}
+}
+
+// errFileLayout is returned for files not written with the layout of
+// this version.
+var errFileLayout = errors.New("not a metrics file of this version")
+
+// readHeader reads the header of a file, checking that the file holds the
+// samples it tells of.
+func readHeader(f *os.File) (slots, written uint64, err error) {
+	buf := make([]byte, headerSize)
+	if _, err := f.ReadAt(buf, 0); err != nil {
+		return 0, 0, err
+	}
+	if string(buf[:len(fileMagic)]) != fileMagic {
+		return 0, 0, errFileLayout
+	}
+	slots = binary.LittleEndian.Uint64(buf[len(fileMagic):])
+	written = binary.LittleEndian.Uint64(buf[len(fileMagic)+8:])
+	if slots == 0 {
+		return 0, 0, errors.New("metrics file without slots")
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	// A sample is written before the header counting it, so a file may
+	// hold one more sample than the header tells of.
+	body := info.Size() - int64(headerSize)
+	if n := body / sampleSize; body%sampleSize != 0 || uint64(n) < min(written, slots) || uint64(n) > slots {
+		return 0, 0, errFileLayout
+	}
+	return slots, written, nil
+}
+
+func writeHeader(f *os.File, slots, written uint64) error {
+	buf := make([]byte, headerSize)
+	copy(buf, fileMagic)
+	binary.LittleEndian.PutUint64(buf[len(fileMagic):], slots)
+	binary.LittleEndian.PutUint64(buf[len(fileMagic)+8:], written)
+	_, err := f.WriteAt(buf, 0)
+	return err
+}
+
+func encodeSample(s Sample) []byte {
+	buf := make([]byte, sampleSize)
+	binary.LittleEndian.PutUint64(buf, uint64(s.At.UnixNano()))
+	for i, v := range []float64{s.CPU, s.Memory, s.MemoryUsed, s.NetworkRx, s.NetworkTx, s.BlockRead, s.BlockWrite, s.Pids} {
+		binary.LittleEndian.PutUint64(buf[8+i*8:], math.Float64bits(v))
+	}
+	return buf
+}
+
+func decodeSample(buf []byte) Sample {
+	value := func(i int) float64 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(buf[8+i*8:]))
+	}
+	return Sample{
+		At:         time.Unix(0, int64(binary.LittleEndian.Uint64(buf))),
+		CPU:        value(0),
+		Memory:     value(1),
+		MemoryUsed: value(2),
+		NetworkRx:  value(3),
+		NetworkTx:  value(4),
+		BlockRead:  value(5),
+		BlockWrite: value(6),
+		Pids:       value(7),
+	}
+}
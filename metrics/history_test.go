@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory_AveragesSamplesPerInterval(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Minute)
+	for i, cpu := range []float64{10, 20, 30, 40} {
+		at := start.Add(time.Duration(i) * 5 * time.Second)
+		if err := h.Record("abc", Sample{At: at, CPU: cpu, NetworkRx: float64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	samples, err := h.Samples("abc", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Samples 0 to 2 span the 10s interval, sample 3 starts the next one.
+	if len(samples) != 1 {
+		t.Fatalf("expected 1 sample, got %+v", samples)
+	}
+	if samples[0].CPU != 20 || samples[0].NetworkRx != 2 {
+		t.Fatalf("expected the mean CPU and the last counters, got %+v", samples[0])
+	}
+}
+
+func TestHistory_SurvivesReopening(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().Add(-30 * time.Minute).Truncate(time.Second)
+	// Every other sample closes an interval: samples 1, 3, 5 and 7 are
+	// written.
+	for i := range 9 {
+		s := Sample{At: at.Add(time.Duration(i) * minInterval), CPU: float64(i), BlockWrite: 1024}
+		if err := h.Record("abc", s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reopened, err := NewHistory(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, err := reopened.Samples("abc", at.Add(2*minInterval))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 {
+		t.Fatalf("expected the 3 samples written since, got %+v", samples)
+	}
+	if !samples[0].At.Equal(at.Add(3*minInterval)) || samples[2].BlockWrite != 1024 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+}
+
+func TestHistory_OverwritesTheOldestSamples(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.slots = 2
+	at := time.Now().Add(-10 * time.Minute)
+	for i := range 7 {
+		s := Sample{At: at.Add(time.Duration(i) * minInterval), CPU: float64(i)}
+		if err := h.Record("abc", s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	samples, err := h.read("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cpu []float64
+	for _, s := range samples {
+		cpu = append(cpu, s.CPU)
+	}
+	// Samples 0 and 1, 2 and 3, 4 and 5 are averaged into 2 slots, sample
+	// 6 waiting for the end of its interval.
+	if len(cpu) != 2 || cpu[0] != 2.5 || cpu[1] != 4.5 {
+		t.Fatalf("expected the newest samples, oldest first, got %v", cpu)
+	}
+}
+
+func TestHistory_RefusesPathsAsIDs(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.write("../abc", Sample{}); err == nil {
+		t.Fatal("expected an ID with a path to be refused")
+	}
+}
+
+func TestSample_Rates(t *testing.T) {
+	at := time.Unix(100, 0)
+	prev := Sample{At: at, NetworkRx: 1000, NetworkTx: 500, BlockRead: 0, BlockWrite: 4096}
+	s := Sample{At: at.Add(2 * time.Second), NetworkRx: 3000, NetworkTx: 500, BlockRead: 1024, BlockWrite: 0}
+	rx, tx, read, write := s.Rates(prev)
+	if rx != 1000 || tx != 0 || read != 512 || write != 0 {
+		t.Fatalf("unexpected rates %v %v %v %v", rx, tx, read, write)
+	}
+	if rx, _, _, _ := prev.Rates(s); rx != 0 {
+		t.Fatalf("expected no rate back in time, got %v", rx)
+	}
+}
+
+func TestHistory_RejectsFilesOfAnotherLayout(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A file of the previous layout: its magic string and one sample of
+	// seven values.
+	old := make([]byte, headerSize+8+7*8)
+	copy(old, "DRYMETR1")
+	binary.LittleEndian.PutUint64(old[len(fileMagic):], historySlots)
+	binary.LittleEndian.PutUint64(old[len(fileMagic)+8:], 1)
+	path := filepath.Join(dir, "abc"+fileSuffix)
+	if err := os.WriteFile(path, old, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Samples("abc", time.Time{}); err == nil {
+		t.Fatal("expected a file of the previous layout to be rejected")
+	}
+
+	// Same magic string, but a size not fitting whole samples.
+	copy(old, fileMagic)
+	if err := os.WriteFile(path, old, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	h.Forget("abc")
+	if _, err := h.Samples("abc", time.Time{}); err == nil {
+		t.Fatal("expected a file of the wrong size to be rejected")
+	}
+
+	at := time.Now().Add(-time.Minute)
+	for _, s := range []Sample{{At: at, CPU: 1}, {At: at.Add(minInterval), CPU: 2}} {
+		if err := h.Record("abc", s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.Forget("abc")
+	samples, err := h.Samples("abc", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].CPU != 1.5 {
+		t.Fatalf("expected the file to start over, got %+v", samples)
+	}
+}
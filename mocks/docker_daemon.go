@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"os"
 	"strconv"
 
 	"github.com/moby/moby/api/types/container"
@@ -88,6 +89,11 @@ func (_m *DockerDaemonMock) Containers(filters []drydocker.ContainerFilter, _ dr
 	return containers
 }
 
+// Close mock
+func (_m *DockerDaemonMock) Close() error {
+	return nil
+}
+
 // DiskUsage mock
 func (_m *DockerDaemonMock) DiskUsage() (client.DiskUsageResult, error) {
 	return client.DiskUsageResult{}, nil
@@ -210,7 +216,7 @@ func (_m *DockerDaemonMock) Kill(id string) error {
 }
 
 // Logs provides a mock function with given fields: id
-func (_m *DockerDaemonMock) Logs(id, since string, ts bool) (io.ReadCloser, error) {
+func (_m *DockerDaemonMock) Logs(id string, opts drydocker.LogOptions) (io.ReadCloser, error) {
 	return nil, nil
 }
 
@@ -279,6 +285,16 @@ func (_m *DockerDaemonMock) Prune() (*drydocker.PruneReport, error) {
 	return nil, nil
 }
 
+// PauseContainer provides a mock function with given fields: id
+func (_m *DockerDaemonMock) PauseContainer(id string) error {
+	return nil
+}
+
+// RenameContainer provides a mock function with given fields: id, name
+func (_m *DockerDaemonMock) RenameContainer(id, name string) error {
+	return nil
+}
+
 // RestartContainer provides a mock function with given fields: id
 func (_m *DockerDaemonMock) RestartContainer(id string) error {
 	return nil
@@ -340,8 +356,13 @@ func (_m *DockerDaemonMock) ResolveService(id string) (string, error) {
 }
 
 // RunImage mock
-func (_m *DockerDaemonMock) RunImage(image image.Summary, command string) error {
-	return nil
+func (_m *DockerDaemonMock) RunImage(image image.Summary, opts drydocker.RunOptions) (string, error) {
+	return "", nil
+}
+
+// RunImageAttached mock
+func (_m *DockerDaemonMock) RunImageAttached(image image.Summary, opts drydocker.RunOptions) (*drydocker.AttachSession, error) {
+	return nil, nil
 }
 
 // Service mock
@@ -389,6 +410,16 @@ func (_m *DockerDaemonMock) StopContainer(id string) error {
 	return nil
 }
 
+// UnpauseContainer provides a mock function with given fields: id
+func (_m *DockerDaemonMock) UnpauseContainer(id string) error {
+	return nil
+}
+
+// UpdateContainerResources provides a mock function with given fields: id, opts
+func (_m *DockerDaemonMock) UpdateContainerResources(id string, opts drydocker.ResourceOptions) error {
+	return nil
+}
+
 // Sort provides a mock function with given fields: sortMode
 func (_m *DockerDaemonMock) Sort(sortMode drydocker.SortMode) {
 }
@@ -515,3 +546,60 @@ func (_m *DockerDaemonMock) ComposeProjectRestart(project string) (drydocker.Com
 func (_m *DockerDaemonMock) ComposeProjectRemove(project string) (drydocker.ComposeServiceActionReport, error) {
 	return drydocker.ComposeServiceActionReport{}, nil
 }
+
+// ContainerChanges mock
+func (_m *DockerDaemonMock) ContainerChanges(id string) ([]container.FilesystemChange, error) {
+	return []container.FilesystemChange{
+		{Kind: container.ChangeModify, Path: "/etc"},
+		{Kind: container.ChangeAdd, Path: "/etc/app.conf"},
+		{Kind: container.ChangeDelete, Path: "/tmp/cache"},
+	}, nil
+}
+
+// ContainerFileTree mock
+func (_m *DockerDaemonMock) ContainerFileTree(ctx context.Context, id string) (*drydocker.FileTree, error) {
+	return drydocker.NewFileTree(
+		drydocker.FileEntry{Path: "/etc/hostname", Size: 13, Mode: 0o644},
+		drydocker.FileEntry{Path: "/etc/app.conf", Size: 42, Mode: 0o644},
+		drydocker.FileEntry{Path: "/bin/sh", Mode: os.ModeSymlink | 0o777, LinkTarget: "busybox"},
+		drydocker.FileEntry{Path: "/tmp", Mode: os.ModeDir | 0o1777},
+	), nil
+}
+
+// CopyFromContainer mock
+func (_m *DockerDaemonMock) CopyFromContainer(ctx context.Context, id, src, dest string, progress func(int64)) error {
+	return nil
+}
+
+// CopyToContainer mock
+func (_m *DockerDaemonMock) CopyToContainer(ctx context.Context, id, src, dest string, progress func(int64)) error {
+	return nil
+}
+
+// ReadContainerFile mock
+func (_m *DockerDaemonMock) ReadContainerFile(id, path string, limit int64) ([]byte, error) {
+	return []byte("dry-container\n"), nil
+}
+
+// CommitContainer mock
+func (_m *DockerDaemonMock) CommitContainer(id string, opts drydocker.CommitOptions) (string, error) {
+	return "sha256:0123456789abcdef", nil
+}
+
+// ExportContainer mock
+func (_m *DockerDaemonMock) ExportContainer(ctx context.Context, id, path string, progress func(int64)) error {
+	progress(1024)
+	return nil
+}
+
+// ImageLoad mock
+func (_m *DockerDaemonMock) ImageLoad(ctx context.Context, path string, progress func(int64)) ([]string, error) {
+	progress(1024)
+	return []string{"dry/dry:1"}, nil
+}
+
+// ImageSave mock
+func (_m *DockerDaemonMock) ImageSave(ctx context.Context, refs []string, path string, progress func(int64)) error {
+	progress(1024)
+	return nil
+}
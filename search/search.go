@@ -3,6 +3,7 @@ package search
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -19,11 +20,62 @@ type Result struct {
 // * the number of hits (lines)
 // * the line index
 func NewSearch(text [][]rune, pattern string) (*Result, error) {
+	return search(text, pattern, func(line string) bool {
+		return strings.Contains(line, pattern)
+	})
+}
+
+// Options tell how a pattern is matched.
+type Options struct {
+	// Regexp makes the pattern a regular expression instead of plain text.
+	Regexp bool
+	// IgnoreCase matches letters regardless of their case.
+	IgnoreCase bool
+}
+
+// Compile returns the regular expression matching the given pattern.
+func Compile(pattern string, opts Options) (*regexp.Regexp, error) {
+	expr := pattern
+	if !opts.Regexp {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if opts.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// NewPatternSearch works as NewSearch, matching lines with a pattern
+// compiled with the given options.
+func NewPatternSearch(text [][]rune, pattern string, opts Options) (*Result, error) {
+	re, err := Compile(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	return search(text, pattern, re.MatchString)
+}
+
+// Spans returns the byte ranges of the non-empty matches of re in s.
+func Spans(re *regexp.Regexp, s string) [][]int {
+	locs := re.FindAllStringIndex(s, -1)
+	spans := locs[:0]
+	for _, loc := range locs {
+		if loc[1] > loc[0] {
+			spans = append(spans, loc)
+		}
+	}
+	return spans
+}
+
+func search(text [][]rune, pattern string, match func(string) bool) (*Result, error) {
 	if text != nil {
 		sr := &Result{Pattern: pattern, index: -1}
 		for i, l := range text {
-			line := string(l)
-			if strings.Contains(line, pattern) {
+			if match(string(l)) {
 				sr.Hits++
 				sr.Lines = append(sr.Lines, i)
 			}
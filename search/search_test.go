@@ -81,6 +81,41 @@ func TestResultIteration(t *testing.T) {
 	}
 }
 
+func TestPatternSearch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		opts    Options
+		lines   []int
+	}{
+		{"nope", Options{}, []int{0, 1, 6, 7}},
+		{"nope", Options{IgnoreCase: true}, []int{0, 1, 5, 6, 7}},
+		{`^line ?\d+$`, Options{Regexp: true}, []int{3, 4, 9}},
+		{`^line ?\d+$`, Options{}, nil},
+		{"(", Options{}, nil},
+	}
+	for _, tt := range tests {
+		rs, err := NewPatternSearch(testText(), tt.pattern, tt.opts)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.pattern, err)
+		}
+		if !reflect.DeepEqual(tt.lines, rs.Lines) {
+			t.Errorf("%q %+v: expected lines %v, got %v", tt.pattern, tt.opts, tt.lines, rs.Lines)
+		}
+	}
+	if _, err := NewPatternSearch(testText(), "(", Options{Regexp: true}); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+}
+
+func TestSpans(t *testing.T) {
+	re, _ := Compile("l*", Options{Regexp: true})
+	got := Spans(re, "hello world")
+	want := [][]int{{2, 4}, {9, 10}}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("expected non-empty spans %v, got %v", want, got)
+	}
+}
+
 func testText() [][]rune {
 	return [][]rune{
 		[]rune("one 1 nope"),